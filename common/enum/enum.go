@@ -7,8 +7,45 @@ type PointOption int
 // MergeOption 空間IDマージオプション用の型
 type MergeOption int
 
+// CoveringMode 精度変換、マージの近似方法用の型
+type CoveringMode int
+
+// BoundaryPolicy 拡張空間IDの移動時の範囲外の扱い用の型
+type BoundaryPolicy int
+
+// ElementShape 形態素演算の構造要素の形状用の型
+type ElementShape int
+
 // 点群APIで入力可能な座標取得のオプション
 const (
 	Vertex PointOption = iota // 空間IDの頂点座標を取得(0)
 	Center                    // 空間IDの中心座標を取得(1)
 )
+
+// 空間IDマージAPIで入力可能なマージのオプション
+const (
+	ExactMerge     MergeOption = iota // 稠密な場合のみマージし、稠密でない場合はマージ元の空間IDを返却(0)
+	FillRatioMerge                    // 充填率が閾値以上の場合にマージ(過大近似)(1)
+	DenseOnlyMerge                    // 稠密な場合のみマージし、稠密でない場合はマージ元の空間IDを除去(過小近似)(2)
+)
+
+// 精度変換APIとマージAPIで入力可能な近似方法
+const (
+	OuterCovering CoveringMode = iota // 入力の空間を包含する拡張空間IDを返却(過大近似)。マージAPIでは充填率の閾値を最小とした FillRatioMerge と同じ(0)
+	InnerCovering                     // 入力の空間に完全に満たされる拡張空間IDのみ返却(過小近似)。マージAPIでは DenseOnlyMerge と同じ(1)
+)
+
+// 拡張空間ID移動APIで入力可能な範囲外の扱い
+//
+// 緯度方向を周回させる(極を越えると反対の極に移る)扱いは提供しない。
+const (
+	ClampBoundary BoundaryPolicy = iota // 経度方向は周回し、緯度方向は範囲の端(極)で停止(0)
+	DropBoundary                        // 経度方向は周回し、緯度方向の範囲外は除外(1)
+)
+
+// 形態素演算APIで入力可能な構造要素の形状
+const (
+	BoxElement    ElementShape = iota // 水平方向、垂直方向の層目で指定する直方体(0)
+	CrossElement                      // 面で接する6個の拡張空間IDと中心(1)
+	SphereElement                     // 中心からの距離で指定する球(2)
+)
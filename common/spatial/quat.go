@@ -12,56 +12,59 @@ type Quat struct {
 	W, X, Y, Z float64
 }
 
-// 四元数の計算結果確認用メソッド
-// 実装では使用しないためコメントアウト
-// // Mul 四元数の積
-// //
-// // 四元数の積を算出して返却する。
-// //
-// // 引数：
-// //  b： 掛け合わせる四元数
-// //
-// // 戻り値：
-// //  四元数の積
-// func (a Quat) Mul(b Quat) Quat {
-// 	return Quat{
-// 		(b.W * a.W) - (b.X * a.X) - (b.Y * a.Y) - (b.Z * a.Z),
-// 		(b.X * a.W) + (b.W * a.X) - (b.Z * a.Y) + (b.Y * a.Z),
-// 		(b.Y * a.W) + (b.Z * a.X) + (b.W * a.Y) - (b.X * a.Z),
-// 		(b.Z * a.W) - (b.Y * a.X) + (b.X * a.Y) + (b.W * a.Z),
-// 	}
-// }
+// Mul 四元数の積
+//
+// 四元数の積を算出して返却する。
+//
+// 引数：
+//
+//	b： 掛け合わせる四元数
+//
+// 戻り値：
+//
+//	四元数の積
+func (a Quat) Mul(b Quat) Quat {
+	return Quat{
+		(b.W * a.W) - (b.X * a.X) - (b.Y * a.Y) - (b.Z * a.Z),
+		(b.X * a.W) + (b.W * a.X) - (b.Z * a.Y) + (b.Y * a.Z),
+		(b.Y * a.W) + (b.Z * a.X) + (b.W * a.Y) - (b.X * a.Z),
+		(b.Z * a.W) - (b.Y * a.X) + (b.X * a.Y) + (b.W * a.Z),
+	}
+}
 
-// // Conjugate 四元数の共役
-// //
-// // 共役四元数を算出して返却する。
-// //
-// // 戻り値：
-// //  共役四元数
-// func (a Quat) Conjugate() Quat {
-// 	return Quat{
-// 		a.W,
-// 		-a.X,
-// 		-a.Y,
-// 		-a.Z,
-// 	}
-// }
+// Conjugate 四元数の共役
+//
+// 共役四元数を算出して返却する。
+//
+// 戻り値：
+//
+//	共役四元数
+func (a Quat) Conjugate() Quat {
+	return Quat{
+		a.W,
+		-a.X,
+		-a.Y,
+		-a.Z,
+	}
+}
 
-// // TransformVec3 四元数でのベクトル変換
-// //
-// // 入力のベクトルを四元数で変換した値を返却する。
-// //
-// // 引数：
-// //  v： 入力ベクトル
-// //
-// // 戻り値：
-// //  変換後のベクトル
-// func (a Quat) TransformVec3(v Vector3) Vector3 {
-// 	vecQuat := Quat{0.0, v.X, v.Y, v.Z}
-// 	conjugate := a.Conjugate()
-// 	vecQuat = conjugate.Mul(vecQuat).Mul(a)
-// 	return Vector3{vecQuat.X, vecQuat.Y, vecQuat.Z}
-// }
+// TransformVec3 四元数でのベクトル変換
+//
+// 入力のベクトルを四元数で変換した値を返却する。
+//
+// 引数：
+//
+//	v： 入力ベクトル
+//
+// 戻り値：
+//
+//	変換後のベクトル
+func (a Quat) TransformVec3(v Vector3) Vector3 {
+	vecQuat := Quat{0.0, v.X, v.Y, v.Z}
+	conjugate := a.Conjugate()
+	vecQuat = conjugate.Mul(vecQuat).Mul(a)
+	return Vector3{vecQuat.X, vecQuat.Y, vecQuat.Z}
+}
 
 // RotateBetweenVector 2ベクトル間の四元数算出処理
 //
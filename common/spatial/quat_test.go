@@ -1,6 +1,7 @@
 package spatial
 
 import (
+	"math"
 	"testing"
 
 	"github.com/trajectoryjp/spatial_id_go/v4/common"
@@ -210,3 +211,29 @@ func TestQuatFromAxisAngle01(t *testing.T) {
 	// - TestRotateBetweenVector02
 	// - TestRotateBetweenVector03
 }
+
+// TestQuatTransformVec301 正常系動作確認
+//
+// 試験詳細：
+//   - 試験データ
+//     回転軸ベクトル：(0,0,2)
+//     回転角度(ラジアン)： math.Pi/2
+//     入力ベクトル：(1,0,3)
+//
+// + 確認内容
+//   - 回転軸の右ねじの向きに回転したベクトル(0,1,3)が返ってくること
+func TestQuatTransformVec301(t *testing.T) {
+	quat := QuatFromAxisAngle(Vector3{0, 0, 2}, math.Pi/2)
+
+	// テスト対象呼び出し
+	resultVal := quat.TransformVec3(Vector3{1, 0, 3})
+
+	expectVal := Vector3{0, 1, 3}
+	if !common.AlmostEqual(resultVal.X, expectVal.X, consts.Minima) ||
+		!common.AlmostEqual(resultVal.Y, expectVal.Y, consts.Minima) ||
+		!common.AlmostEqual(resultVal.Z, expectVal.Z, consts.Minima) {
+		t.Errorf("ベクトル - 期待値%v, 取得値%v", expectVal, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
@@ -0,0 +1,327 @@
+package detector
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl64"
+	geodesy "github.com/trajectoryjp/geodesy_go/coordinates"
+	"github.com/trajectoryjp/multidimensional-radix-tree/src/tree"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/consts"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+	"github.com/trajectoryjp/spatial_id_go/v4/transform"
+)
+
+// 見通し判定で扱う高度の範囲(単位:m)。radix treeの高さ方向は -altitudeRange ～ altitudeRange とする。
+const altitudeRange = 1 << (consts.ZOriginValue - 1)
+
+// lineOfSight 見通し判定用の線分と障害物
+//
+// 座標は高さ、経度方向、緯度方向の順に、radix treeの空間全体を0～1とした正規化座標で扱う。
+type lineOfSight struct {
+	tree      tree.TreeInterface            // 障害物を格納したradix tree
+	obstacles map[[2]int64]map[[3]int64]int // 水平方向精度、垂直方向精度の組ごとの障害物(x成分、y成分、高さ成分)の入力順
+	maxZoom   int64                         // 探索する最大の精度
+	origin    mgl64.Vec3                    // 始点の正規化座標
+	direction mgl64.Vec3                    // 始点から終点への正規化座標の差
+}
+
+// lineOfSightCell 見通し判定の探索対象の範囲
+type lineOfSightCell struct {
+	zoom    int64       // 精度
+	indexes tree.Indexs // 高さ、経度方向、緯度方向のインデックス
+	enter   float64     // 線分が範囲に入る位置(始点0、終点1)
+}
+
+// CheckLineOfSightOnExtendedSpatialIds 拡張空間IDによる見通しの判定関数
+//
+// 始点と終点を結ぶ線分が障害物の拡張空間IDを通過するか判定する。
+// 線分は経度、緯度をWebメルカトル図法で投影した平面と高さの空間における直線とする。
+//
+// 障害物を格納したradix treeで線分の通過する範囲を精度の粗い順に絞り込み、
+// 線分上の全ての拡張空間IDを求めることなく、始点に最も近い障害物を探索する。
+// radix treeには障害物を水平方向精度と垂直方向精度の小さい方の精度で格納する。
+//
+// 引数：
+//
+//	start      ：始点
+//	end        ：終点
+//	obstacleIDs：障害物の拡張空間IDのスライス。精度が混在していてもよい。
+//
+// 戻り値：
+//
+//	見通しの有無。線分が障害物を通過しない場合true。
+//	線分が最初に通過する障害物の拡張空間ID。見通しがある場合は空文字。
+//	始点から線分が最初に障害物に入る位置までの距離(単位:m)。見通しがある場合は0。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのx成分、y成分が精度の範囲外の場合。
+//	 高度範囲外                ：始点、終点、障害物の高さが -16,777,216m以上 ～ 16,777,216m未満の範囲外の場合。
+func CheckLineOfSightOnExtendedSpatialIds(start, end *object.Point, obstacleIDs []string) (bool, string, float64, error) {
+	origin, err := getNormalizedCoordinate(start)
+	if err != nil {
+		return false, "", 0, err
+	}
+	target, err := getNormalizedCoordinate(end)
+	if err != nil {
+		return false, "", 0, err
+	}
+
+	sight := &lineOfSight{
+		tree:      tree.CreateTree(tree.Create3DTable()),
+		obstacles: map[[2]int64]map[[3]int64]int{},
+		maxZoom:   1,
+		origin:    origin,
+		direction: target.Sub(origin),
+	}
+	for i, obstacleID := range obstacleIDs {
+		if err := sight.appendObstacle(obstacleID, i); err != nil {
+			return false, "", 0, fmt.Errorf("%w @obstacleIDs[%v]", err, i)
+		}
+	}
+
+	root := lineOfSightCell{indexes: tree.Indexs{0, 0, 0}}
+	if enter, ok := sight.enterCell(root.zoom, root.indexes); ok {
+		root.enter = enter
+		if index, enter, ok := sight.search(root); ok {
+			return false, obstacleIDs[index], sight.distance(start, enter), nil
+		}
+	}
+	return true, "", 0, nil
+}
+
+// getNormalizedCoordinate 正規化座標の取得
+//
+// 引数：
+//
+//	point：座標
+//
+// 戻り値：
+//
+//	高さ、経度方向、緯度方向の正規化座標
+//
+// 戻り値(エラー)：
+//
+//	高さが範囲外の場合エラーを返却する。
+func getNormalizedCoordinate(point *object.Point) (mgl64.Vec3, error) {
+	if point.Alt() < -altitudeRange || point.Alt() >= altitudeRange {
+		return mgl64.Vec3{}, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("altitude is out of range: %v", point.Alt()))
+	}
+
+	lat := point.Lat() * math.Pi / 180
+	return mgl64.Vec3{
+		(point.Alt() + altitudeRange) / (2 * altitudeRange),
+		(point.Lon() + 180) / 360,
+		(1 - math.Asinh(math.Tan(lat))/math.Pi) / 2,
+	}, nil
+}
+
+// appendObstacle 障害物の追加
+//
+// 引数：
+//
+//	obstacleID：障害物の拡張空間ID
+//	order     ：障害物の入力順
+//
+// 戻り値(エラー)：
+//
+//	拡張空間IDが不正な場合エラーを返却する。
+func (l *lineOfSight) appendObstacle(obstacleID string, order int) error {
+	extendedSpatialID, zoom, indexes, err := getTreeIndexesOnExtendedSpatialId(obstacleID)
+	if err != nil {
+		return err
+	}
+	l.tree.Append(indexes, tree.ZoomSetLevel(zoom), obstacleID)
+
+	hZoom, vZoom := extendedSpatialID.HZoom(), extendedSpatialID.VZoom()
+	x, y, z := extendedSpatialID.X(), extendedSpatialID.Y(), extendedSpatialID.Z()
+	zoomSet := [2]int64{hZoom, vZoom}
+	if _, ok := l.obstacles[zoomSet]; !ok {
+		l.obstacles[zoomSet] = map[[3]int64]int{}
+	}
+	if _, ok := l.obstacles[zoomSet][[3]int64{x, y, z}]; !ok {
+		l.obstacles[zoomSet][[3]int64{x, y, z}] = order
+	}
+	l.maxZoom = max(l.maxZoom, hZoom, vZoom)
+	return nil
+}
+
+// getTreeIndexesOnExtendedSpatialId 拡張空間IDを格納するradix treeの範囲の取得
+//
+// radix treeには水平方向精度と垂直方向精度の小さい方の精度で格納する。
+//
+// 引数：
+//
+//	extendedSpatialID：拡張空間ID
+//
+// 戻り値：
+//
+//	拡張空間IDオブジェクト
+//	範囲の精度
+//	範囲の高さ、経度方向、緯度方向のインデックス
+//
+// 戻り値(エラー)：
+//
+//	拡張空間IDが不正な場合エラーを返却する。
+func getTreeIndexesOnExtendedSpatialId(extendedSpatialID string) (*object.ExtendedSpatialID, int64, tree.Indexs, error) {
+	id, err := object.NewExtendedSpatialID(extendedSpatialID)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	hZoom, vZoom := id.HZoom(), id.VZoom()
+	if hZoom < 0 || hZoom > consts.MaxTileXYZZoom || vZoom < 0 || vZoom > consts.MaxTileXYZZoom {
+		return nil, 0, nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("zoom is out of range: %v", extendedSpatialID))
+	}
+	x, y, z := id.X(), id.Y(), id.Z()
+	if x < 0 || x >= int64(1)<<hZoom || y < 0 || y >= int64(1)<<hZoom {
+		return nil, 0, nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("index out of range: %v", extendedSpatialID))
+	}
+
+	// 垂直方向精度以下の精度では高さ方向のインデックスは1個となる
+	zoom := min(hZoom, vZoom)
+	altitudeKey, _, err := transform.ConvertZToMinMaxAltitudekey(z, vZoom, zoom, consts.ZOriginValue, consts.ZBaseOffsetForNegativeFIndex)
+	if err != nil || altitudeKey < 0 {
+		return nil, 0, nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("input f-index %v is out of altitude range: %v", z, extendedSpatialID))
+	}
+	return id, zoom, tree.Indexs{altitudeKey, x >> (hZoom - zoom), y >> (hZoom - zoom)}, nil
+}
+
+// search 障害物の探索
+//
+// 線分が範囲に入る位置の順に子の範囲を探索するため、最初に見つかった障害物が始点に最も近い。
+//
+// 引数：
+//
+//	cell：線分が通過する範囲
+//
+// 戻り値：
+//
+//	障害物の入力順
+//	線分が障害物に入る位置(始点0、終点1)
+//	障害物が見つかった場合true
+func (l *lineOfSight) search(cell lineOfSightCell) (int, float64, bool) {
+	if !l.tree.IsOverlap(cell.indexes, tree.ZoomSetLevel(cell.zoom)) {
+		return 0, 0, false
+	}
+	if index, ok := l.findObstacle(cell); ok {
+		return index, cell.enter, true
+	}
+	if cell.zoom >= l.maxZoom {
+		return 0, 0, false
+	}
+
+	children := []lineOfSightCell{}
+	for i := int64(0); i < 8; i++ {
+		indexes := tree.Indexs{cell.indexes[0]*2 + i&1, cell.indexes[1]*2 + i>>1&1, cell.indexes[2]*2 + i>>2&1}
+		if enter, ok := l.enterCell(cell.zoom+1, indexes); ok {
+			children = append(children, lineOfSightCell{zoom: cell.zoom + 1, indexes: indexes, enter: enter})
+		}
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].enter < children[j].enter
+	})
+
+	for _, child := range children {
+		if index, enter, ok := l.search(child); ok {
+			return index, enter, true
+		}
+	}
+	return 0, 0, false
+}
+
+// findObstacle 範囲を包含する障害物の取得
+//
+// 精度1以上の範囲は、高さ方向の境界が精度の粗い拡張空間IDの境界と一致する。
+//
+// 引数：
+//
+//	cell：範囲
+//
+// 戻り値：
+//
+//	範囲を包含する障害物のうち最初に入力された障害物の入力順
+//	範囲を包含する障害物がある場合true
+func (l *lineOfSight) findObstacle(cell lineOfSightCell) (int, bool) {
+	if cell.zoom == 0 {
+		return 0, false
+	}
+
+	// 高さ方向の下端(単位:2^-10m)
+	bottom := cell.indexes[0]<<(consts.MaxTileXYZZoom-cell.zoom) - altitudeRange<<(consts.MaxTileXYZZoom-consts.ZOriginValue)
+
+	found, order := false, 0
+	for zoomSet, obstacles := range l.obstacles {
+		if zoomSet[0] > cell.zoom || zoomSet[1] > cell.zoom {
+			continue
+		}
+		key := [3]int64{
+			cell.indexes[1] >> (cell.zoom - zoomSet[0]),
+			cell.indexes[2] >> (cell.zoom - zoomSet[0]),
+			bottom >> (consts.MaxTileXYZZoom - zoomSet[1]),
+		}
+		if index, ok := obstacles[key]; ok && (!found || index < order) {
+			found, order = true, index
+		}
+	}
+	return order, found
+}
+
+// enterCell 線分が範囲に入る位置の取得
+//
+// 引数：
+//
+//	zoom   ：範囲の精度
+//	indexes：範囲の高さ、経度方向、緯度方向のインデックス
+//
+// 戻り値：
+//
+//	線分が範囲に入る位置(始点0、終点1)
+//	線分が範囲を通過する場合true
+func (l *lineOfSight) enterCell(zoom int64, indexes tree.Indexs) (float64, bool) {
+	size := math.Ldexp(1, -int(zoom))
+
+	enter, exit := 0.0, 1.0
+	for i, index := range indexes {
+		lower, upper := float64(index)*size, float64(index+1)*size
+		if l.direction[i] == 0 {
+			if l.origin[i] < lower || l.origin[i] > upper {
+				return 0, false
+			}
+			continue
+		}
+
+		t1 := (lower - l.origin[i]) / l.direction[i]
+		t2 := (upper - l.origin[i]) / l.direction[i]
+		enter = max(enter, min(t1, t2))
+		exit = min(exit, max(t1, t2))
+		if enter > exit {
+			return 0, false
+		}
+	}
+	return enter, true
+}
+
+// distance 始点から線分上の位置までの距離の取得
+//
+// 引数：
+//
+//	start：始点
+//	t    ：線分上の位置(始点0、終点1)
+//
+// 戻り値：
+//
+//	始点と線分上の位置の地心直交座標における距離(単位:m)
+func (l *lineOfSight) distance(start *object.Point, t float64) float64 {
+	point := l.origin.Add(l.direction.Mul(t))
+	lat := math.Atan(math.Sinh(math.Pi*(1-2*point[2]))) * 180 / math.Pi
+
+	from := geodesy.GeocentricFromGeodetic(geodesy.Geodetic{start.Lon(), start.Lat(), start.Alt()})
+	to := geodesy.GeocentricFromGeodetic(geodesy.Geodetic{point[1]*360 - 180, lat, point[0]*2*altitudeRange - altitudeRange})
+	return mgl64.Vec3(to).Sub(mgl64.Vec3(from)).Len()
+}
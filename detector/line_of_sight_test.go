@@ -0,0 +1,102 @@
+package detector
+
+import (
+	"math"
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// TestCheckLineOfSightOnExtendedSpatialIds01 拡張空間IDによる見通しの判定関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 始点：経度0.01度、緯度0.01度、高さ10.5m、終点：経度1度、緯度0.01度、高さ10.5m
+//   - パターン1：線分上の"10/513/511/20/0"と、それより始点に近い"12/2051/2047/20/0"
+//   - パターン2：線分の上方の"10/513/511/20/1"
+//   - パターン3：線分上の垂直方向精度25の"10/513/511/25/10"と、その上方の"10/512/511/25/11"
+//
+// + 確認内容
+//   - パターン1：始点に近い"12/2051/2047/20/0"と、その西端までの距離が返却されること
+//   - パターン2：見通しありが返却されること
+//   - パターン3：水平方向精度と垂直方向精度の異なる"10/513/511/25/10"と、その西端までの距離が返却されること
+func TestCheckLineOfSightOnExtendedSpatialIds01(t *testing.T) {
+	start, _ := object.NewPoint(0.01, 0.01, 10.5)
+	end, _ := object.NewPoint(1, 0.01, 10.5)
+
+	// 線分上の経度lonの位置までの距離
+	sight := &lineOfSight{}
+	sight.origin, _ = getNormalizedCoordinate(start)
+	target, _ := getNormalizedCoordinate(end)
+	sight.direction = target.Sub(sight.origin)
+	distanceTo := func(lon float64) float64 {
+		return sight.distance(start, (lon-0.01)/(1-0.01))
+	}
+
+	testCases := []struct {
+		obstacleIDs    []string
+		expectVisible  bool
+		expectID       string
+		expectDistance float64
+	}{
+		{[]string{"10/513/511/20/0", "12/2051/2047/20/0"}, false, "12/2051/2047/20/0", distanceTo(2051.0/4096*360 - 180)},
+		{[]string{"10/513/511/20/1"}, true, "", 0},
+		{[]string{"10/512/511/25/11", "10/513/511/25/10"}, false, "10/513/511/25/10", distanceTo(513.0/1024*360 - 180)},
+	}
+
+	for _, testCase := range testCases {
+		resultVisible, resultID, resultDistance, resultErr := CheckLineOfSightOnExtendedSpatialIds(start, end, testCase.obstacleIDs)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if resultVisible != testCase.expectVisible || resultID != testCase.expectID {
+			t.Errorf("見通し - 期待値：%v %v, 取得値：%v %v", testCase.expectVisible, testCase.expectID, resultVisible, resultID)
+		}
+		if math.Abs(resultDistance-testCase.expectDistance) > 1e-3 {
+			t.Errorf("距離 - 期待値：%v, 取得値：%v", testCase.expectDistance, resultDistance)
+		}
+	}
+
+	// 西端までの距離はおよそ経度差の距離となる
+	_, _, resultDistance, _ := CheckLineOfSightOnExtendedSpatialIds(start, end, testCases[0].obstacleIDs)
+	if expectDistance := (2051.0/4096*360 - 180 - 0.01) * 111319.49; math.Abs(resultDistance-expectDistance) > 1 {
+		t.Errorf("距離 - 期待値：%v, 取得値：%v", expectDistance, resultDistance)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestCheckLineOfSightOnExtendedSpatialIds02 拡張空間IDによる見通しの判定関数 異常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：拡張空間IDフォーマット不正
+//   - パターン2：x成分が範囲外
+//   - パターン3：始点の高さが範囲外
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestCheckLineOfSightOnExtendedSpatialIds02(t *testing.T) {
+	start, _ := object.NewPoint(0.01, 0.01, 10.5)
+	end, _ := object.NewPoint(1, 0.01, 10.5)
+	high, _ := object.NewPoint(0.01, 0.01, 1<<24)
+
+	testCases := []struct {
+		start       *object.Point
+		obstacleIDs []string
+		expectErr   string
+	}{
+		{start, []string{"10/513/511/20"}, "InputValueError,入力チェックエラー @obstacleIDs[0]"},
+		{start, []string{"10/513/511/20/0", "2/4/0/20/0"}, "InputValueError,入力チェックエラー,index out of range: 2/4/0/20/0 @obstacleIDs[1]"},
+		{high, []string{"10/513/511/20/0"}, "InputValueError,入力チェックエラー,altitude is out of range: 1.6777216e+07"},
+	}
+
+	for _, testCase := range testCases {
+		_, _, _, resultErr := CheckLineOfSightOnExtendedSpatialIds(testCase.start, end, testCase.obstacleIDs)
+		if resultErr == nil || resultErr.Error() != testCase.expectErr {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expectErr, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
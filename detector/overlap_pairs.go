@@ -0,0 +1,375 @@
+package detector
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/consts"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/transform"
+)
+
+// overlapEntry 重複の検出用の空間IDの成分
+//
+// 高さ成分は、精度を下げた場合に右シフトで親の高さ成分となる値とする。
+type overlapEntry struct {
+	hZoom, vZoom int64 // 水平方向精度、垂直方向精度
+	x, y, z      int64 // x成分、y成分、高さ成分
+}
+
+// overlapIndex 重複の検出用に空間ID列を格納した索引
+//
+// 精度の組ごとに、重複の判定を行う精度へ変換した成分の索引を必要になった時点で作成する。
+type overlapIndex struct {
+	entries  []overlapEntry                      // 空間IDの成分
+	zoomSets [][2]int64                          // 水平方向精度、垂直方向精度の組の昇順のスライス
+	groups   map[[2]int64][]int                  // 精度の組ごとの空間IDの入力順
+	reduced  map[[4]int64]map[overlapEntry][]int // 精度の組、変換後の精度の組ごとの、変換後の成分の空間IDの入力順
+}
+
+// GetSpatialIdsArrayOverlaps 2つの空間ID列の重複する組の取得関数
+//
+// 比較対象として入力された2つの空間ID列の、重複する空間IDの組を全て取得する。
+// 重複の報告に使用する。重複の有無のみ必要な場合は CheckSpatialIdsArrayOverlap を使用する。
+//
+// 引数:
+//
+//	spatialIds1, spatialIds2: 重複判定対象の空間ID列。ズームレベルが異なっている入力も許容。
+//	maxCount: 取得する組の最大数。この数の組が見つかった時点で検出を終了する。0以下の場合は上限なし。
+//
+// ただし高度は16,777,216未満 〜 -16,777,216m以上に制限される(この範囲で高度変換を行う)
+//
+// 戻り値:
+//
+//	[][2]int:
+//		重複する組の(空間ID列1の入力順, 空間ID列2の入力順)のスライス。空間ID列1、空間ID列2の入力順の昇順で返却される。
+//
+//	error:
+//		以下の条件に当てはまる場合、エラーインスタンスが返却される。このときスライスはnilで返却される。
+//	 		空間IDフォーマット不正：空間IDのフォーマットに違反する値が"重複判定対象空間ID"に入力されていた場合。
+//	 		空間ID範囲外：ズームレベル、x成分、y成分が範囲外の空間IDが入力されていた場合。
+//	 		空間ID高度範囲外：高度範囲外の空間IDが入力されていた場合。
+func GetSpatialIdsArrayOverlaps(spatialIds1 []string, spatialIds2 []string, maxCount int) ([][2]int, error) {
+	entries1, entries2, err := getOverlapEntriesOnSpatialIds(spatialIds1, spatialIds2)
+	if err != nil {
+		return nil, err
+	}
+	return getOverlapPairs(entries1, entries2, maxCount), nil
+}
+
+// GetSpatialIdsArrayIntersection 2つの空間ID列の重複する範囲の取得関数
+//
+// 比較対象として入力された2つの空間ID列の、重複する空間IDの組ごとの共通部分を空間ID列として取得する。
+// 共通部分は、組のうちズームレベルの大きい方の空間IDとなる。
+//
+// 引数:
+//
+//	spatialIds1, spatialIds2: 重複判定対象の空間ID列。ズームレベルが異なっている入力も許容。
+//	maxCount: 共通部分を取得する組の最大数。この数の組が見つかった時点で検出を終了する。0以下の場合は上限なし。
+//
+// ただし高度は16,777,216未満 〜 -16,777,216m以上に制限される(この範囲で高度変換を行う)
+//
+// 戻り値:
+//
+//	[]string:
+//		重複する範囲の空間IDのスライス。重複する組の順に、同じ空間IDを除いて返却される。
+//
+//	error:
+//		GetSpatialIdsArrayOverlaps と同じ条件でエラーインスタンスが返却される。このときスライスはnilで返却される。
+func GetSpatialIdsArrayIntersection(spatialIds1 []string, spatialIds2 []string, maxCount int) ([]string, error) {
+	entries1, entries2, err := getOverlapEntriesOnSpatialIds(spatialIds1, spatialIds2)
+	if err != nil {
+		return nil, err
+	}
+
+	intersection := []string{}
+	contained := map[string]struct{}{}
+	for _, pair := range getOverlapPairs(entries1, entries2, maxCount) {
+		spatialId := spatialIds1[pair[0]]
+		if entries2[pair[1]].hZoom > entries1[pair[0]].hZoom {
+			spatialId = spatialIds2[pair[1]]
+		}
+		if _, ok := contained[spatialId]; !ok {
+			contained[spatialId] = struct{}{}
+			intersection = append(intersection, spatialId)
+		}
+	}
+	return intersection, nil
+}
+
+// GetExtendedSpatialIdsArrayOverlaps 2つの拡張空間ID列の重複する組の取得関数
+//
+// 比較対象として入力された2つの拡張空間ID列の、重複する拡張空間IDの組を全て取得する。
+// 重複の報告に使用する。重複の有無のみ必要な場合は CheckExtendedSpatialIdsArrayOverlap を使用する。
+//
+// 引数:
+//
+//	extendedSpatialIds1, extendedSpatialIds2 : 重複判定対象の拡張空間ID列。ズームレベルが異なっている入力も許容。
+//	maxCount: 取得する組の最大数。この数の組が見つかった時点で検出を終了する。0以下の場合は上限なし。
+//
+// ただし高度は16,777,216未満 〜 -16,777,216m以上に制限される
+//
+// 戻り値:
+//
+//	[][2]int:
+//		重複する組の(拡張空間ID列1の入力順, 拡張空間ID列2の入力順)のスライス。拡張空間ID列1、拡張空間ID列2の入力順の昇順で返却される。
+//
+//	error:
+//		以下の条件に当てはまる場合、エラーインスタンスが返却される。このときスライスはnilで返却される。
+//	 		拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"重複判定対象空間ID"に入力されていた場合。
+//	 		拡張空間ID範囲外：ズームレベル、x成分、y成分が範囲外の拡張空間IDが入力されていた場合。
+//	 		拡張空間ID高度範囲外：高度範囲外の拡張空間IDが入力されていた場合。
+func GetExtendedSpatialIdsArrayOverlaps(extendedSpatialIds1 []string, extendedSpatialIds2 []string, maxCount int) ([][2]int, error) {
+	entries1, entries2, err := getOverlapEntriesOnExtendedSpatialIds(extendedSpatialIds1, extendedSpatialIds2)
+	if err != nil {
+		return nil, err
+	}
+	return getOverlapPairs(entries1, entries2, maxCount), nil
+}
+
+// GetExtendedSpatialIdsArrayIntersection 2つの拡張空間ID列の重複する範囲の取得関数
+//
+// 比較対象として入力された2つの拡張空間ID列の、重複する拡張空間IDの組ごとの共通部分を拡張空間ID列として取得する。
+// 共通部分は、水平方向、垂直方向それぞれ組のうちズームレベルの大きい方の成分からなる拡張空間IDとなる。
+//
+// 引数:
+//
+//	extendedSpatialIds1, extendedSpatialIds2 : 重複判定対象の拡張空間ID列。ズームレベルが異なっている入力も許容。
+//	maxCount: 共通部分を取得する組の最大数。この数の組が見つかった時点で検出を終了する。0以下の場合は上限なし。
+//
+// ただし高度は16,777,216未満 〜 -16,777,216m以上に制限される
+//
+// 戻り値:
+//
+//	[]string:
+//		重複する範囲の拡張空間IDのスライス。重複する組の順に、同じ拡張空間IDを除いて返却される。
+//
+//	error:
+//		GetExtendedSpatialIdsArrayOverlaps と同じ条件でエラーインスタンスが返却される。このときスライスはnilで返却される。
+func GetExtendedSpatialIdsArrayIntersection(extendedSpatialIds1 []string, extendedSpatialIds2 []string, maxCount int) ([]string, error) {
+	entries1, entries2, err := getOverlapEntriesOnExtendedSpatialIds(extendedSpatialIds1, extendedSpatialIds2)
+	if err != nil {
+		return nil, err
+	}
+
+	intersection := []string{}
+	contained := map[overlapEntry]struct{}{}
+	for _, pair := range getOverlapPairs(entries1, entries2, maxCount) {
+		entry := entries1[pair[0]].intersect(entries2[pair[1]])
+		if _, ok := contained[entry]; !ok {
+			contained[entry] = struct{}{}
+			intersection = append(intersection, fmt.Sprintf("%v/%v/%v/%v/%v", entry.hZoom, entry.x, entry.y, entry.vZoom, entry.z))
+		}
+	}
+	return intersection, nil
+}
+
+// getOverlapEntriesOnSpatialIds 2つの空間ID列の成分の取得
+//
+// 高さ成分は、高度インデックスをオフセット変換のみ実行して自然数にした値とする。
+//
+// 引数：
+//
+//	spatialIds1, spatialIds2：空間ID列
+//
+// 戻り値：
+//
+//	空間ID列1の成分のスライス
+//	空間ID列2の成分のスライス
+//
+// 戻り値(エラー)：
+//
+//	空間IDが不正な場合エラーを返却する。
+func getOverlapEntriesOnSpatialIds(spatialIds1 []string, spatialIds2 []string) ([]overlapEntry, []overlapEntry, error) {
+	getEntries := func(spatialIds []string, name string) ([]overlapEntry, error) {
+		entries := make([]overlapEntry, 0, len(spatialIds))
+		for i, spatialId := range spatialIds {
+			zoom, f, x, y, err := getSpatialIdAttrs(spatialId)
+			if err != nil {
+				return nil, fmt.Errorf("%w @%v[%v]", err, name, i)
+			}
+			if zoom < 0 || zoom > consts.MaxTileXYZZoom || x < 0 || x >= 1<<zoom || y < 0 || y >= 1<<zoom {
+				return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("index out of range @%v[%v] = %v", name, i, spatialId))
+			}
+			// minAltitudeKey == maxAltitudeKeyになるため結果は片方のみ利用する
+			convertedFIndex, _, err := transform.ConvertZToMinMaxAltitudekey(int64(f), int64(zoom), int64(zoom), consts.ZOriginValue, consts.ZBaseOffsetForNegativeFIndex)
+			if err != nil || convertedFIndex < 0 {
+				return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("input f-index %v is out of altitude range @%v[%v] = %v", f, name, i, spatialId))
+			}
+			entries = append(entries, overlapEntry{int64(zoom), int64(zoom), int64(x), int64(y), convertedFIndex})
+		}
+		return entries, nil
+	}
+
+	entries1, err := getEntries(spatialIds1, "spatialId1")
+	if err != nil {
+		return nil, nil, err
+	}
+	entries2, err := getEntries(spatialIds2, "spatialId2")
+	if err != nil {
+		return nil, nil, err
+	}
+	return entries1, entries2, nil
+}
+
+// getOverlapEntriesOnExtendedSpatialIds 2つの拡張空間ID列の成分の取得
+//
+// 引数：
+//
+//	extendedSpatialIds1, extendedSpatialIds2：拡張空間ID列
+//
+// 戻り値：
+//
+//	拡張空間ID列1の成分のスライス
+//	拡張空間ID列2の成分のスライス
+//
+// 戻り値(エラー)：
+//
+//	拡張空間IDが不正な場合エラーを返却する。
+func getOverlapEntriesOnExtendedSpatialIds(extendedSpatialIds1 []string, extendedSpatialIds2 []string) ([]overlapEntry, []overlapEntry, error) {
+	getEntries := func(extendedSpatialIds []string, name string) ([]overlapEntry, error) {
+		entries := make([]overlapEntry, 0, len(extendedSpatialIds))
+		for i, extendedSpatialId := range extendedSpatialIds {
+			id, _, _, err := getTreeIndexesOnExtendedSpatialId(extendedSpatialId)
+			if err != nil {
+				return nil, fmt.Errorf("%w @%v[%v]", err, name, i)
+			}
+			entries = append(entries, overlapEntry{id.HZoom(), id.VZoom(), id.X(), id.Y(), id.Z()})
+		}
+		return entries, nil
+	}
+
+	entries1, err := getEntries(extendedSpatialIds1, "extendedSpatialIds1")
+	if err != nil {
+		return nil, nil, err
+	}
+	entries2, err := getEntries(extendedSpatialIds2, "extendedSpatialIds2")
+	if err != nil {
+		return nil, nil, err
+	}
+	return entries1, entries2, nil
+}
+
+// getOverlapPairs 重複する組の取得
+//
+// 引数：
+//
+//	entries1, entries2：空間ID列の成分
+//	maxCount          ：取得する組の最大数。0以下の場合は上限なし。
+//
+// 戻り値：
+//
+//	重複する組の入力順のスライス。入力順の昇順。
+func getOverlapPairs(entries1, entries2 []overlapEntry, maxCount int) [][2]int {
+	index := newOverlapIndex(entries2)
+
+	pairs := [][2]int{}
+	for i, entry1 := range entries1 {
+		for _, j := range index.find(entry1) {
+			pairs = append(pairs, [2]int{i, j})
+			if len(pairs) == maxCount {
+				return pairs
+			}
+		}
+	}
+	return pairs
+}
+
+// newOverlapIndex 重複の検出用の索引の作成
+//
+// 引数：
+//
+//	entries：空間ID列の成分
+//
+// 戻り値：
+//
+//	索引
+func newOverlapIndex(entries []overlapEntry) *overlapIndex {
+	index := &overlapIndex{
+		entries: entries,
+		groups:  map[[2]int64][]int{},
+		reduced: map[[4]int64]map[overlapEntry][]int{},
+	}
+	for i, entry := range entries {
+		zoomSet := [2]int64{entry.hZoom, entry.vZoom}
+		if _, ok := index.groups[zoomSet]; !ok {
+			index.zoomSets = append(index.zoomSets, zoomSet)
+		}
+		index.groups[zoomSet] = append(index.groups[zoomSet], i)
+	}
+	slices.SortFunc(index.zoomSets, func(a, b [2]int64) int {
+		return slices.Compare(a[:], b[:])
+	})
+	return index
+}
+
+// find 重複する空間IDの取得
+//
+// 2つの空間IDを水平方向、垂直方向それぞれ小さい方の精度に変換し、一致する場合に重複するとする。
+//
+// 引数：
+//
+//	entry：空間IDの成分
+//
+// 戻り値：
+//
+//	重複する空間IDの入力順の昇順のスライス
+func (o *overlapIndex) find(entry overlapEntry) []int {
+	found := []int{}
+	for _, zoomSet := range o.zoomSets {
+		hZoom, vZoom := min(entry.hZoom, zoomSet[0]), min(entry.vZoom, zoomSet[1])
+		key := [4]int64{zoomSet[0], zoomSet[1], hZoom, vZoom}
+		reduced, ok := o.reduced[key]
+		if !ok {
+			reduced = map[overlapEntry][]int{}
+			for _, i := range o.groups[zoomSet] {
+				parent := o.entries[i].reduce(hZoom, vZoom)
+				reduced[parent] = append(reduced[parent], i)
+			}
+			o.reduced[key] = reduced
+		}
+		found = append(found, reduced[entry.reduce(hZoom, vZoom)]...)
+	}
+	slices.Sort(found)
+	return found
+}
+
+// reduce 精度を下げた成分の取得
+//
+// 引数：
+//
+//	hZoom：水平方向精度。元の水平方向精度以下であること。
+//	vZoom：垂直方向精度。元の垂直方向精度以下であること。
+//
+// 戻り値：
+//
+//	成分を包含する精度の成分
+func (e overlapEntry) reduce(hZoom, vZoom int64) overlapEntry {
+	return overlapEntry{
+		hZoom: hZoom,
+		vZoom: vZoom,
+		x:     e.x >> (e.hZoom - hZoom),
+		y:     e.y >> (e.hZoom - hZoom),
+		z:     e.z >> (e.vZoom - vZoom),
+	}
+}
+
+// intersect 重複する成分の共通部分の取得
+//
+// 引数：
+//
+//	other：重複する成分
+//
+// 戻り値：
+//
+//	水平方向、垂直方向それぞれ精度の大きい方の成分
+func (e overlapEntry) intersect(other overlapEntry) overlapEntry {
+	intersection := e
+	if other.hZoom > e.hZoom {
+		intersection.hZoom, intersection.x, intersection.y = other.hZoom, other.x, other.y
+	}
+	if other.vZoom > e.vZoom {
+		intersection.vZoom, intersection.z = other.vZoom, other.z
+	}
+	return intersection
+}
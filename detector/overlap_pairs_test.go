@@ -0,0 +1,167 @@
+package detector
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// TestGetSpatialIdsArrayOverlaps01 2つの空間ID列の重複する組の取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 空間ID列1：{"20/0/524288/524287", "19/0/262144/262143", "20/5/524290/524287"}
+//   - 空間ID列2：{"21/1/1048576/1048574", "20/5/524290/524287", "20/0/524290/524287"}
+//   - パターン1：組の最大数 0
+//   - パターン2：組の最大数 2
+//
+// + 確認内容
+//   - パターン1：ズームレベルの異なる組を含む全ての重複する組が入力順に返却されること
+//   - パターン2：最初の2組が返却されること
+//   - 重複する範囲として、組のうちズームレベルの大きい方の空間IDが重複なく返却されること
+//   - 全ての組の判定結果が CheckSpatialIdsOverlap と一致すること
+func TestGetSpatialIdsArrayOverlaps01(t *testing.T) {
+	spatialIds1 := []string{"20/0/524288/524287", "19/0/262144/262143", "20/5/524290/524287"}
+	spatialIds2 := []string{"21/1/1048576/1048574", "20/5/524290/524287", "20/0/524290/524287"}
+
+	testCases := []struct {
+		maxCount           int
+		expectPairs        [][2]int
+		expectIntersection []string
+	}{
+		{0, [][2]int{{0, 0}, {1, 0}, {2, 1}}, []string{"21/1/1048576/1048574", "20/5/524290/524287"}},
+		{2, [][2]int{{0, 0}, {1, 0}}, []string{"21/1/1048576/1048574"}},
+	}
+
+	for _, testCase := range testCases {
+		resultPairs, resultErr := GetSpatialIdsArrayOverlaps(spatialIds1, spatialIds2, testCase.maxCount)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultPairs, testCase.expectPairs) {
+			t.Errorf("重複する組 - 期待値：%v, 取得値：%v", testCase.expectPairs, resultPairs)
+		}
+
+		resultIntersection, resultErr := GetSpatialIdsArrayIntersection(spatialIds1, spatialIds2, testCase.maxCount)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultIntersection, testCase.expectIntersection) {
+			t.Errorf("空間ID - 期待値：%v, 取得値：%v", testCase.expectIntersection, resultIntersection)
+		}
+	}
+
+	resultPairs, _ := GetSpatialIdsArrayOverlaps(spatialIds1, spatialIds2, 0)
+	for i, spatialId1 := range spatialIds1 {
+		for j, spatialId2 := range spatialIds2 {
+			expectValue, _ := CheckSpatialIdsOverlap(spatialId1, spatialId2)
+			if resultValue := slices.Contains(resultPairs, [2]int{i, j}); resultValue != expectValue {
+				t.Errorf("重複 %v %v - 期待値：%v, 取得値：%v", spatialId1, spatialId2, expectValue, resultValue)
+			}
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetExtendedSpatialIdsArrayOverlaps01 2つの拡張空間ID列の重複する組の取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 拡張空間ID列1：{"20/524288/524287/25/0", "19/262144/262143/20/0", "20/524290/524287/25/5", "20/524288/524287/25/-1"}
+//   - 拡張空間ID列2：{"21/1048576/1048574/24/0", "20/524290/524287/26/10", "20/524290/524287/25/0", "20/524288/524287/24/-1"}
+//   - パターン1：組の最大数 0
+//   - パターン2：組の最大数 3
+//
+// + 確認内容
+//   - パターン1：水平方向、垂直方向のズームレベルの大小が異なる組、負の高さ成分の組を含む全ての重複する組が入力順に返却されること
+//   - パターン2：最初の3組が返却されること
+//   - 重複する範囲として、水平方向、垂直方向それぞれズームレベルの大きい方の成分からなる拡張空間IDが返却されること
+//   - 高さ成分が負でない全ての組の判定結果が CheckExtendedSpatialIdsOverlap と一致すること
+func TestGetExtendedSpatialIdsArrayOverlaps01(t *testing.T) {
+	extendedSpatialIds1 := []string{"20/524288/524287/25/0", "19/262144/262143/20/0", "20/524290/524287/25/5", "20/524288/524287/25/-1"}
+	extendedSpatialIds2 := []string{"21/1048576/1048574/24/0", "20/524290/524287/26/10", "20/524290/524287/25/0", "20/524288/524287/24/-1"}
+
+	testCases := []struct {
+		maxCount           int
+		expectPairs        [][2]int
+		expectIntersection []string
+	}{
+		{0, [][2]int{{0, 0}, {1, 0}, {2, 1}, {3, 3}}, []string{"21/1048576/1048574/25/0", "21/1048576/1048574/24/0", "20/524290/524287/26/10", "20/524288/524287/25/-1"}},
+		{3, [][2]int{{0, 0}, {1, 0}, {2, 1}}, []string{"21/1048576/1048574/25/0", "21/1048576/1048574/24/0", "20/524290/524287/26/10"}},
+	}
+
+	for _, testCase := range testCases {
+		resultPairs, resultErr := GetExtendedSpatialIdsArrayOverlaps(extendedSpatialIds1, extendedSpatialIds2, testCase.maxCount)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultPairs, testCase.expectPairs) {
+			t.Errorf("重複する組 - 期待値：%v, 取得値：%v", testCase.expectPairs, resultPairs)
+		}
+
+		resultIntersection, resultErr := GetExtendedSpatialIdsArrayIntersection(extendedSpatialIds1, extendedSpatialIds2, testCase.maxCount)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultIntersection, testCase.expectIntersection) {
+			t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", testCase.expectIntersection, resultIntersection)
+		}
+	}
+
+	// 負の高さ成分は ChangeExtendedSpatialIdsZoom が0方向に丸めるため、CheckExtendedSpatialIdsOverlap との比較から除く
+	resultPairs, _ := GetExtendedSpatialIdsArrayOverlaps(extendedSpatialIds1, extendedSpatialIds2, 0)
+	for i, extendedSpatialId1 := range extendedSpatialIds1[:3] {
+		for j, extendedSpatialId2 := range extendedSpatialIds2[:3] {
+			expectValue, _ := CheckExtendedSpatialIdsOverlap(extendedSpatialId1, extendedSpatialId2)
+			if resultValue := slices.Contains(resultPairs, [2]int{i, j}); resultValue != expectValue {
+				t.Errorf("重複 %v %v - 期待値：%v, 取得値：%v", extendedSpatialId1, extendedSpatialId2, expectValue, resultValue)
+			}
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetExtendedSpatialIdsArrayOverlaps02 2つの空間ID列、拡張空間ID列の重複する組の取得関数 空の入力、異常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：空の空間ID列、拡張空間ID列
+//   - パターン2：空間ID列2の空間IDフォーマット不正
+//   - パターン3：空間ID列1の高度範囲外
+//   - パターン4：拡張空間ID列1の拡張空間IDフォーマット不正
+//   - パターン5：拡張空間ID列2のx成分の範囲外
+//
+// + 確認内容
+//   - パターン1：空のスライスが返却されること
+//   - パターン2～5：エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetExtendedSpatialIdsArrayOverlaps02(t *testing.T) {
+	resultPairs, resultErr := GetSpatialIdsArrayOverlaps([]string{}, []string{"20/0/524288/524287"}, 0)
+	if resultErr != nil || len(resultPairs) != 0 {
+		t.Errorf("重複する組 - 取得値：%v %v", resultPairs, resultErr)
+	}
+	resultIntersection, resultErr := GetExtendedSpatialIdsArrayIntersection([]string{"20/524288/524287/25/0"}, []string{}, 0)
+	if resultErr != nil || len(resultIntersection) != 0 {
+		t.Errorf("拡張空間ID - 取得値：%v %v", resultIntersection, resultErr)
+	}
+
+	_, resultErr = GetSpatialIdsArrayOverlaps([]string{"20/0/524288/524287"}, []string{"20/0/524288"}, 0)
+	if expectErr := "InputValueError,入力チェックエラー,spatialId: 20/0/524288 @spatialId2[0]"; resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+	_, resultErr = GetSpatialIdsArrayIntersection([]string{"0/1/0/0"}, []string{"20/0/524288/524287"}, 0)
+	if expectErr := "InputValueError,入力チェックエラー,input f-index 1 is out of altitude range @spatialId1[0] = 0/1/0/0"; resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+	_, resultErr = GetExtendedSpatialIdsArrayOverlaps([]string{"20/524288/524287/25"}, []string{"20/524288/524287/25/0"}, 0)
+	if expectErr := "InputValueError,入力チェックエラー @extendedSpatialIds1[0]"; resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+	_, resultErr = GetExtendedSpatialIdsArrayIntersection([]string{"20/524288/524287/25/0"}, []string{"20/524288/524287/25/0", "2/4/0/25/0"}, 0)
+	if expectErr := "InputValueError,入力チェックエラー,index out of range: 2/4/0/25/0 @extendedSpatialIds2[1]"; resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}
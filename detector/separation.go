@@ -0,0 +1,477 @@
+package detector
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/go-gl/mathgl/mgl64"
+	closest "github.com/trajectoryjp/closest_go"
+	geodesy "github.com/trajectoryjp/geodesy_go/coordinates"
+	"github.com/trajectoryjp/multidimensional-radix-tree/src/tree"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/consts"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// 距離の下限値の計算に使用する地球の最小の曲率半径(単位:m)。WGS84楕円体の赤道における子午線曲率半径。
+const minCurvatureRadius = 6335439.0
+
+// 距離の下限値の近似誤差に対する余裕
+const lowerDistanceMargin = 0.99
+
+// geodeticBox 経度、緯度、高さの範囲
+type geodeticBox struct {
+	west, east   float64 // 経度の範囲(単位:度)
+	south, north float64 // 緯度の範囲(単位:度)
+	bottom, top  float64 // 高さの範囲(単位:m)
+}
+
+// separationCell 最小距離の探索対象の範囲
+type separationCell struct {
+	zoom          int64       // 精度
+	indexes       tree.Indexs // 高さ、経度方向、緯度方向のインデックス
+	lowerDistance float64     // 範囲までの距離の下限値(単位:m)
+}
+
+// separationQueue 最小距離の探索の優先度付きキュー
+type separationQueue []separationCell
+
+// separationTree 最小距離の探索用に拡張空間IDを格納したradix tree
+//
+// 精度の粗い拡張空間IDの内部の範囲を探索しないよう、radix treeに格納する精度ごとにradix treeを分ける。
+type separationTree struct {
+	trees   map[int64]tree.TreeInterface // radix treeに格納する精度ごとのradix tree
+	zooms   []int64                      // radix treeに格納する精度の昇順のスライス
+	entries map[[4]int64][]int           // radix treeの範囲(精度、高さ、経度方向、緯度方向のインデックス)ごとの拡張空間IDの入力順
+}
+
+// GetMinimumDistanceOnExtendedSpatialIds 2つの拡張空間ID列の最小距離の取得関数
+//
+// 2つの拡張空間ID列の間の最小距離と、最小距離となる拡張空間IDの組を取得する。
+// 2つの予約空間の間の離隔の確認に使用する。
+//
+// 距離は、拡張空間IDの8個の頂点を地心直交座標に変換した凸包の間の距離とし、closest_go で計測する。
+// 重なる、または接する拡張空間IDの組の距離は0となる。
+// closest_go の計測値が距離の下限値を下回る場合は、凸包の頂点、辺、三角形の間の距離を全て計算して求め直す。
+//
+// 拡張空間ID列2を格納したradix treeの範囲を、拡張空間ID列1の各拡張空間IDから距離の下限値の小さい順に探索し、
+// それまでの最小距離より遠い範囲を除外するため、全ての組の距離を計測することはない。
+// 距離の下限値は地球を最小の曲率半径の球で近似して求め、近似誤差に対して1%の余裕を設ける。
+//
+// 引数：
+//
+//	extendedSpatialIds1：拡張空間ID列1。精度が混在していてもよい。
+//	extendedSpatialIds2：拡張空間ID列2。精度が混在していてもよい。
+//
+// 戻り値：
+//
+//	最小距離(単位:m)。いずれかの拡張空間ID列が空の場合は math.Inf(1)。
+//	最小距離となる拡張空間ID列1の拡張空間ID。いずれかの拡張空間ID列が空の場合は空文字。
+//	最小距離となる拡張空間ID列2の拡張空間ID。いずれかの拡張空間ID列が空の場合は空文字。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのx成分、y成分が精度の範囲外の場合。
+//	 高度範囲外                ：拡張空間IDの高さが -16,777,216m以上 ～ 16,777,216m未満の範囲外の場合。
+func GetMinimumDistanceOnExtendedSpatialIds(extendedSpatialIds1, extendedSpatialIds2 []string) (float64, string, string, error) {
+	ids1 := make([]*object.ExtendedSpatialID, 0, len(extendedSpatialIds1))
+	for i, extendedSpatialId1 := range extendedSpatialIds1 {
+		id1, _, _, err := getTreeIndexesOnExtendedSpatialId(extendedSpatialId1)
+		if err != nil {
+			return 0, "", "", fmt.Errorf("%w @extendedSpatialIds1[%v]", err, i)
+		}
+		ids1 = append(ids1, id1)
+	}
+	set2 := &separationTree{trees: map[int64]tree.TreeInterface{}, entries: map[[4]int64][]int{}}
+	for i, extendedSpatialId2 := range extendedSpatialIds2 {
+		if err := set2.append(extendedSpatialId2, i); err != nil {
+			return 0, "", "", fmt.Errorf("%w @extendedSpatialIds2[%v]", err, i)
+		}
+	}
+	if len(extendedSpatialIds1) == 0 || len(extendedSpatialIds2) == 0 {
+		return math.Inf(1), "", "", nil
+	}
+
+	boxes2, hulls2 := map[int]geodeticBox{}, map[int][]*mgl64.Vec3{}
+	best, best1, best2 := math.Inf(1), 0, 0
+	for i, id1 := range ids1 {
+		box1 := getGeodeticBoxOnId(id1)
+		hull1 := box1.convexHull()
+
+		queue := &separationQueue{{indexes: tree.Indexs{0, 0, 0}}}
+		for queue.Len() > 0 {
+			cell := heap.Pop(queue).(separationCell)
+			if cell.lowerDistance >= best {
+				break
+			}
+
+			for _, j := range set2.entries[[4]int64{cell.zoom, cell.indexes[0], cell.indexes[1], cell.indexes[2]}] {
+				if _, ok := hulls2[j]; !ok {
+					id2, _ := object.NewExtendedSpatialID(extendedSpatialIds2[j])
+					boxes2[j] = getGeodeticBoxOnId(id2)
+					hulls2[j] = boxes2[j].convexHull()
+				}
+
+				distance := measureConvexHulls(hull1, hulls2[j])
+				if distance < box1.lowerDistance(boxes2[j]) {
+					distance = measureConvexHullsExhaustively(hull1, hulls2[j])
+				}
+				if distance < best {
+					best, best1, best2 = distance, i, j
+				}
+			}
+
+			for k := int64(0); k < 8; k++ {
+				indexes := tree.Indexs{cell.indexes[0]*2 + k&1, cell.indexes[1]*2 + k>>1&1, cell.indexes[2]*2 + k>>2&1}
+				if !set2.isOverlap(cell.zoom+1, indexes) {
+					continue
+				}
+				heap.Push(queue, separationCell{
+					zoom:          cell.zoom + 1,
+					indexes:       indexes,
+					lowerDistance: box1.lowerDistance(getGeodeticBoxOnCell(cell.zoom+1, indexes)),
+				})
+			}
+		}
+
+		if best == 0 {
+			break
+		}
+	}
+
+	return best, extendedSpatialIds1[best1], extendedSpatialIds2[best2], nil
+}
+
+// append 拡張空間IDの追加
+//
+// 引数：
+//
+//	extendedSpatialID：拡張空間ID
+//	order            ：拡張空間IDの入力順
+//
+// 戻り値(エラー)：
+//
+//	拡張空間IDが不正な場合エラーを返却する。
+func (s *separationTree) append(extendedSpatialID string, order int) error {
+	_, zoom, indexes, err := getTreeIndexesOnExtendedSpatialId(extendedSpatialID)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := s.trees[zoom]; !ok {
+		s.trees[zoom] = tree.CreateTree(tree.Create3DTable())
+		s.zooms = append(s.zooms, zoom)
+		slices.Sort(s.zooms)
+	}
+	s.trees[zoom].Append(indexes, tree.ZoomSetLevel(zoom), extendedSpatialID)
+
+	key := [4]int64{zoom, indexes[0], indexes[1], indexes[2]}
+	s.entries[key] = append(s.entries[key], order)
+	return nil
+}
+
+// isOverlap 範囲の内部に格納された拡張空間IDの有無の判定
+//
+// 範囲より精度の粗い拡張空間IDは、範囲を包含していても対象としない。
+//
+// 引数：
+//
+//	zoom   ：範囲の精度
+//	indexes：範囲の高さ、経度方向、緯度方向のインデックス
+//
+// 戻り値：
+//
+//	範囲の精度以上の精度で格納された拡張空間IDが範囲と重なる場合true
+func (s *separationTree) isOverlap(zoom int64, indexes tree.Indexs) bool {
+	for _, treeZoom := range s.zooms {
+		if treeZoom >= zoom && s.trees[treeZoom].IsOverlap(indexes, tree.ZoomSetLevel(zoom)) {
+			return true
+		}
+	}
+	return false
+}
+
+// getGeodeticBoxOnId 拡張空間IDの経度、緯度、高さの範囲の取得
+//
+// 引数：
+//
+//	id：拡張空間ID
+//
+// 戻り値：
+//
+//	拡張空間IDの範囲
+func getGeodeticBoxOnId(id *object.ExtendedSpatialID) geodeticBox {
+	hSize := math.Ldexp(1, -int(id.HZoom()))
+	vSize := math.Ldexp(1, consts.ZOriginValue-int(id.VZoom()))
+	return geodeticBox{
+		west:   float64(id.X())*hSize*360 - 180,
+		east:   float64(id.X()+1)*hSize*360 - 180,
+		south:  getLatitudeOnNormalizedY(float64(id.Y()+1) * hSize),
+		north:  getLatitudeOnNormalizedY(float64(id.Y()) * hSize),
+		bottom: float64(id.Z()) * vSize,
+		top:    float64(id.Z()+1) * vSize,
+	}
+}
+
+// getGeodeticBoxOnCell radix treeの範囲の経度、緯度、高さの範囲の取得
+//
+// 引数：
+//
+//	zoom   ：範囲の精度
+//	indexes：範囲の高さ、経度方向、緯度方向のインデックス
+//
+// 戻り値：
+//
+//	範囲の経度、緯度、高さの範囲
+func getGeodeticBoxOnCell(zoom int64, indexes tree.Indexs) geodeticBox {
+	size := math.Ldexp(1, -int(zoom))
+	return geodeticBox{
+		west:   float64(indexes[1])*size*360 - 180,
+		east:   float64(indexes[1]+1)*size*360 - 180,
+		south:  getLatitudeOnNormalizedY(float64(indexes[2]+1) * size),
+		north:  getLatitudeOnNormalizedY(float64(indexes[2]) * size),
+		bottom: float64(indexes[0])*size*2*altitudeRange - altitudeRange,
+		top:    float64(indexes[0]+1)*size*2*altitudeRange - altitudeRange,
+	}
+}
+
+// getLatitudeOnNormalizedY 緯度方向の正規化座標の緯度の取得
+//
+// 引数：
+//
+//	y：北端を0、南端を1とする緯度方向の正規化座標
+//
+// 戻り値：
+//
+//	緯度(単位:度)
+func getLatitudeOnNormalizedY(y float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y))) * 180 / math.Pi
+}
+
+// convexHull 範囲の頂点の地心直交座標の取得
+//
+// 戻り値：
+//
+//	8個の頂点の地心直交座標
+func (b geodeticBox) convexHull() []*mgl64.Vec3 {
+	hull := make([]*mgl64.Vec3, 0, 8)
+	for _, lon := range []float64{b.west, b.east} {
+		for _, lat := range []float64{b.south, b.north} {
+			for _, alt := range []float64{b.bottom, b.top} {
+				vertex := mgl64.Vec3(geodesy.GeocentricFromGeodetic(geodesy.Geodetic{lon, lat, alt}))
+				hull = append(hull, &vertex)
+			}
+		}
+	}
+	return hull
+}
+
+// measureConvexHulls 凸包の間の距離の計測
+//
+// 地心直交座標のままでは桁落ちにより closest_go が距離を誤るため、1つ目の凸包の頂点を原点とした座標に変換して計測する。
+//
+// 引数：
+//
+//	hull1, hull2：凸包の頂点の地心直交座標
+//
+// 戻り値：
+//
+//	凸包の間の距離(単位:m)
+func measureConvexHulls(hull1, hull2 []*mgl64.Vec3) float64 {
+	origin := *hull1[0]
+	measure := closest.Measure{}
+	for i, hull := range [2][]*mgl64.Vec3{hull1, hull2} {
+		measure.ConvexHulls[i] = make([]*mgl64.Vec3, 0, len(hull))
+		for _, vertex := range hull {
+			local := vertex.Sub(origin)
+			measure.ConvexHulls[i] = append(measure.ConvexHulls[i], &local)
+		}
+	}
+	measure.MeasureNonnegativeDistance()
+	return measure.Distance
+}
+
+// measureConvexHullsExhaustively 凸包の間の距離の全探索による計測
+//
+// 離れた凸包の間の最短距離は、一方の頂点と他方の面の三角形の間、または辺と辺の間で得られる。
+// 頂点から作れる全ての三角形、線分は凸包に含まれるため、それらの間の距離の最小値を凸包の間の距離とする。
+// closest_go が距離を誤る場合の計測に使用する。重なる凸包の距離は正しく求められない。
+//
+// 引数：
+//
+//	hull1, hull2：凸包の頂点の地心直交座標
+//
+// 戻り値：
+//
+//	凸包の間の距離(単位:m)
+func measureConvexHullsExhaustively(hull1, hull2 []*mgl64.Vec3) float64 {
+	origin := *hull1[0]
+	var locals [2][]mgl64.Vec3
+	for i, hull := range [2][]*mgl64.Vec3{hull1, hull2} {
+		for _, vertex := range hull {
+			locals[i] = append(locals[i], vertex.Sub(origin))
+		}
+	}
+
+	distance := math.Inf(1)
+	for i, points := range locals {
+		others := locals[1-i]
+		for a := 0; a < len(points); a++ {
+			for b := a + 1; b < len(points); b++ {
+				for c := b + 1; c < len(points); c++ {
+					for _, other := range others {
+						distance = min(distance, measurePointTriangle(other, points[a], points[b], points[c]))
+					}
+				}
+			}
+		}
+	}
+	for a1 := 0; a1 < len(locals[0]); a1++ {
+		for b1 := a1 + 1; b1 < len(locals[0]); b1++ {
+			for a2 := 0; a2 < len(locals[1]); a2++ {
+				for b2 := a2 + 1; b2 < len(locals[1]); b2++ {
+					distance = min(distance, measureSegments(locals[0][a1], locals[0][b1], locals[1][a2], locals[1][b2]))
+				}
+			}
+		}
+	}
+	return distance
+}
+
+// measurePointTriangle 点と三角形の内部の間の距離の計測
+//
+// 点から三角形の平面への垂線の足が三角形の外部にある場合、最短距離は辺で得られるため対象としない。
+//
+// 引数：
+//
+//	point  ：点
+//	a, b, c：三角形の頂点
+//
+// 戻り値：
+//
+//	垂線の足が三角形の内部にある場合は垂線の長さ、それ以外の場合は math.Inf(1)
+func measurePointTriangle(point, a, b, c mgl64.Vec3) float64 {
+	ab, ac, ap := b.Sub(a), c.Sub(a), point.Sub(a)
+	normal := ab.Cross(ac)
+	area := normal.Dot(normal)
+	if area == 0 {
+		return math.Inf(1)
+	}
+
+	// 垂線の足の重心座標
+	v := ap.Cross(ac).Dot(normal) / area
+	w := ab.Cross(ap).Dot(normal) / area
+	if v < 0 || w < 0 || v+w > 1 {
+		return math.Inf(1)
+	}
+	return math.Abs(ap.Dot(normal)) / math.Sqrt(area)
+}
+
+// measureSegments 線分の間の距離の計測
+//
+// 引数：
+//
+//	p1, q1：線分1の端点
+//	p2, q2：線分2の端点
+//
+// 戻り値：
+//
+//	線分の間の距離
+func measureSegments(p1, q1, p2, q2 mgl64.Vec3) float64 {
+	d1, d2, r := q1.Sub(p1), q2.Sub(p2), p1.Sub(p2)
+	a, e, f := d1.Dot(d1), d2.Dot(d2), d2.Dot(r)
+	clamp := func(value float64) float64 {
+		return min(max(value, 0), 1)
+	}
+
+	// 線分上の最近点の媒介変数 s, t
+	var s, t float64
+	switch {
+	case a == 0 && e == 0:
+	case a == 0:
+		t = clamp(f / e)
+	default:
+		c := d1.Dot(r)
+		if e == 0 {
+			s = clamp(-c / a)
+			break
+		}
+		b := d1.Dot(d2)
+		if denominator := a*e - b*b; denominator > 0 {
+			s = clamp((b*f - c*e) / denominator)
+		}
+		t = (b*s + f) / e
+		if t < 0 {
+			t, s = 0, clamp(-c/a)
+		} else if t > 1 {
+			t, s = 1, clamp((b-c)/a)
+		}
+	}
+	return p1.Add(d1.Mul(s)).Sub(p2.Add(d2.Mul(t))).Len()
+}
+
+// lowerDistance 範囲の間の距離の下限値の取得
+//
+// 地球を最小の曲率半径の球で近似し、経度、緯度、高さの差から地心直交座標における距離の下限値を求める。
+//
+// 引数：
+//
+//	other：範囲
+//
+// 戻り値：
+//
+//	範囲の間の距離の下限値(単位:m)
+func (b geodeticBox) lowerDistance(other geodeticBox) float64 {
+	gap := func(lower1, upper1, lower2, upper2 float64) float64 {
+		return max(0, lower2-upper1, lower1-upper2)
+	}
+
+	dLon := math.Inf(1)
+	for _, shift := range []float64{-360, 0, 360} {
+		dLon = min(dLon, gap(b.west, b.east, other.west+shift, other.east+shift))
+	}
+	dLon = min(dLon, 180) * math.Pi / 180
+	dLat := gap(b.south, b.north, other.south, other.north) * math.Pi / 180
+	dAlt := gap(b.bottom, b.top, other.bottom, other.top)
+
+	// 中心角の下限値：hav(θ) >= hav(Δφ) + cos²(φmax)hav(Δλ)
+	cosLat := math.Cos(max(math.Abs(b.south), math.Abs(b.north), math.Abs(other.south), math.Abs(other.north)) * math.Pi / 180)
+	sinHalf := math.Sqrt(math.Pow(math.Sin(dLat/2), 2) + math.Pow(cosLat*math.Sin(dLon/2), 2))
+	angle := 2 * math.Asin(min(sinHalf, 1))
+
+	// 地心からの距離の下限値が radius の2点の距離は radius×sin(θ) 以上(θ <= 90度)
+	radius := max(minCurvatureRadius+min(b.bottom, other.bottom), 0)
+	return max(radius*math.Sin(min(angle, math.Pi/2)), dAlt) * lowerDistanceMargin
+}
+
+// Len heap.Interface の実装
+func (q separationQueue) Len() int {
+	return len(q)
+}
+
+// Less heap.Interface の実装
+func (q separationQueue) Less(i, j int) bool {
+	return q[i].lowerDistance < q[j].lowerDistance
+}
+
+// Swap heap.Interface の実装
+func (q separationQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Push heap.Interface の実装
+func (q *separationQueue) Push(x any) {
+	*q = append(*q, x.(separationCell))
+}
+
+// Pop heap.Interface の実装
+func (q *separationQueue) Pop() any {
+	old := *q
+	cell := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return cell
+}
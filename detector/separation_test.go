@@ -0,0 +1,168 @@
+package detector
+
+import (
+	"math"
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// TestGetMinimumDistanceOnExtendedSpatialIds01 2つの拡張空間ID列の最小距離の取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：赤道直下の"20/524288/524287/25/0"と、東に1個離れた"20/524290/524287/25/0"
+//   - パターン2：精度の混在した、離れた拡張空間ID列(全ての組の距離を計測した結果と比較)
+//   - パターン3：精度の異なる重なる拡張空間IDを含む拡張空間ID列
+//   - パターン4：垂直方向精度の粗い細長い拡張空間ID"17/65520/32762/12/6"と、約49km下方の"17/65520/32711/22/-2"
+//
+// + 確認内容
+//   - パターン1：拡張空間ID1個分の幅(約38.2m)の距離が返却されること
+//   - パターン2：全ての組の最小距離と、その組が返却されること
+//   - パターン3：距離0と重なる組が返却されること
+//   - パターン4：高さの差(約49.2km)以上の距離が返却されること
+func TestGetMinimumDistanceOnExtendedSpatialIds01(t *testing.T) {
+	resultVal, result1, result2, resultErr := GetMinimumDistanceOnExtendedSpatialIds([]string{"20/524288/524287/25/0"}, []string{"20/524290/524287/25/0"})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if math.Abs(resultVal-38.2) > 0.1 || result1 != "20/524288/524287/25/0" || result2 != "20/524290/524287/25/0" {
+		t.Errorf("最小距離 - 取得値：%v %v %v", resultVal, result1, result2)
+	}
+
+	ids1 := []string{"20/524288/524287/25/0", "18/131072/131071/23/3", "20/524300/524280/24/-2"}
+	ids2 := []string{"16/32790/32760/20/0", "20/524320/524250/25/40", "22/2097260/2097100/25/5", "19/262160/262150/25/0"}
+	expectVal, expect1, expect2 := math.Inf(1), "", ""
+	for _, id1 := range ids1 {
+		for _, id2 := range ids2 {
+			if distance := measureExtendedSpatialIds(id1, id2); distance < expectVal {
+				expectVal, expect1, expect2 = distance, id1, id2
+			}
+		}
+	}
+	resultVal, result1, result2, resultErr = GetMinimumDistanceOnExtendedSpatialIds(ids1, ids2)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if math.Abs(resultVal-expectVal) > 1e-6 || result1 != expect1 || result2 != expect2 {
+		t.Errorf("最小距離 - 期待値：%v %v %v, 取得値：%v %v %v", expectVal, expect1, expect2, resultVal, result1, result2)
+	}
+
+	resultVal, result1, result2, resultErr = GetMinimumDistanceOnExtendedSpatialIds(ids1, append(ids2, "19/262144/262143/24/0"))
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if resultVal != 0 || result1 != "20/524288/524287/25/0" || result2 != "19/262144/262143/24/0" {
+		t.Errorf("最小距離 - 取得値：%v %v %v", resultVal, result1, result2)
+	}
+
+	resultVal, _, _, resultErr = GetMinimumDistanceOnExtendedSpatialIds([]string{"17/65520/32711/22/-2"}, []string{"17/65520/32762/12/6"})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if resultVal < 49152-8 || resultVal > 50000 {
+		t.Errorf("最小距離 - 取得値：%v", resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetMinimumDistanceOnExtendedSpatialIds02 2つの拡張空間ID列の最小距離の取得関数 空の拡張空間ID列、異常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：空の拡張空間ID列
+//   - パターン2：拡張空間ID列1の拡張空間IDフォーマット不正
+//   - パターン3：拡張空間ID列2の高度範囲外
+//
+// + 確認内容
+//   - パターン1：math.Inf(1)と空文字が返却されること
+//   - パターン2、3：エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetMinimumDistanceOnExtendedSpatialIds02(t *testing.T) {
+	resultVal, result1, result2, resultErr := GetMinimumDistanceOnExtendedSpatialIds([]string{}, []string{"20/524290/524287/25/0"})
+	if resultErr != nil || !math.IsInf(resultVal, 1) || result1 != "" || result2 != "" {
+		t.Errorf("最小距離 - 取得値：%v %v %v %v", resultVal, result1, result2, resultErr)
+	}
+
+	testCases := []struct {
+		ids1      []string
+		ids2      []string
+		expectErr string
+	}{
+		{[]string{"20/524288/524287/25"}, []string{"20/524290/524287/25/0"}, "InputValueError,入力チェックエラー @extendedSpatialIds1[0]"},
+		{[]string{"20/524288/524287/25/0"}, []string{"20/524290/524287/25/0", "20/524290/524287/0/1"}, "InputValueError,入力チェックエラー,input f-index 1 is out of altitude range: 20/524290/524287/0/1 @extendedSpatialIds2[1]"},
+	}
+
+	for _, testCase := range testCases {
+		_, _, _, resultErr := GetMinimumDistanceOnExtendedSpatialIds(testCase.ids1, testCase.ids2)
+		if resultErr == nil || resultErr.Error() != testCase.expectErr {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expectErr, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetMinimumDistanceOnExtendedSpatialIds03 2つの拡張空間ID列の最小距離の取得関数 隣接する高さの拡張空間IDの確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：緯度方向に2個離れ、高さ成分5と6で隣接する"18/131075/131071/25/5"と"18/131075/131068/25/6"
+//   - パターン2：パターン1の高さ成分を-1と0にしたもの
+//   - パターン3：経度方向に1個離れ、高さ成分0と1で隣接する"20/524288/524287/25/0"と"20/524290/524287/25/1"
+//
+// + 確認内容
+//   - パターン1、2：拡張空間ID2個分の高さ(約303.7m)の距離が返却されること
+//   - パターン3：拡張空間ID1個分の幅(約38.2m)の距離が返却されること
+//   - 距離が範囲の間の距離の下限値以上であること
+func TestGetMinimumDistanceOnExtendedSpatialIds03(t *testing.T) {
+	testCases := []struct {
+		id1       string
+		id2       string
+		expectVal float64
+	}{
+		{"18/131075/131071/25/5", "18/131075/131068/25/6", 303.7},
+		{"18/131075/131071/25/-1", "18/131075/131068/25/0", 303.7},
+		{"20/524288/524287/25/0", "20/524290/524287/25/1", 38.2},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, _, _, resultErr := GetMinimumDistanceOnExtendedSpatialIds([]string{testCase.id1}, []string{testCase.id2})
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if math.Abs(resultVal-testCase.expectVal) > 0.1 {
+			t.Errorf("最小距離 %v %v - 期待値：%v, 取得値：%v", testCase.id1, testCase.id2, testCase.expectVal, resultVal)
+		}
+
+		id1, _ := object.NewExtendedSpatialID(testCase.id1)
+		id2, _ := object.NewExtendedSpatialID(testCase.id2)
+		if lowerDistance := getGeodeticBoxOnId(id1).lowerDistance(getGeodeticBoxOnId(id2)); resultVal < lowerDistance {
+			t.Errorf("最小距離 %v %v - 下限値：%v, 取得値：%v", testCase.id1, testCase.id2, lowerDistance, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// measureExtendedSpatialIds 試験用の2つの拡張空間IDの距離の計測
+//
+// 離れた拡張空間IDの組は、closest_go を使用せず全探索で計測する。
+//
+// 引数：
+//
+//	extendedSpatialId1, extendedSpatialId2：拡張空間ID
+//
+// 戻り値：
+//
+//	凸包の間の距離
+func measureExtendedSpatialIds(extendedSpatialId1, extendedSpatialId2 string) float64 {
+	id1, _ := object.NewExtendedSpatialID(extendedSpatialId1)
+	id2, _ := object.NewExtendedSpatialID(extendedSpatialId2)
+
+	box1, box2 := getGeodeticBoxOnId(id1), getGeodeticBoxOnId(id2)
+	if box1.lowerDistance(box2) > 0 {
+		return measureConvexHullsExhaustively(box1.convexHull(), box2.convexHull())
+	}
+	return measureConvexHulls(box1.convexHull(), box2.convexHull())
+}
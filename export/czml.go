@@ -0,0 +1,295 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+)
+
+// CZMLのドキュメントの定数
+const (
+	czmlVersion     = "1.0"
+	czmlDocumentId  = "document"
+	czmlDefaultName = "spatial_id_go"
+)
+
+// CZMLEntry CZML出力対象の構造体
+//
+// 1つの拡張空間IDと、その有効期間、属性を保持する。
+// StartとEndが共にゼロ値の場合は有効期間を設定しない(常に表示される)。
+type CZMLEntry struct {
+	ExtendedSpatialId string         // 拡張空間ID
+	Start             time.Time      // 有効期間の開始時刻
+	End               time.Time      // 有効期間の終了時刻
+	Properties        map[string]any // CZMLのpropertiesに出力する属性
+	Color             *Color         // 色。nilの場合は DefaultColor とする。
+}
+
+// CZMLOptions CZML出力のオプションの構造体
+type CZMLOptions struct {
+	Name      string // ドキュメント名。空文字の場合は"spatial_id_go"とする。
+	ChunkSize int    // 1ドキュメントあたりの最大エントリ数。0以下の場合は分割しない。
+}
+
+// czmlPacket CZMLのパケットの構造体
+type czmlPacket struct {
+	Id           string         `json:"id"`
+	Name         string         `json:"name,omitempty"`
+	Version      string         `json:"version,omitempty"`
+	Clock        *czmlClock     `json:"clock,omitempty"`
+	Availability string         `json:"availability,omitempty"`
+	Properties   map[string]any `json:"properties,omitempty"`
+	Polygon      *czmlPolygon   `json:"polygon,omitempty"`
+}
+
+// czmlClock CZMLの時計
+type czmlClock struct {
+	Interval    string `json:"interval"`
+	CurrentTime string `json:"currentTime"`
+	Multiplier  int    `json:"multiplier"`
+	Range       string `json:"range"`
+	Step        string `json:"step"`
+}
+
+// czmlPolygon CZMLの多角形
+type czmlPolygon struct {
+	Positions      czmlPositions `json:"positions"`
+	Height         float64       `json:"height"`
+	ExtrudedHeight float64       `json:"extrudedHeight"`
+	Material       czmlMaterial  `json:"material"`
+	Outline        bool          `json:"outline"`
+	OutlineColor   czmlColor     `json:"outlineColor"`
+}
+
+// czmlPositions CZMLの座標リスト
+type czmlPositions struct {
+	CartographicDegrees []float64 `json:"cartographicDegrees"`
+}
+
+// czmlMaterial CZMLのマテリアル
+type czmlMaterial struct {
+	SolidColor czmlSolidColor `json:"solidColor"`
+}
+
+// czmlSolidColor CZMLの単色マテリアル
+type czmlSolidColor struct {
+	Color czmlColor `json:"color"`
+}
+
+// czmlColor CZMLの色
+type czmlColor struct {
+	Rgba [4]uint8 `json:"rgba"`
+}
+
+// GetCZMLOnExtendedSpatialIds 拡張空間IDのCZML変換関数
+//
+// 拡張空間IDのボクセルを有効期間付きの箱として、CesiumのCZMLドキュメントに変換する。
+//
+// 各ボクセルは底面の4頂点を水平位置、ボクセルの最低高度を height、最高高度を extrudedHeight とした
+// 押し出し多角形のパケットとして出力する。パケットのidは"拡張空間ID#エントリのインデックス"とし、
+// 同じ拡張空間IDが異なる有効期間で複数回入力された場合も区別できるようにする。
+// ドキュメントの時計の範囲は全エントリの有効期間を包含する範囲とする。
+//
+// ChunkSizeが指定された場合は、エントリを先頭から ChunkSize 件ずつ分割し、
+// それぞれドキュメントパケットを持つ独立したCZMLドキュメントとして返却する。
+//
+// 引数：
+//
+//	entries：出力対象のスライス
+//	options：出力オプション。nilの場合は既定値とする。
+//
+// 戻り値：
+//
+//	CZMLドキュメント(JSON)のバイト列のスライス
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 有効期間不正              ：終了時刻が開始時刻より前の場合、開始時刻と終了時刻の片方のみが指定された場合。
+func GetCZMLOnExtendedSpatialIds(entries []CZMLEntry, options *CZMLOptions) ([][]byte, error) {
+	if options == nil {
+		options = &CZMLOptions{}
+	}
+	name := options.Name
+	if name == "" {
+		name = czmlDefaultName
+	}
+	chunkSize := options.ChunkSize
+	if chunkSize <= 0 || chunkSize > len(entries) {
+		chunkSize = len(entries)
+	}
+
+	// 全エントリをパケットに変換
+	packets := make([]czmlPacket, 0, len(entries))
+	for index, entry := range entries {
+		packet, err := newCZMLPacket(index, entry)
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, packet)
+	}
+
+	// エントリが無い場合もドキュメントパケットのみのドキュメントを1つ返却する
+	documents := [][]byte{}
+	for start := 0; ; start += chunkSize {
+		end := min(start+chunkSize, len(entries))
+
+		document := []czmlPacket{newCZMLDocumentPacket(name, entries[start:end])}
+		document = append(document, packets[start:end]...)
+
+		data, err := json.Marshal(document)
+		if err != nil {
+			return nil, errors.NewSpatialIdError(errors.OtherErrorCode, err.Error())
+		}
+		documents = append(documents, data)
+
+		if end == len(entries) {
+			break
+		}
+	}
+
+	return documents, nil
+}
+
+// newCZMLPacket CZMLパケットの作成
+//
+// 引数：
+//
+//	index：エントリのインデックス
+//	entry：出力対象
+//
+// 戻り値：
+//
+//	CZMLパケット
+//
+// 戻り値(エラー)：
+//
+//	拡張空間ID、有効期間が不正な場合エラーを返却する。
+func newCZMLPacket(index int, entry CZMLEntry) (czmlPacket, error) {
+	voxels, err := newVoxels([]string{entry.ExtendedSpatialId})
+	if err != nil {
+		return czmlPacket{}, err
+	}
+	vertexes := voxels[0].vertexes
+
+	availability, err := getCZMLInterval(entry.Start, entry.End)
+	if err != nil {
+		return czmlPacket{}, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("entries[%v]: %v", index, err))
+	}
+
+	// 底面の4頂点
+	positions := make([]float64, 0, 12)
+	for _, vertex := range vertexes[:4] {
+		positions = append(positions, vertex.Lon(), vertex.Lat(), vertex.Alt())
+	}
+
+	color := DefaultColor
+	if entry.Color != nil {
+		color = *entry.Color
+	}
+	rgba := czmlColor{Rgba: [4]uint8{color.R, color.G, color.B, color.A}}
+
+	return czmlPacket{
+		Id:           fmt.Sprintf("%s#%d", entry.ExtendedSpatialId, index),
+		Name:         entry.ExtendedSpatialId,
+		Availability: availability,
+		Properties:   entry.Properties,
+		Polygon: &czmlPolygon{
+			Positions:      czmlPositions{CartographicDegrees: positions},
+			Height:         vertexes[0].Alt(),
+			ExtrudedHeight: vertexes[4].Alt(),
+			Material:       czmlMaterial{SolidColor: czmlSolidColor{Color: rgba}},
+			Outline:        true,
+			OutlineColor:   czmlColor{Rgba: [4]uint8{color.R, color.G, color.B, 255}},
+		},
+	}, nil
+}
+
+// newCZMLDocumentPacket CZMLドキュメントパケットの作成
+//
+// エントリの有効期間を包含する範囲を時計の範囲とする。
+// 有効期間を持つエントリが無い場合は時計を設定しない。
+//
+// 引数：
+//
+//	name   ：ドキュメント名
+//	entries：ドキュメントに含めるエントリ
+//
+// 戻り値：
+//
+//	ドキュメントパケット
+func newCZMLDocumentPacket(name string, entries []CZMLEntry) czmlPacket {
+	packet := czmlPacket{Id: czmlDocumentId, Name: name, Version: czmlVersion}
+
+	var start, end time.Time
+	for _, entry := range entries {
+		if entry.Start.IsZero() && entry.End.IsZero() {
+			continue
+		}
+		if start.IsZero() || entry.Start.Before(start) {
+			start = entry.Start
+		}
+		if end.IsZero() || entry.End.After(end) {
+			end = entry.End
+		}
+	}
+
+	if !start.IsZero() {
+		interval, _ := getCZMLInterval(start, end)
+		packet.Clock = &czmlClock{
+			Interval:    interval,
+			CurrentTime: formatCZMLTime(start),
+			Multiplier:  1,
+			Range:       "LOOP_STOP",
+			Step:        "SYSTEM_CLOCK_MULTIPLIER",
+		}
+	}
+
+	return packet
+}
+
+// getCZMLInterval CZMLの時間範囲の文字列化
+//
+// 引数：
+//
+//	start：開始時刻
+//	end  ：終了時刻
+//
+// 戻り値：
+//
+//	"開始時刻/終了時刻"形式のISO 8601の時間範囲。開始時刻と終了時刻が共にゼロ値の場合は空文字。
+//
+// 戻り値(エラー)：
+//
+//	終了時刻が開始時刻より前の場合、片方のみがゼロ値の場合エラーを返却する。
+func getCZMLInterval(start, end time.Time) (string, error) {
+	if start.IsZero() && end.IsZero() {
+		return "", nil
+	}
+	if start.IsZero() || end.IsZero() {
+		return "", fmt.Errorf("both start and end are required")
+	}
+	if end.Before(start) {
+		return "", fmt.Errorf("end is before start")
+	}
+
+	return strings.Join([]string{formatCZMLTime(start), formatCZMLTime(end)}, "/"), nil
+}
+
+// formatCZMLTime CZMLの時刻の文字列化
+//
+// 引数：
+//
+//	t：時刻
+//
+// 戻り値：
+//
+//	UTCのISO 8601形式の時刻
+func formatCZMLTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,118 @@
+package export
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestGetCZMLOnExtendedSpatialIds01 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     3エントリ(同じ拡張空間IDを異なる時間帯で2回含む), 分割件数:2
+//
+// + 確認内容
+//   - 2つのCZMLドキュメントに分割され、それぞれ先頭がドキュメントパケットであること
+//   - 各パケットに有効期間、属性、色、高度範囲が設定されること
+//   - ドキュメントの時計の範囲がエントリの有効期間を包含すること
+func TestGetCZMLOnExtendedSpatialIds01(t *testing.T) {
+	base := time.Date(2024, 4, 1, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	red := Color{R: 255, A: 200}
+	entries := []CZMLEntry{
+		{"25/29803148/13212522/25/10", base, base.Add(time.Hour), map[string]any{"reservationId": "A"}, &red},
+		{"25/29803148/13212522/25/10", base.Add(2 * time.Hour), base.Add(3 * time.Hour), nil, nil},
+		{"25/29803149/13212522/25/10", time.Time{}, time.Time{}, nil, nil},
+	}
+
+	resultVal, resultErr := GetCZMLOnExtendedSpatialIds(entries, &CZMLOptions{Name: "reservations", ChunkSize: 2})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if len(resultVal) != 2 {
+		t.Fatalf("ドキュメント数 - 期待値：2, 取得値：%v", len(resultVal))
+	}
+
+	documents := make([][]czmlPacket, len(resultVal))
+	for i, data := range resultVal {
+		if err := json.Unmarshal(data, &documents[i]); err != nil {
+			t.Fatalf("CZML不正：%s", err)
+		}
+	}
+
+	if len(documents[0]) != 3 || len(documents[1]) != 2 {
+		t.Errorf("パケット数 - 期待値：3, 2, 取得値：%v, %v", len(documents[0]), len(documents[1]))
+	}
+	for _, document := range documents {
+		if document[0].Id != czmlDocumentId || document[0].Version != czmlVersion || document[0].Name != "reservations" {
+			t.Errorf("ドキュメントパケット不正：%+v", document[0])
+		}
+	}
+
+	expectInterval := "2024-04-01T00:00:00Z/2024-04-01T03:00:00Z"
+	if documents[0][0].Clock == nil || documents[0][0].Clock.Interval != expectInterval {
+		t.Errorf("時計の範囲 - 期待値：%s, 取得値：%+v", expectInterval, documents[0][0].Clock)
+	}
+	if documents[1][0].Clock != nil {
+		t.Errorf("時計 - 期待値：nil, 取得値：%+v", documents[1][0].Clock)
+	}
+
+	packet := documents[0][1]
+	if packet.Id != "25/29803148/13212522/25/10#0" || documents[0][2].Id != "25/29803148/13212522/25/10#1" {
+		t.Errorf("パケットID不正：%s, %s", packet.Id, documents[0][2].Id)
+	}
+	if packet.Availability != "2024-04-01T00:00:00Z/2024-04-01T01:00:00Z" {
+		t.Errorf("有効期間 - 期待値：2024-04-01T00:00:00Z/2024-04-01T01:00:00Z, 取得値：%s", packet.Availability)
+	}
+	if !reflect.DeepEqual(packet.Properties, map[string]any{"reservationId": "A"}) {
+		t.Errorf("属性 - 期待値：map[reservationId:A], 取得値：%v", packet.Properties)
+	}
+	if packet.Polygon.Material.SolidColor.Color.Rgba != [4]uint8{255, 0, 0, 200} {
+		t.Errorf("色 - 期待値：[255 0 0 200], 取得値：%v", packet.Polygon.Material.SolidColor.Color.Rgba)
+	}
+	if packet.Polygon.Height != 10 || packet.Polygon.ExtrudedHeight != 11 || len(packet.Polygon.Positions.CartographicDegrees) != 12 {
+		t.Errorf("多角形不正：%+v", packet.Polygon)
+	}
+	if documents[1][1].Availability != "" {
+		t.Errorf("有効期間 - 期待値：\"\", 取得値：%s", documents[1][1].Availability)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetCZMLOnExtendedSpatialIds02 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：終了時刻が開始時刻より前
+//   - パターン2：開始時刻のみ指定
+//   - パターン3：拡張空間IDのフォーマット不正
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetCZMLOnExtendedSpatialIds02(t *testing.T) {
+	base := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		entry  CZMLEntry
+		expect string
+	}{
+		{CZMLEntry{ExtendedSpatialId: "25/1/1/25/0", Start: base, End: base.Add(-time.Hour)}, "InputValueError,入力チェックエラー,entries[0]: end is before start"},
+		{CZMLEntry{ExtendedSpatialId: "25/1/1/25/0", Start: base}, "InputValueError,入力チェックエラー,entries[0]: both start and end are required"},
+		{CZMLEntry{ExtendedSpatialId: "25/1/1/25"}, "InputValueError,入力チェックエラー"},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := GetCZMLOnExtendedSpatialIds([]CZMLEntry{testCase.entry}, nil)
+		if resultVal != nil {
+			t.Errorf("CZML - 期待値：nil, 取得値：%v", resultVal)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
@@ -0,0 +1,89 @@
+// Package export 拡張空間IDを可視化用のファイル形式で出力するパッケージ
+package export
+
+import (
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+	"github.com/trajectoryjp/spatial_id_go/v4/shape"
+)
+
+// Color RGBAの色の構造体
+type Color struct {
+	R uint8 // 赤
+	G uint8 // 緑
+	B uint8 // 青
+	A uint8 // 不透明度
+}
+
+// DefaultColor 色が指定されない場合に使用する色(半透明の白)
+var DefaultColor = Color{R: 255, G: 255, B: 255, A: 128}
+
+// ColorFunc 拡張空間IDごとの色の取得関数の型
+type ColorFunc func(extendedSpatialId string) Color
+
+// getColor 色の取得関数
+//
+// 色の取得関数がnilの場合は DefaultColor を返却する。
+//
+// 引数：
+//
+//	colorFunc        ：色の取得関数
+//	extendedSpatialId：拡張空間ID
+//
+// 戻り値：
+//
+//	色
+func getColor(colorFunc ColorFunc, extendedSpatialId string) Color {
+	if colorFunc == nil {
+		return DefaultColor
+	}
+	return colorFunc(extendedSpatialId)
+}
+
+// voxel 出力対象のボクセルの構造体
+type voxel struct {
+	id        string                    // 入力された拡張空間ID
+	spatialId *object.ExtendedSpatialID // 拡張空間IDオブジェクト
+	vertexes  []*object.Point           // 8頂点(北西、北東、南東、南西の順に底面→上面)
+}
+
+// newVoxels ボクセル変換関数
+//
+// 重複した拡張空間IDを除去し、入力順にボクセルに変換する。
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間IDのスライス
+//
+// 戻り値：
+//
+//	ボクセルのスライス
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+func newVoxels(extendedSpatialIds []string) ([]*voxel, error) {
+	voxels := make([]*voxel, 0, len(extendedSpatialIds))
+	seen := map[string]struct{}{}
+
+	for _, extendedSpatialId := range extendedSpatialIds {
+		if _, ok := seen[extendedSpatialId]; ok {
+			continue
+		}
+		seen[extendedSpatialId] = struct{}{}
+
+		vertexes, err := shape.GetPointOnExtendedSpatialId(extendedSpatialId, enum.Vertex)
+		if err != nil {
+			return nil, err
+		}
+		spatialId, err := object.NewExtendedSpatialID(extendedSpatialId)
+		if err != nil {
+			return nil, err
+		}
+		voxels = append(voxels, &voxel{id: extendedSpatialId, spatialId: spatialId, vertexes: vertexes})
+	}
+
+	return voxels, nil
+}
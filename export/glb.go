@@ -0,0 +1,500 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/trajectoryjp/spatial_id_go/v4/common"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+
+	geodesy "github.com/trajectoryjp/geodesy_go/coordinates"
+)
+
+// CoordinateSystem 出力座標系用の型
+type CoordinateSystem int
+
+// GLBの出力座標系
+const (
+	ECEF CoordinateSystem = iota // 地心直交座標系(0)
+	ENU                          // 原点を基準とした東・北・上の局所直交座標系(1)
+)
+
+// GLBのチャンク、glTFの定数
+const (
+	glbMagic            = 0x46546C67 // "glTF"
+	glbVersion          = 2
+	glbChunkJSON        = 0x4E4F534A // "JSON"
+	glbChunkBIN         = 0x004E4942 // "BIN\0"
+	gltfArrayBuffer     = 34962
+	gltfElementBuffer   = 34963
+	gltfFloat           = 5126
+	gltfUnsignedByte    = 5121
+	gltfUnsignedInt     = 5125
+	gltfTriangles       = 4
+	gltfGenerator       = "spatial_id_go"
+	gltfAlphaModeOpaque = "OPAQUE"
+	gltfAlphaModeBlend  = "BLEND"
+)
+
+// GLBOptions GLB出力のオプションの構造体
+type GLBOptions struct {
+	CoordinateSystem CoordinateSystem // 出力座標系
+	Origin           *object.Point    // ENUの原点。nilの場合は全ボクセルの外接範囲の底面中心とする。
+	Color            ColorFunc        // ボクセルごとの色の取得関数。nilの場合は DefaultColor とする。
+}
+
+// glbFace ボクセルの面の定義
+type glbFace struct {
+	vertexes [4]int   // 頂点のインデックス
+	neighbor [3]int64 // 面を共有する隣接ボクセルの(x, y, z)方向の差分
+}
+
+// glbFaces ボクセルの6面
+//
+// 頂点の並び順は北西、北東、南東、南西の順に底面4頂点→上面4頂点。
+var glbFaces = []glbFace{
+	{[4]int{0, 1, 2, 3}, [3]int64{0, 0, -1}}, // 底面
+	{[4]int{4, 5, 6, 7}, [3]int64{0, 0, 1}},  // 上面
+	{[4]int{0, 1, 5, 4}, [3]int64{0, -1, 0}}, // 北面
+	{[4]int{1, 2, 6, 5}, [3]int64{1, 0, 0}},  // 東面
+	{[4]int{2, 3, 7, 6}, [3]int64{0, 1, 0}},  // 南面
+	{[4]int{3, 0, 4, 7}, [3]int64{-1, 0, 0}}, // 西面
+}
+
+// gltfDocument glTFのJSONの構造体
+type gltfDocument struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       int              `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes"`
+	Materials   []gltfMaterial   `json:"materials"`
+	Buffers     []gltfBuffer     `json:"buffers"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Accessors   []gltfAccessor   `json:"accessors"`
+}
+
+// gltfAsset glTFのアセット情報
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator"`
+}
+
+// gltfScene glTFのシーン
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+// gltfNode glTFのノード
+type gltfNode struct {
+	Mesh        int        `json:"mesh"`
+	Translation [3]float64 `json:"translation"`
+}
+
+// gltfMesh glTFのメッシュ
+type gltfMesh struct {
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+// gltfPrimitive glTFのプリミティブ
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Material   int            `json:"material"`
+	Mode       int            `json:"mode"`
+}
+
+// gltfMaterial glTFのマテリアル
+type gltfMaterial struct {
+	PbrMetallicRoughness gltfPbr `json:"pbrMetallicRoughness"`
+	AlphaMode            string  `json:"alphaMode"`
+	DoubleSided          bool    `json:"doubleSided"`
+}
+
+// gltfPbr glTFのPBRパラメータ
+type gltfPbr struct {
+	BaseColorFactor [4]float64 `json:"baseColorFactor"`
+	MetallicFactor  float64    `json:"metallicFactor"`
+	RoughnessFactor float64    `json:"roughnessFactor"`
+}
+
+// gltfBuffer glTFのバッファ
+type gltfBuffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+// gltfBufferView glTFのバッファビュー
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target"`
+}
+
+// gltfAccessor glTFのアクセサ
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Normalized    bool      `json:"normalized,omitempty"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float64 `json:"min,omitempty"`
+	Max           []float64 `json:"max,omitempty"`
+}
+
+// GetGLBOnExtendedSpatialIds 拡張空間IDのGLB変換関数
+//
+// 拡張空間IDのボクセルを直方体のメッシュとし、1つのGLB(glTF 2.0のバイナリ形式)に変換する。
+//
+// 頂点座標はECEF、またはENUで計算し、glTFの規約(Y軸が上)に合わせて(X, Y, Z)を(X, Z, -Y)に変換して格納する。
+// CesiumJS等でZ軸を上とする座標系に戻すと、元のECEF、ENUの座標となる。
+// 単精度浮動小数点数の精度を確保するため、頂点座標は外接範囲の中心からの相対座標とし、
+// 中心の座標はノードの平行移動量として格納する。
+//
+// 同じ精度で隣接するボクセル間の共有面は出力しない。
+// 精度の異なるボクセル間の共有面は除去しない。
+// 色はボクセルごとに頂点カラー(COLOR_0)として格納し、半透明の色が含まれる場合はアルファブレンドを有効にする。
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間IDのスライス
+//	options           ：出力オプション。nilの場合は既定値(ECEF、DefaultColor)とする。
+//
+// 戻り値：
+//
+//	GLBのバイト列
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 入力値不正                ：拡張空間IDが空の場合、座標系が未対応の場合。
+func GetGLBOnExtendedSpatialIds(extendedSpatialIds []string, options *GLBOptions) ([]byte, error) {
+	if options == nil {
+		options = &GLBOptions{}
+	}
+	if len(extendedSpatialIds) == 0 {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "extendedSpatialIds is empty")
+	}
+	if options.CoordinateSystem != ECEF && options.CoordinateSystem != ENU {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "unsupported coordinate system")
+	}
+
+	voxels, err := newVoxels(extendedSpatialIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// 頂点座標を出力座標系に変換
+	toLocal := getGLBCoordinateConverter(voxels, options)
+	corners := make([][]mgl64.Vec3, len(voxels))
+	for i, v := range voxels {
+		corners[i] = make([]mgl64.Vec3, len(v.vertexes))
+		for j, vertex := range v.vertexes {
+			corners[i][j] = toLocal(vertex)
+		}
+	}
+	center := getGLBCenter(corners)
+
+	// 隣接判定用の拡張空間ID
+	occupied := map[[5]int64]struct{}{}
+	for _, v := range voxels {
+		occupied[[5]int64(v.spatialId.FieldParams())] = struct{}{}
+	}
+
+	positions := []float32{}
+	normals := []float32{}
+	colors := []uint8{}
+	indices := []uint32{}
+	isOpaque := true
+
+	for i, v := range voxels {
+		color := getColor(options.Color, v.id)
+		isOpaque = isOpaque && color.A == 255
+		voxelCenter := getGLBCenter(corners[i : i+1])
+
+		for _, face := range glbFaces {
+			if hasGLBNeighbor(occupied, v.spatialId, face.neighbor) {
+				continue
+			}
+
+			quad := [4]mgl64.Vec3{}
+			for j, index := range face.vertexes {
+				quad[j] = corners[i][index]
+			}
+
+			// 外向きの法線となるよう頂点順を揃える
+			normal := quad[2].Sub(quad[0]).Cross(quad[3].Sub(quad[1]))
+			faceCenter := quad[0].Add(quad[1]).Add(quad[2]).Add(quad[3]).Mul(0.25)
+			if normal.Dot(faceCenter.Sub(voxelCenter)) < 0 {
+				quad[1], quad[3] = quad[3], quad[1]
+				normal = normal.Mul(-1)
+			}
+			if normal.Len() > 0 {
+				normal = normal.Normalize()
+			}
+
+			base := uint32(len(positions) / 3)
+			for _, p := range quad {
+				positions = appendGLBVec3(positions, p.Sub(center))
+				normals = appendGLBVec3(normals, normal)
+				colors = append(colors, color.R, color.G, color.B, color.A)
+			}
+			indices = append(indices, base, base+1, base+2, base, base+2, base+3)
+		}
+	}
+
+	return encodeGLB(positions, normals, colors, indices, toGLTFAxes(center), isOpaque)
+}
+
+// getGLBCoordinateConverter 出力座標系への変換関数の取得
+//
+// 引数：
+//
+//	voxels ：ボクセルのスライス
+//	options：出力オプション
+//
+// 戻り値：
+//
+//	地理座標を出力座標系の座標に変換する関数
+func getGLBCoordinateConverter(voxels []*voxel, options *GLBOptions) func(p *object.Point) mgl64.Vec3 {
+	toECEF := func(p *object.Point) mgl64.Vec3 {
+		return mgl64.Vec3(geodesy.GeocentricFromGeodetic(geodesy.Geodetic{p.Lon(), p.Lat(), p.Alt()}))
+	}
+	if options.CoordinateSystem == ECEF {
+		return toECEF
+	}
+
+	origin := options.Origin
+	if origin == nil {
+		origin = getBottomCenterOnVoxels(voxels)
+	}
+
+	// ECEFからENUへの回転行列
+	lon := common.DegreeToRadian(origin.Lon())
+	lat := common.DegreeToRadian(origin.Lat())
+	rotation := mgl64.Mat3FromRows(
+		mgl64.Vec3{-math.Sin(lon), math.Cos(lon), 0},
+		mgl64.Vec3{-math.Sin(lat) * math.Cos(lon), -math.Sin(lat) * math.Sin(lon), math.Cos(lat)},
+		mgl64.Vec3{math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)},
+	)
+	originECEF := toECEF(origin)
+
+	return func(p *object.Point) mgl64.Vec3 {
+		return rotation.Mul3x1(toECEF(p).Sub(originECEF))
+	}
+}
+
+// getBottomCenterOnVoxels ボクセルの外接範囲の底面中心の取得
+//
+// 引数：
+//
+//	voxels：ボクセルのスライス
+//
+// 戻り値：
+//
+//	外接範囲の経度、緯度の中心と最低高度の地理座標
+func getBottomCenterOnVoxels(voxels []*voxel) *object.Point {
+	minLon, minLat, minAlt := math.Inf(1), math.Inf(1), math.Inf(1)
+	maxLon, maxLat := math.Inf(-1), math.Inf(-1)
+
+	for _, v := range voxels {
+		for _, vertex := range v.vertexes {
+			minLon, maxLon = math.Min(minLon, vertex.Lon()), math.Max(maxLon, vertex.Lon())
+			minLat, maxLat = math.Min(minLat, vertex.Lat()), math.Max(maxLat, vertex.Lat())
+			minAlt = math.Min(minAlt, vertex.Alt())
+		}
+	}
+
+	center, _ := object.NewPoint((minLon+maxLon)/2, (minLat+maxLat)/2, minAlt)
+	return center
+}
+
+// getGLBCenter 外接範囲の中心の取得
+//
+// 引数：
+//
+//	corners：ボクセルごとの頂点座標
+//
+// 戻り値：
+//
+//	外接範囲の中心座標
+func getGLBCenter(corners [][]mgl64.Vec3) mgl64.Vec3 {
+	minimum := mgl64.Vec3{math.Inf(1), math.Inf(1), math.Inf(1)}
+	maximum := mgl64.Vec3{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+
+	for _, voxelCorners := range corners {
+		for _, p := range voxelCorners {
+			for axis := 0; axis < 3; axis++ {
+				minimum[axis] = math.Min(minimum[axis], p[axis])
+				maximum[axis] = math.Max(maximum[axis], p[axis])
+			}
+		}
+	}
+
+	return minimum.Add(maximum).Mul(0.5)
+}
+
+// hasGLBNeighbor 隣接ボクセルの有無の判定
+//
+// 経度方向は経度180度の境界で循環させる。
+//
+// 引数：
+//
+//	occupied ：出力対象の拡張空間IDの集合
+//	spatialId：判定対象の拡張空間ID
+//	offset   ：隣接ボクセルの(x, y, z)方向の差分
+//
+// 戻り値：
+//
+//	同じ精度の隣接ボクセルが出力対象に含まれる場合true
+func hasGLBNeighbor(occupied map[[5]int64]struct{}, spatialId *object.ExtendedSpatialID, offset [3]int64) bool {
+	maxIndex := int64(1) << spatialId.HZoom()
+	x := ((spatialId.X()+offset[0])%maxIndex + maxIndex) % maxIndex
+
+	_, ok := occupied[[5]int64{spatialId.HZoom(), x, spatialId.Y() + offset[1], spatialId.VZoom(), spatialId.Z() + offset[2]}]
+	return ok
+}
+
+// toGLTFAxes glTFの座標軸への変換
+//
+// Z軸が上の座標(X, Y, Z)を、Y軸が上のglTFの座標(X, Z, -Y)に変換する。
+//
+// 引数：
+//
+//	v：Z軸が上の座標
+//
+// 戻り値：
+//
+//	glTFの座標
+func toGLTFAxes(v mgl64.Vec3) [3]float64 {
+	return [3]float64{v.X(), v.Z(), -v.Y()}
+}
+
+// appendGLBVec3 座標の単精度浮動小数点数での追加
+//
+// 引数：
+//
+//	values：追加先のスライス
+//	v     ：Z軸が上の座標
+//
+// 戻り値：
+//
+//	glTFの座標軸に変換した座標を追加したスライス
+func appendGLBVec3(values []float32, v mgl64.Vec3) []float32 {
+	axes := toGLTFAxes(v)
+	return append(values, float32(axes[0]), float32(axes[1]), float32(axes[2]))
+}
+
+// encodeGLB GLBの組み立て
+//
+// 引数：
+//
+//	positions  ：頂点座標
+//	normals    ：法線
+//	colors     ：頂点カラー
+//	indices    ：三角形の頂点インデックス
+//	translation：ノードの平行移動量
+//	isOpaque   ：全ての色が不透明であるか
+//
+// 戻り値：
+//
+//	GLBのバイト列
+//
+// 戻り値(エラー)：
+//
+//	JSONの変換に失敗した場合エラーを返却する。
+func encodeGLB(
+	positions, normals []float32,
+	colors []uint8,
+	indices []uint32,
+	translation [3]float64,
+	isOpaque bool,
+) ([]byte, error) {
+	// バイナリチャンク
+	bin := &bytes.Buffer{}
+	views := []gltfBufferView{}
+	for _, section := range []struct {
+		data   any
+		target int
+	}{
+		{positions, gltfArrayBuffer},
+		{normals, gltfArrayBuffer},
+		{colors, gltfArrayBuffer},
+		{indices, gltfElementBuffer},
+	} {
+		offset := bin.Len()
+		if err := binary.Write(bin, binary.LittleEndian, section.data); err != nil {
+			return nil, errors.NewSpatialIdError(errors.OtherErrorCode, err.Error())
+		}
+		views = append(views, gltfBufferView{Buffer: 0, ByteOffset: offset, ByteLength: bin.Len() - offset, Target: section.target})
+	}
+
+	// 頂点座標の範囲
+	minimum := []float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	maximum := []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for i, value := range positions {
+		minimum[i%3] = math.Min(minimum[i%3], float64(value))
+		maximum[i%3] = math.Max(maximum[i%3], float64(value))
+	}
+
+	alphaMode := gltfAlphaModeBlend
+	if isOpaque {
+		alphaMode = gltfAlphaModeOpaque
+	}
+
+	vertexCount := len(positions) / 3
+	document := gltfDocument{
+		Asset:  gltfAsset{Version: "2.0", Generator: gltfGenerator},
+		Scene:  0,
+		Scenes: []gltfScene{{Nodes: []int{0}}},
+		Nodes:  []gltfNode{{Mesh: 0, Translation: translation}},
+		Meshes: []gltfMesh{{Primitives: []gltfPrimitive{{
+			Attributes: map[string]int{"POSITION": 0, "NORMAL": 1, "COLOR_0": 2},
+			Indices:    3,
+			Material:   0,
+			Mode:       gltfTriangles,
+		}}}},
+		Materials: []gltfMaterial{{
+			PbrMetallicRoughness: gltfPbr{BaseColorFactor: [4]float64{1, 1, 1, 1}, MetallicFactor: 0, RoughnessFactor: 1},
+			AlphaMode:            alphaMode,
+		}},
+		Buffers:     []gltfBuffer{{ByteLength: bin.Len()}},
+		BufferViews: views,
+		Accessors: []gltfAccessor{
+			{BufferView: 0, ComponentType: gltfFloat, Count: vertexCount, Type: "VEC3", Min: minimum, Max: maximum},
+			{BufferView: 1, ComponentType: gltfFloat, Count: vertexCount, Type: "VEC3"},
+			{BufferView: 2, ComponentType: gltfUnsignedByte, Normalized: true, Count: vertexCount, Type: "VEC4"},
+			{BufferView: 3, ComponentType: gltfUnsignedInt, Count: len(indices), Type: "SCALAR"},
+		},
+	}
+
+	jsonChunk, err := json.Marshal(document)
+	if err != nil {
+		return nil, errors.NewSpatialIdError(errors.OtherErrorCode, err.Error())
+	}
+
+	// チャンクは4バイト境界に揃える(JSONは空白、バイナリは0で埋める)
+	for len(jsonChunk)%4 != 0 {
+		jsonChunk = append(jsonChunk, ' ')
+	}
+	binChunk := bin.Bytes()
+	for len(binChunk)%4 != 0 {
+		binChunk = append(binChunk, 0)
+	}
+
+	glb := &bytes.Buffer{}
+	header := []uint32{glbMagic, glbVersion, uint32(12 + 8 + len(jsonChunk) + 8 + len(binChunk))}
+	binary.Write(glb, binary.LittleEndian, header)
+	binary.Write(glb, binary.LittleEndian, []uint32{uint32(len(jsonChunk)), glbChunkJSON})
+	glb.Write(jsonChunk)
+	binary.Write(glb, binary.LittleEndian, []uint32{uint32(len(binChunk)), glbChunkBIN})
+	glb.Write(binChunk)
+
+	return glb.Bytes(), nil
+}
@@ -0,0 +1,153 @@
+package export
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// decodeGLB GLBのJSONチャンクとバイナリチャンクの読み込み
+func decodeGLB(t *testing.T, glb []byte) (*gltfDocument, []byte) {
+	t.Helper()
+
+	if binary.LittleEndian.Uint32(glb[0:]) != glbMagic || binary.LittleEndian.Uint32(glb[4:]) != glbVersion {
+		t.Fatalf("GLBヘッダ不正：%v", glb[:8])
+	}
+	if int(binary.LittleEndian.Uint32(glb[8:])) != len(glb) {
+		t.Fatalf("GLB長 - 期待値：%v, 取得値：%v", len(glb), binary.LittleEndian.Uint32(glb[8:]))
+	}
+
+	jsonLength := int(binary.LittleEndian.Uint32(glb[12:]))
+	document := &gltfDocument{}
+	if err := json.Unmarshal(glb[20:20+jsonLength], document); err != nil {
+		t.Fatalf("JSONチャンク不正：%s", err)
+	}
+	binLength := int(binary.LittleEndian.Uint32(glb[20+jsonLength:]))
+
+	return document, glb[28+jsonLength : 28+jsonLength+binLength]
+}
+
+// TestGetGLBOnExtendedSpatialIds01 正常系動作確認(ECEF)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：東西に隣接する2ボクセル 25/29803148/13212522/25/0, 25/29803149/13212522/25/0
+//
+// + 確認内容
+//   - 共有面を除いた10面(40頂点、60インデックス)が出力されること
+//   - 色が頂点カラーとして格納され、不透明の場合はOPAQUEとなること
+//   - 頂点座標の範囲がボクセルの大きさ程度であること
+func TestGetGLBOnExtendedSpatialIds01(t *testing.T) {
+	ids := []string{"25/29803148/13212522/25/0", "25/29803149/13212522/25/0"}
+	red := Color{R: 255, A: 255}
+
+	resultVal, resultErr := GetGLBOnExtendedSpatialIds(ids, &GLBOptions{
+		Color: func(string) Color { return red },
+	})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	document, bin := decodeGLB(t, resultVal)
+
+	if document.Accessors[0].Count != 40 || document.Accessors[3].Count != 60 {
+		t.Errorf("頂点数, インデックス数 - 期待値：40, 60, 取得値：%v, %v", document.Accessors[0].Count, document.Accessors[3].Count)
+	}
+	if document.Materials[0].AlphaMode != gltfAlphaModeOpaque {
+		t.Errorf("alphaMode - 期待値：%s, 取得値：%s", gltfAlphaModeOpaque, document.Materials[0].AlphaMode)
+	}
+
+	colorView := document.BufferViews[2]
+	color := bin[colorView.ByteOffset : colorView.ByteOffset+4]
+	if color[0] != 255 || color[1] != 0 || color[2] != 0 || color[3] != 255 {
+		t.Errorf("頂点カラー - 期待値：[255 0 0 255], 取得値：%v", color)
+	}
+
+	for axis := 0; axis < 3; axis++ {
+		size := document.Accessors[0].Max[axis] - document.Accessors[0].Min[axis]
+		if size <= 0 || size > 3 {
+			t.Errorf("頂点座標の範囲 - 期待値：0m～3m, 取得値：%v", size)
+		}
+	}
+	if math.Abs(document.Nodes[0].Translation[0]) < 1e6 {
+		t.Errorf("平行移動量 - 期待値：ECEF座標, 取得値：%v", document.Nodes[0].Translation)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetGLBOnExtendedSpatialIds02 正常系動作確認(ENU)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：ボクセル 25/29803148/13212522/25/10, 原点：ボクセル底面の北西端
+//
+// + 確認内容
+//   - 6面(24頂点、36インデックス)が出力されること
+//   - 半透明の既定色の場合はBLENDとなること
+//   - 頂点座標がENUで原点の東、南、上方向(glTFの+X, +Z, +Y)に位置すること
+func TestGetGLBOnExtendedSpatialIds02(t *testing.T) {
+	id := "25/29803148/13212522/25/10"
+	voxels, _ := newVoxels([]string{id})
+	origin := voxels[0].vertexes[0]
+
+	resultVal, resultErr := GetGLBOnExtendedSpatialIds([]string{id, id}, &GLBOptions{CoordinateSystem: ENU, Origin: origin})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	document, _ := decodeGLB(t, resultVal)
+
+	if document.Accessors[0].Count != 24 || document.Accessors[3].Count != 36 {
+		t.Errorf("頂点数, インデックス数 - 期待値：24, 36, 取得値：%v, %v", document.Accessors[0].Count, document.Accessors[3].Count)
+	}
+	if document.Materials[0].AlphaMode != gltfAlphaModeBlend {
+		t.Errorf("alphaMode - 期待値：%s, 取得値：%s", gltfAlphaModeBlend, document.Materials[0].AlphaMode)
+	}
+
+	translation := document.Nodes[0].Translation
+	if translation[0] <= 0 || translation[1] <= 0 || translation[2] <= 0 {
+		t.Errorf("平行移動量 - 期待値：東、上、南方向が正, 取得値：%v", translation)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetGLBOnExtendedSpatialIds03 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：拡張空間IDが空
+//   - パターン2：拡張空間IDのフォーマット不正
+//   - パターン3：未対応の座標系
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetGLBOnExtendedSpatialIds03(t *testing.T) {
+	origin, _ := object.NewPoint(0, 0, 0)
+
+	testCases := []struct {
+		ids     []string
+		options *GLBOptions
+		expect  string
+	}{
+		{[]string{}, nil, "InputValueError,入力チェックエラー,extendedSpatialIds is empty"},
+		{[]string{"25/1/1/25"}, nil, "InputValueError,入力チェックエラー"},
+		{[]string{"25/1/1/25/0"}, &GLBOptions{CoordinateSystem: 2, Origin: origin}, "InputValueError,入力チェックエラー,unsupported coordinate system"},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := GetGLBOnExtendedSpatialIds(testCase.ids, testCase.options)
+		if resultVal != nil {
+			t.Errorf("GLB - 期待値：nil, 取得値：%v", resultVal)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
@@ -0,0 +1,271 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+)
+
+// KMLの定数
+const (
+	kmlNamespace       = "http://www.opengis.net/kml/2.2"
+	kmlDefaultName     = "spatial_id_go"
+	kmlAltitudeMode    = "absolute"
+	kmzDocumentName    = "doc.kml"
+	kmlStyleIdPrefix   = "category-"
+	kmlOutlineWidth    = 1.0
+	kmlDefaultCategory = ""
+)
+
+// KMLGrouping KMLのフォルダ分け方法用の型
+type KMLGrouping int
+
+// KMLのフォルダ分け方法
+const (
+	GroupByZoom     KMLGrouping = iota // 水平方向精度、垂直方向精度ごと(0)
+	GroupByCategory                    // 分類ごと(1)
+)
+
+// KMLOptions KML出力のオプションの構造体
+type KMLOptions struct {
+	Name          string                                // ドキュメント名。空文字の場合は"spatial_id_go"とする。
+	Grouping      KMLGrouping                           // フォルダ分け方法
+	Category      func(extendedSpatialId string) string // 拡張空間IDの分類の取得関数。nilの場合は全て同じ分類とする。
+	CategoryColor func(category string) Color           // 分類ごとの色の取得関数。nilの場合は DefaultColor とする。
+}
+
+// kmlDocument KMLのルート要素
+type kmlDocument struct {
+	XMLName  xml.Name   `xml:"kml"`
+	Xmlns    string     `xml:"xmlns,attr"`
+	Document kmlDocBody `xml:"Document"`
+}
+
+// kmlDocBody KMLのDocument要素
+type kmlDocBody struct {
+	Name    string      `xml:"name"`
+	Styles  []kmlStyle  `xml:"Style"`
+	Folders []kmlFolder `xml:"Folder"`
+}
+
+// kmlStyle KMLのスタイル
+type kmlStyle struct {
+	Id        string       `xml:"id,attr"`
+	LineStyle kmlLineStyle `xml:"LineStyle"`
+	PolyStyle kmlPolyStyle `xml:"PolyStyle"`
+}
+
+// kmlLineStyle KMLの線のスタイル
+type kmlLineStyle struct {
+	Color string  `xml:"color"`
+	Width float64 `xml:"width"`
+}
+
+// kmlPolyStyle KMLの面のスタイル
+type kmlPolyStyle struct {
+	Color string `xml:"color"`
+}
+
+// kmlFolder KMLのフォルダ
+type kmlFolder struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+// kmlPlacemark KMLのプレースマーク
+type kmlPlacemark struct {
+	Name     string     `xml:"name"`
+	StyleUrl string     `xml:"styleUrl"`
+	Polygon  kmlPolygon `xml:"Polygon"`
+}
+
+// kmlPolygon KMLの多角形
+type kmlPolygon struct {
+	Extrude      int    `xml:"extrude"`
+	AltitudeMode string `xml:"altitudeMode"`
+	Coordinates  string `xml:"outerBoundaryIs>LinearRing>coordinates"`
+}
+
+// GetKMLOnExtendedSpatialIds 拡張空間IDのKML変換関数
+//
+// 拡張空間IDのボクセルを、Google Earth等で表示可能なKMLに変換する。
+//
+// 各ボクセルはボクセル上面を absolute の高度モードで配置し、押し出し(extrude)を有効にした多角形として出力する。
+// KMLの仕様上、押し出しは多角形から地表までとなる。
+// プレースマークは水平方向精度、垂直方向精度ごと、または分類ごとのフォルダにまとめ、
+// 分類ごとに KMLOptions.CategoryColor の色のスタイルを定義する。
+// フォルダ、スタイルは名前の昇順、フォルダ内のプレースマークは入力順に出力する。
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間IDのスライス。重複は除去される。
+//	options           ：出力オプション。nilの場合は既定値とする。
+//
+// 戻り値：
+//
+//	KMLのバイト列
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 入力値不正                ：フォルダ分け方法が未対応の場合。
+func GetKMLOnExtendedSpatialIds(extendedSpatialIds []string, options *KMLOptions) ([]byte, error) {
+	if options == nil {
+		options = &KMLOptions{}
+	}
+	if options.Grouping != GroupByZoom && options.Grouping != GroupByCategory {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "unsupported grouping")
+	}
+	name := options.Name
+	if name == "" {
+		name = kmlDefaultName
+	}
+
+	voxels, err := newVoxels(extendedSpatialIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分類ごとのスタイルの定義
+	categories := make([]string, len(voxels))
+	styleIds := map[string]string{}
+	for i, v := range voxels {
+		categories[i] = kmlDefaultCategory
+		if options.Category != nil {
+			categories[i] = options.Category(v.id)
+		}
+		styleIds[categories[i]] = ""
+	}
+
+	styleNames := make([]string, 0, len(styleIds))
+	for category := range styleIds {
+		styleNames = append(styleNames, category)
+	}
+	sort.Strings(styleNames)
+
+	styles := make([]kmlStyle, 0, len(styleNames))
+	for index, category := range styleNames {
+		styleIds[category] = kmlStyleIdPrefix + strconv.Itoa(index)
+		color := DefaultColor
+		if options.CategoryColor != nil {
+			color = options.CategoryColor(category)
+		}
+		styles = append(styles, kmlStyle{
+			Id:        styleIds[category],
+			LineStyle: kmlLineStyle{Color: formatKMLColor(Color{color.R, color.G, color.B, 255}), Width: kmlOutlineWidth},
+			PolyStyle: kmlPolyStyle{Color: formatKMLColor(color)},
+		})
+	}
+
+	// フォルダへの振り分け
+	folders := map[string]*kmlFolder{}
+	for i, v := range voxels {
+		folderName := categories[i]
+		if options.Grouping == GroupByZoom {
+			folderName = fmt.Sprintf("zoom %v/%v", v.spatialId.HZoom(), v.spatialId.VZoom())
+		}
+		folder, ok := folders[folderName]
+		if !ok {
+			folder = &kmlFolder{Name: folderName}
+			folders[folderName] = folder
+		}
+
+		// 上面の4頂点を閉じた環とする
+		top := v.vertexes[4:]
+		coordinates := make([]string, 0, len(top)+1)
+		for _, vertex := range append(top, top[0]) {
+			coordinates = append(coordinates, strings.Join([]string{
+				strconv.FormatFloat(vertex.Lon(), 'f', -1, 64),
+				strconv.FormatFloat(vertex.Lat(), 'f', -1, 64),
+				strconv.FormatFloat(vertex.Alt(), 'f', -1, 64),
+			}, ","))
+		}
+
+		folder.Placemarks = append(folder.Placemarks, kmlPlacemark{
+			Name:     v.id,
+			StyleUrl: "#" + styleIds[categories[i]],
+			Polygon: kmlPolygon{
+				Extrude:      1,
+				AltitudeMode: kmlAltitudeMode,
+				Coordinates:  strings.Join(coordinates, " "),
+			},
+		})
+	}
+
+	folderNames := make([]string, 0, len(folders))
+	for folderName := range folders {
+		folderNames = append(folderNames, folderName)
+	}
+	sort.Strings(folderNames)
+
+	document := kmlDocument{Xmlns: kmlNamespace, Document: kmlDocBody{Name: name, Styles: styles}}
+	for _, folderName := range folderNames {
+		document.Document.Folders = append(document.Document.Folders, *folders[folderName])
+	}
+
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, errors.NewSpatialIdError(errors.OtherErrorCode, err.Error())
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// GetKMZOnExtendedSpatialIds 拡張空間IDのKMZ変換関数
+//
+// GetKMLOnExtendedSpatialIds で変換したKMLを"doc.kml"としてZIP圧縮したKMZに変換する。
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間IDのスライス。重複は除去される。
+//	options           ：出力オプション。nilの場合は既定値とする。
+//
+// 戻り値：
+//
+//	KMZのバイト列
+//
+// 戻り値(エラー)：
+//
+//	GetKMLOnExtendedSpatialIds と同じ条件でエラーインスタンスが返却される。
+func GetKMZOnExtendedSpatialIds(extendedSpatialIds []string, options *KMLOptions) ([]byte, error) {
+	kml, err := GetKMLOnExtendedSpatialIds(extendedSpatialIds, options)
+	if err != nil {
+		return nil, err
+	}
+
+	kmz := &bytes.Buffer{}
+	archive := zip.NewWriter(kmz)
+	writer, err := archive.Create(kmzDocumentName)
+	if err != nil {
+		return nil, errors.NewSpatialIdError(errors.OtherErrorCode, err.Error())
+	}
+	if _, err := writer.Write(kml); err != nil {
+		return nil, errors.NewSpatialIdError(errors.OtherErrorCode, err.Error())
+	}
+	if err := archive.Close(); err != nil {
+		return nil, errors.NewSpatialIdError(errors.OtherErrorCode, err.Error())
+	}
+
+	return kmz.Bytes(), nil
+}
+
+// formatKMLColor KMLの色の文字列化
+//
+// 引数：
+//
+//	color：色
+//
+// 戻り値：
+//
+//	KMLの"aabbggrr"形式の16進数文字列
+func formatKMLColor(color Color) string {
+	return fmt.Sprintf("%02x%02x%02x%02x", color.A, color.B, color.G, color.R)
+}
@@ -0,0 +1,145 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestGetKMLOnExtendedSpatialIds01 正常系動作確認(分類ごと)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     3つの拡張空間ID(精度の異なるIDを含む), 分類：精度25は"reserved"、それ以外は"track"
+//
+// + 確認内容
+//   - 分類ごとのフォルダ、スタイルが名前の昇順で出力されること
+//   - スタイルの色がKMLの"aabbggrr"形式であること
+//   - 各プレースマークが上面の高さで押し出し多角形(absolute)として出力されること
+func TestGetKMLOnExtendedSpatialIds01(t *testing.T) {
+	ids := []string{"25/29803148/13212522/25/10", "25/29803149/13212522/25/10", "20/931348/412891/20/0"}
+	options := &KMLOptions{
+		Name:     "operations",
+		Grouping: GroupByCategory,
+		Category: func(id string) string {
+			if strings.HasPrefix(id, "25/") {
+				return "reserved"
+			}
+			return "track"
+		},
+		CategoryColor: func(category string) Color {
+			if category == "reserved" {
+				return Color{R: 0xff, G: 0x00, B: 0x00, A: 0x80}
+			}
+			return Color{R: 0x00, G: 0x00, B: 0xff, A: 0xff}
+		},
+	}
+
+	resultVal, resultErr := GetKMLOnExtendedSpatialIds(ids, options)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	document := kmlDocument{}
+	if err := xml.Unmarshal(resultVal, &document); err != nil {
+		t.Fatalf("KML不正：%s", err)
+	}
+
+	if document.Document.Name != "operations" || len(document.Document.Folders) != 2 || len(document.Document.Styles) != 2 {
+		t.Fatalf("ドキュメント不正：%+v", document.Document)
+	}
+	if document.Document.Folders[0].Name != "reserved" || document.Document.Folders[1].Name != "track" {
+		t.Errorf("フォルダ名 - 期待値：[reserved track], 取得値：[%s %s]", document.Document.Folders[0].Name, document.Document.Folders[1].Name)
+	}
+	if document.Document.Styles[0].PolyStyle.Color != "800000ff" || document.Document.Styles[1].PolyStyle.Color != "ffff0000" {
+		t.Errorf("スタイルの色 - 期待値：[800000ff ffff0000], 取得値：%+v", document.Document.Styles)
+	}
+
+	placemark := document.Document.Folders[0].Placemarks[0]
+	if placemark.Name != ids[0] || placemark.StyleUrl != "#"+document.Document.Styles[0].Id {
+		t.Errorf("プレースマーク不正：%+v", placemark)
+	}
+	if placemark.Polygon.Extrude != 1 || placemark.Polygon.AltitudeMode != kmlAltitudeMode {
+		t.Errorf("多角形不正：%+v", placemark.Polygon)
+	}
+	coordinates := strings.Fields(placemark.Polygon.Coordinates)
+	if len(coordinates) != 5 || coordinates[0] != coordinates[4] || !strings.HasSuffix(coordinates[0], ",11") {
+		t.Errorf("座標 - 期待値：高さ11mの閉じた環, 取得値：%v", coordinates)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetKMZOnExtendedSpatialIds01 正常系動作確認(精度ごと)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：精度の異なる2つの拡張空間ID, 既定のオプション
+//
+// + 確認内容
+//   - doc.kmlを含むKMZが出力されること
+//   - 精度ごとのフォルダに振り分けられること
+func TestGetKMZOnExtendedSpatialIds01(t *testing.T) {
+	ids := []string{"25/29803148/13212522/25/10", "20/931348/412891/20/0"}
+
+	resultVal, resultErr := GetKMZOnExtendedSpatialIds(ids, nil)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(resultVal), int64(len(resultVal)))
+	if err != nil || len(archive.File) != 1 || archive.File[0].Name != kmzDocumentName {
+		t.Fatalf("KMZ不正：%v", err)
+	}
+	file, _ := archive.File[0].Open()
+	kml, _ := io.ReadAll(file)
+
+	document := kmlDocument{}
+	if err := xml.Unmarshal(kml, &document); err != nil {
+		t.Fatalf("KML不正：%s", err)
+	}
+	if len(document.Document.Folders) != 2 || document.Document.Folders[0].Name != "zoom 20/20" || document.Document.Folders[1].Name != "zoom 25/25" {
+		t.Errorf("フォルダ - 期待値：[zoom 20/20, zoom 25/25], 取得値：%+v", document.Document.Folders)
+	}
+	if len(document.Document.Styles) != 1 {
+		t.Errorf("スタイル数 - 期待値：1, 取得値：%v", len(document.Document.Styles))
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetKMLOnExtendedSpatialIds02 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：未対応のフォルダ分け方法
+//   - パターン2：拡張空間IDのフォーマット不正
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetKMLOnExtendedSpatialIds02(t *testing.T) {
+	testCases := []struct {
+		ids     []string
+		options *KMLOptions
+		expect  string
+	}{
+		{[]string{"25/1/1/25/0"}, &KMLOptions{Grouping: 2}, "InputValueError,入力チェックエラー,unsupported grouping"},
+		{[]string{"25/1/1/25"}, nil, "InputValueError,入力チェックエラー"},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := GetKMLOnExtendedSpatialIds(testCase.ids, testCase.options)
+		if resultVal != nil {
+			t.Errorf("KML - 期待値：nil, 取得値：%s", resultVal)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
@@ -1,8 +1,8 @@
 module github.com/trajectoryjp/spatial_id_go/v4
 
-go 1.22
+go 1.23
 
-toolchain go1.22.6
+toolchain go1.23.6
 
 require (
 	github.com/go-gl/mathgl v1.1.0
@@ -2,11 +2,13 @@
 package integrate
 
 import (
+	"fmt"
 	"math"
-	"strconv"
+	"slices"
 	"strings"
 
 	"github.com/trajectoryjp/spatial_id_go/v4/common"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
 	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
 	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
 	"github.com/trajectoryjp/spatial_id_go/v4/shape"
@@ -51,6 +53,7 @@ import (
 //	 空間IDにおける精度を上げる場合、精度が1上がるごとに8のべき乗で空間ID数が増加する。
 //	 そのため、低い精度から高い精度に変換する際、精度差が大きすぎると変換後の空間ID数は大幅に増大する。
 //	 動作環境によってはメモリ不足となる可能性があるため、注意すること。
+//	 変換前に結果の個数を確認する場合は CountSpatialIdsOnZoomChange を使用すること。
 func ChangeSpatialIdsZoom(spatialIds []string, zoom int64) ([]string, error) {
 	// 変換後の空間ID格納用のスライス
 	resultIDList := []string{}
@@ -91,7 +94,8 @@ func ChangeSpatialIdsZoom(spatialIds []string, zoom int64) ([]string, error) {
 // 入力された拡張空間IDに内包される拡張空間IDを返却する。
 //
 // 精度を下げる場合、入力された拡張空間IDボクセルを変換後の精度となるよう拡大し、
-// 入力された拡張空間IDを内包している拡張空間IDを返却する。
+// 入力された拡張空間IDを内包している拡張空間IDを返却する(過大近似)。
+// 入力に完全に満たされる拡張空間IDのみが必要な場合は ChangeExtendedSpatialIdsZoomWithMode を使用すること。
 //
 // 水平/垂直方向精度について、一方の精度が上がり一方の精度が下がる場合、
 // 精度が上がった方向はボクセルを分割、精度が下がった方向はボクセルの拡大がされた拡張空間IDを返却する。
@@ -125,6 +129,7 @@ func ChangeSpatialIdsZoom(spatialIds []string, zoom int64) ([]string, error) {
 //	 拡張空間IDにおける精度を上げる場合、水平精度が1上がるごとに4のべき乗、垂直方向精度が1上がるごとに2のべき乗で拡張空間ID数が増加する。
 //	 そのため、低い精度から高い精度に変換する際、精度差が大きすぎると変換後の拡張空間ID数は大幅に増大する。
 //	 動作環境によってはメモリ不足となる可能性があるため、注意すること。
+//	 変換前に結果の個数を確認する場合は CountExtendedSpatialIdsOnZoomChange を使用すること。
 func ChangeExtendedSpatialIdsZoom(
 	extendedSpatialIds []string,
 	hZoom int64,
@@ -179,6 +184,177 @@ func ChangeExtendedSpatialIdsZoom(
 	return common.Unique(resultIDList), nil
 }
 
+// ChangeSpatialIdsZoomWithMode 近似方法指定の空間IDの精度変換関数
+//
+// ChangeSpatialIdsZoom の精度を下げる場合の近似方法を指定する。
+// 近似方法ごとの変換結果は ChangeExtendedSpatialIdsZoomWithMode のドキュメントを参照。
+//
+// 引数：
+//
+//	spatialIds：精度変換対象の空間ID。複数の空間IDを指定可能、空間ID毎に精度が異なっている入力も許容。
+//	zoom      ：変換後の精度。空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	mode      ：近似方法
+//
+// 戻り値：
+//
+//	精度変換後の全空間IDを格納した配列が返却される。IDの重複は解消された形で返却される。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過          ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 空間IDフォーマット不正：空間IDのフォーマットに違反する値が"変換対象空間ID"に入力されていた場合。
+//	 空間ID範囲外          ：InnerCovering の場合に、空間IDのx成分、y成分が精度の範囲外の場合。
+//	 近似方法不正          ：未対応の近似方法が入力されていた場合。
+func ChangeSpatialIdsZoomWithMode(spatialIds []string, zoom int64, mode enum.CoveringMode) ([]string, error) {
+	extendedSpatialIds, err := shape.ConvertSpatialIdsToExtendedSpatialIds(spatialIds)
+	if err != nil {
+		return []string{}, err
+	}
+
+	changeIDList, err := ChangeExtendedSpatialIdsZoomWithMode(extendedSpatialIds, zoom, zoom, mode)
+	if err != nil {
+		return []string{}, err
+	}
+
+	resultIDList, _ := shape.ConvertExtendedSpatialIdsToSpatialIds(changeIDList)
+
+	return resultIDList, nil
+}
+
+// ChangeExtendedSpatialIdsZoomWithMode 近似方法指定の拡張空間IDの精度変換関数
+//
+// ChangeExtendedSpatialIdsZoom の精度を下げる場合の近似方法を指定する。
+// 精度を上げる方向は、近似方法に関わらず入力された拡張空間IDボクセルを分割する。
+//
+//	・OuterCovering：入力された拡張空間IDを内包している拡張空間IDを返却する(ChangeExtendedSpatialIdsZoom と同じ)。
+//	                 結果は入力の空間を包含する(過大近似)。
+//	・InnerCovering：入力された拡張空間ID全体により完全に満たされる拡張空間IDのみを返却する。
+//	                 結果は入力の空間に包含される(過小近似)。飛行許可範囲等の安全側の判定用途に使用する。
+//
+// InnerCovering では、複数の拡張空間IDを合わせて満たされる場合も満たされるとみなす。
+// 入力を拡張空間IDの集合にまとめて判定するため、結果は精度、x成分、y成分、高さ成分の昇順で返却される。
+//
+// 引数：
+//
+//	extendedSpatialIds：精度変換対象の拡張空間ID。複数の拡張空間IDを指定可能、拡張空間ID毎に精度が異なっている入力も許容。
+//	hZoom             ：変換後の水平方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	vZoom             ：変換後の垂直方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	mode              ：近似方法
+//
+// 戻り値：
+//
+//	精度変換後の全拡張空間IDを格納した配列が返却される。IDの重複は解消された形で返却される。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"変換対象の拡張空間ID"に入力されていた場合。
+//	 拡張空間ID範囲外          ：InnerCovering の場合に、拡張空間IDのx成分、y成分が精度の範囲外の場合。
+//	 近似方法不正              ：未対応の近似方法が入力されていた場合。
+func ChangeExtendedSpatialIdsZoomWithMode(
+	extendedSpatialIds []string,
+	hZoom int64,
+	vZoom int64,
+	mode enum.CoveringMode,
+) ([]string, error) {
+	switch mode {
+	case enum.OuterCovering:
+		return ChangeExtendedSpatialIdsZoom(extendedSpatialIds, hZoom, vZoom)
+	case enum.InnerCovering:
+	default:
+		return []string{}, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("unsupported covering mode: %v", mode))
+	}
+
+	if !shape.CheckZoom(hZoom) || !shape.CheckZoom(vZoom) {
+		return []string{}, errors.NewSpatialIdError(errors.InputValueErrorCode, "")
+	}
+
+	set, err := NewSpatialIDSet(extendedSpatialIds)
+	if err != nil {
+		return []string{}, err
+	}
+	root := set.getRoot()
+
+	cells := map[coveringCell]struct{}{}
+	set.forEachCell(0, 0, func(cellHZoom, x, y, cellVZoom, z int64) {
+		// 精度を下げる方向がある場合は、変換後の拡張空間IDが満たされているかを判定
+		isCoarsened := cellHZoom > hZoom || cellVZoom > vZoom
+
+		minX, minY, maxX, maxY := getFloorHorizontalRange(cellHZoom, x, y, hZoom)
+		minZ, maxZ := getFloorVerticalRange(cellVZoom, z, vZoom)
+		for changedY := minY; changedY <= maxY; changedY++ {
+			for changedX := minX; changedX <= maxX; changedX++ {
+				for changedZ := minZ; changedZ <= maxZ; changedZ++ {
+					cell := coveringCell{hZoom, changedX, changedY, vZoom, changedZ}
+					if isCoarsened && !isCellFilled(root, cell) {
+						continue
+					}
+					cells[cell] = struct{}{}
+				}
+			}
+		}
+	})
+
+	changedCells := make([]coveringCell, 0, len(cells))
+	for cell := range cells {
+		changedCells = append(changedCells, cell)
+	}
+	sortCoveringCells(changedCells)
+
+	resultIDList := make([]string, 0, len(changedCells))
+	for _, cell := range changedCells {
+		resultIDList = append(resultIDList, formatExtendedSpatialId(cell.hZoom, cell.x, cell.y, cell.vZoom, cell.z))
+	}
+
+	return resultIDList, nil
+}
+
+// getFloorHorizontalRange 水平方向の精度変換後のインデックスの範囲の取得
+//
+// HorizontalZoomMinMax と異なり、精度を下げる場合は切り捨てではなく床関数で変換する。
+//
+// 引数：
+//
+//	inputZoom ：変換前の水平方向精度
+//	xIndex    ：変換前のx成分
+//	yIndex    ：変換前のy成分
+//	outputZoom：変換後の水平方向精度
+//
+// 戻り値：
+//
+//	最小x成分、最小y成分、最大x成分、最大y成分
+func getFloorHorizontalRange(inputZoom, xIndex, yIndex, outputZoom int64) (int64, int64, int64, int64) {
+	if outputZoom <= inputZoom {
+		shift := inputZoom - outputZoom
+		return xIndex >> shift, yIndex >> shift, xIndex >> shift, yIndex >> shift
+	}
+	shift := outputZoom - inputZoom
+	return xIndex << shift, yIndex << shift, (xIndex+1)<<shift - 1, (yIndex+1)<<shift - 1
+}
+
+// getFloorVerticalRange 垂直方向の精度変換後のインデックスの範囲の取得
+//
+// VerticalZoomMinMax と異なり、精度を下げる場合は切り捨てではなく床関数で変換する。
+//
+// 引数：
+//
+//	inputZoom ：変換前の垂直方向精度
+//	vIndex    ：変換前の高さ成分
+//	outputZoom：変換後の垂直方向精度
+//
+// 戻り値：
+//
+//	最小高さ成分、最大高さ成分
+func getFloorVerticalRange(inputZoom, vIndex, outputZoom int64) (int64, int64) {
+	if outputZoom <= inputZoom {
+		return vIndex >> (inputZoom - outputZoom), vIndex >> (inputZoom - outputZoom)
+	}
+	shift := outputZoom - inputZoom
+	return vIndex << shift, (vIndex+1)<<shift - 1
+}
+
 // HorizontalZoom 拡張空間IDの水平方向の精度変換関数
 //
 // 変換対象として入力された拡張空間IDの水平方向成分を、指定された精度に変換する。
@@ -211,22 +387,7 @@ func HorizontalZoom(
 	yIndex int64,
 	outputZoom int64,
 ) []string {
-	// 変換後の拡張空間ID格納用のスライス
-	horizontalIDs := []string{}
-
-	minXparam, minYparam, maxXparam, maxYparam := HorizontalZoomMinMax(inputZoom, xIndex, yIndex, outputZoom)
-
-	// 変換後の拡張空間IDを定義
-	for y := minYparam; y <= maxYparam; y++ {
-		for x := minXparam; x <= maxXparam; x++ {
-			horizontalIDs = append(
-				horizontalIDs,
-				strconv.FormatInt(outputZoom, 10)+
-					"/"+strconv.FormatInt(x, 10)+
-					"/"+strconv.FormatInt(y, 10))
-		}
-	}
-	return horizontalIDs
+	return slices.Collect(HorizontalZoomSeq(inputZoom, xIndex, yIndex, outputZoom))
 }
 
 // HorizontalZoomMinMax 拡張空間IDの水平方向の精度変換関数
@@ -299,8 +460,16 @@ func HorizontalZoomMinMax(inputZoom int64, xIndex int64, yIndex int64, outputZoo
 //	 そのため、低い精度から高い精度に変換する際、精度差が大きすぎると変換後の拡張空間ID数は大幅に増大する。
 //	 動作環境によってはメモリ不足となる可能性があるため、注意すること。
 func VerticalZoom(inputZoom int64, vIndex int64, outputZoom int64) []string {
-	verticalIDs := []string{}
+	return slices.Collect(VerticalZoomSeq(inputZoom, vIndex, outputZoom))
+}
 
+// VerticalZoomMinMax 拡張空間IDの垂直方向の精度変換関数
+//
+// VerticalZoom() の返却値が最小vインデックスと最大vインデックスになったもの
+// 詳細は VerticalZoom のドキュメントを参照
+//
+// 戻り値の順序:(最小vインデックス, 最大vインデックス)
+func VerticalZoomMinMax(inputZoom int64, vIndex int64, outputZoom int64) (int64, int64) {
 	// 精度の差異を取得
 	vZoomDiff := outputZoom - inputZoom
 
@@ -325,12 +494,5 @@ func VerticalZoom(inputZoom int64, vIndex int64, outputZoom int64) []string {
 		// 変換後の z 成分の最大値を定義
 		maxVparam = minVparam
 	}
-	// 変換後の拡張空間IDを定義
-	for v := minVparam; v <= maxVparam; v++ {
-		// 生成した拡張空間IDを戻り値に格納
-		verticalIDs = append(
-			verticalIDs,
-			strconv.FormatInt(outputZoom, 10)+"/"+strconv.FormatInt(v, 10))
-	}
-	return verticalIDs
+	return minVparam, maxVparam
 }
@@ -0,0 +1,130 @@
+package integrate
+
+import (
+	"iter"
+	"strconv"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/shape"
+)
+
+// ChangeExtendedSpatialIdsZoomSeq 拡張空間IDの精度変換のイテレータ取得関数
+//
+// ChangeExtendedSpatialIdsZoom の精度変換結果を、1つずつ生成して返却するイテレータを取得する。
+// 精度変換結果の全体をメモリに保持しないため、大量の拡張空間IDをデータベース等へ逐次書き込む用途に使用する。
+//
+// 重複の解消は、入力された拡張空間IDの精度変換結果の範囲を互いに重ならない拡張空間IDの集合にまとめてから
+// 分割することで行う。そのため、使用するメモリ量は精度変換結果の個数ではなく、入力の個数に比例する。
+// 返却される拡張空間IDの集合は ChangeExtendedSpatialIdsZoom と同じとなるが、順序は異なる。
+//
+// 入力値の検証はイテレータの取得時に行う。
+//
+// 引数：
+//
+//	extendedSpatialIds：精度変換対象の拡張空間ID。
+//	hZoom             ：変換後の水平方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	vZoom             ：変換後の垂直方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//
+// 戻り値：
+//
+//	精度変換後の拡張空間IDのイテレータ
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"変換対象の拡張空間ID"に入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのx成分、y成分が精度の範囲外の場合。
+func ChangeExtendedSpatialIdsZoomSeq(extendedSpatialIds []string, hZoom, vZoom int64) (iter.Seq[string], error) {
+	if !shape.CheckZoom(hZoom) || !shape.CheckZoom(vZoom) {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "")
+	}
+
+	set, err := newZoomChangeRangeSet(extendedSpatialIds, hZoom, vZoom)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(string) bool) {
+		// 走査の打ち切り
+		stopped := false
+
+		walkHorizontalNode(set.getRoot(), 0, 0, 0, func(zoom, x, y int64, vertical verticalSet) {
+			if stopped {
+				return
+			}
+			for _, key := range getSortedVerticalKeys(vertical) {
+				walkVerticalNode(vertical[key], 0, key, func(vertZoom, z int64) {
+					if stopped {
+						return
+					}
+					for h := range HorizontalZoomSeq(zoom, x, y, hZoom) {
+						for v := range VerticalZoomSeq(vertZoom, z, vZoom) {
+							if !yield(h + "/" + v) {
+								stopped = true
+								return
+							}
+						}
+					}
+				})
+			}
+		})
+	}, nil
+}
+
+// HorizontalZoomSeq 拡張空間IDの水平方向の精度変換のイテレータ取得関数
+//
+// HorizontalZoom の返却値を、1つずつ生成して返却するイテレータを取得する。
+// 返却される水平方向成分と順序は HorizontalZoom と同じとなる。
+//
+// 引数：
+//
+//	inputZoom ：精度変換対象の拡張空間IDの水平方向精度。
+//	xIndex    ：精度変換対象の拡張空間IDのxIndex成分。
+//	yIndex    ：精度変換対象の拡張空間IDのyIndex成分。
+//	outputZoom：変換後の水平方向精度。
+//
+// 戻り値：
+//
+//	精度変換後の拡張空間IDの水平方向成分のイテレータ
+func HorizontalZoomSeq(inputZoom, xIndex, yIndex, outputZoom int64) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		minXparam, minYparam, maxXparam, maxYparam := HorizontalZoomMinMax(inputZoom, xIndex, yIndex, outputZoom)
+
+		for y := minYparam; y <= maxYparam; y++ {
+			for x := minXparam; x <= maxXparam; x++ {
+				if !yield(strconv.FormatInt(outputZoom, 10) +
+					"/" + strconv.FormatInt(x, 10) +
+					"/" + strconv.FormatInt(y, 10)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// VerticalZoomSeq 拡張空間IDの垂直方向の精度変換のイテレータ取得関数
+//
+// VerticalZoom の返却値を、1つずつ生成して返却するイテレータを取得する。
+// 返却される垂直方向成分と順序は VerticalZoom と同じとなる。
+//
+// 引数：
+//
+//	inputZoom ：精度変換対象の拡張空間IDの垂直方向精度成分。
+//	vIndex    ：精度変換対象の拡張空間IDのvIndex成分。
+//	outputZoom：変換後の垂直方向精度。
+//
+// 戻り値：
+//
+//	精度変換後の拡張空間IDの垂直方向成分のイテレータ
+func VerticalZoomSeq(inputZoom, vIndex, outputZoom int64) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		minVparam, maxVparam := VerticalZoomMinMax(inputZoom, vIndex, outputZoom)
+
+		for v := minVparam; v <= maxVparam; v++ {
+			if !yield(strconv.FormatInt(outputZoom, 10) + "/" + strconv.FormatInt(v, 10)) {
+				return
+			}
+		}
+	}
+}
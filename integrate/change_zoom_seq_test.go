@@ -0,0 +1,163 @@
+package integrate
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// TestChangeExtendedSpatialIdsZoomSeq01 拡張空間IDの精度変換のイテレータ取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：精度を上げる場合(重なる拡張空間IDを含む)
+//   - パターン2：精度を下げる場合(変換後に重複する拡張空間IDを含む)
+//   - パターン3：水平方向精度を上げ、垂直方向精度を下げる場合(負の高さを含む)
+//
+// + 確認内容
+//   - ChangeExtendedSpatialIdsZoom の返却値と同じ拡張空間IDが重複なく返却されること
+func TestChangeExtendedSpatialIdsZoomSeq01(t *testing.T) {
+	testCases := []struct {
+		ids   []string
+		hZoom int64
+		vZoom int64
+	}{
+		{[]string{"1/0/0/1/0", "2/1/1/2/1", "2/2/2/1/0"}, 3, 3},
+		{[]string{"5/10/10/5/3", "5/11/11/5/2", "4/5/5/4/1"}, 3, 3},
+		{[]string{"2/1/1/3/-1", "2/1/1/3/1", "3/2/2/3/-3"}, 4, 1},
+	}
+
+	for _, testCase := range testCases {
+		expectVal, _ := ChangeExtendedSpatialIdsZoom(testCase.ids, testCase.hZoom, testCase.vZoom)
+		sort.Strings(expectVal)
+
+		resultSeq, resultErr := ChangeExtendedSpatialIdsZoomSeq(testCase.ids, testCase.hZoom, testCase.vZoom)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		resultVal := slices.Collect(resultSeq)
+		sort.Strings(resultVal)
+
+		if !reflect.DeepEqual(resultVal, expectVal) {
+			t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestChangeExtendedSpatialIdsZoomSeq02 拡張空間IDの精度変換のイテレータ取得関数 正常系動作確認(走査の打ち切り)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：{"0/0/0/0/0"}を水平方向精度20、垂直方向精度20に変換し、3個目で走査を打ち切る
+//
+// + 確認内容
+//   - 結果の全体を生成せずに3個の拡張空間IDが取得でき、同じイテレータを再度走査できること
+func TestChangeExtendedSpatialIdsZoomSeq02(t *testing.T) {
+	resultSeq, resultErr := ChangeExtendedSpatialIdsZoomSeq([]string{"0/0/0/0/0"}, 20, 20)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	expectVal := []string{"20/0/0/20/0", "20/0/0/20/1", "20/0/0/20/2"}
+	for i := 0; i < 2; i++ {
+		resultVal := []string{}
+		for id := range resultSeq {
+			resultVal = append(resultVal, id)
+			if len(resultVal) == 3 {
+				break
+			}
+		}
+		if !reflect.DeepEqual(resultVal, expectVal) {
+			t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestChangeExtendedSpatialIdsZoomSeq03 拡張空間IDの精度変換のイテレータ取得関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：精度閾値超過
+//   - パターン2：拡張空間IDフォーマット不正
+//
+// + 確認内容
+//   - nilとエラーインスタンス（InputValueErrorCode）が返却されること
+func TestChangeExtendedSpatialIdsZoomSeq03(t *testing.T) {
+	testCases := []struct {
+		ids    []string
+		hZoom  int64
+		vZoom  int64
+		expect string
+	}{
+		{[]string{"0/0/0/0/0"}, 0, 36, "InputValueError,入力チェックエラー"},
+		{[]string{"0/0/0/0"}, 1, 1, "InputValueError,入力チェックエラー"},
+	}
+
+	for _, testCase := range testCases {
+		resultSeq, resultErr := ChangeExtendedSpatialIdsZoomSeq(testCase.ids, testCase.hZoom, testCase.vZoom)
+		if resultSeq != nil {
+			t.Errorf("イテレータ - 期待値：nil, 取得値：%v", resultSeq)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestHorizontalZoomSeq01 拡張空間IDの水平方向の精度変換のイテレータ取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：精度を上げる場合 (1/1/0 → 3)
+//   - パターン2：精度を下げる場合 (3/5/6 → 1)
+//
+// + 確認内容
+//   - HorizontalZoom の返却値と同じ順序で水平方向成分が返却されること
+func TestHorizontalZoomSeq01(t *testing.T) {
+	testCases := [][4]int64{{1, 1, 0, 3}, {3, 5, 6, 1}}
+
+	for _, testCase := range testCases {
+		expectVal := HorizontalZoom(testCase[0], testCase[1], testCase[2], testCase[3])
+		resultVal := slices.Collect(HorizontalZoomSeq(testCase[0], testCase[1], testCase[2], testCase[3]))
+		if !reflect.DeepEqual(resultVal, expectVal) {
+			t.Errorf("水平方向成分 - 期待値：%v, 取得値：%v", expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestVerticalZoomSeq01 拡張空間IDの垂直方向の精度変換のイテレータ取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：精度を上げる場合 (2/-1 → 4)
+//   - パターン2：精度を下げる場合 (4/5 → 2)
+//
+// + 確認内容
+//   - 期待する垂直方向成分が昇順で返却されること
+func TestVerticalZoomSeq01(t *testing.T) {
+	testCases := []struct {
+		input  [3]int64
+		expect []string
+	}{
+		{[3]int64{2, -1, 4}, []string{"4/-4", "4/-3", "4/-2", "4/-1"}},
+		{[3]int64{4, 5, 2}, []string{"2/1"}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal := slices.Collect(VerticalZoomSeq(testCase.input[0], testCase.input[1], testCase.input[2]))
+		if !reflect.DeepEqual(resultVal, testCase.expect) {
+			t.Errorf("垂直方向成分 - 期待値：%v, 取得値：%v", testCase.expect, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
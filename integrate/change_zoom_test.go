@@ -3,6 +3,8 @@ package integrate
 import (
 	"reflect"
 	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
 )
 
 // TestNewChangeSpatialIdsZoom01 空間IDの精度変換関数 正常系動作確認
@@ -816,6 +818,110 @@ func TestVerticalZoom03(t *testing.T) {
 	t.Log("テスト終了")
 }
 
+// TestChangeExtendedSpatialIdsZoomWithMode01 近似方法指定の拡張空間IDの精度変換関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 拡張空間ID："1/0/0/1/0"を満たす水平方向精度2の4つの拡張空間ID、"1/1/0/1/0"の一部の"2/2/0/1/0"
+//   - パターン1：OuterCovering、水平方向精度1、垂直方向精度1
+//   - パターン2：InnerCovering、水平方向精度1、垂直方向精度1
+//   - パターン3：InnerCovering、水平方向精度1、垂直方向精度2(垂直方向は精度を上げる)
+//   - パターン4：InnerCovering、水平方向精度0、垂直方向精度1
+//
+// + 確認内容
+//   - パターン1：入力を内包する拡張空間IDが返却されること
+//   - パターン2：複数の入力で満たされる拡張空間IDのみ返却されること
+//   - パターン3：満たされる拡張空間IDを垂直方向に分割した拡張空間IDが返却されること
+//   - パターン4：満たされる拡張空間IDがない場合、空のスライスが返却されること
+func TestChangeExtendedSpatialIdsZoomWithMode01(t *testing.T) {
+	ids := []string{"2/0/0/1/0", "2/1/0/1/0", "2/0/1/1/0", "2/1/1/1/0", "2/2/0/1/0"}
+	testCases := []struct {
+		hZoom  int64
+		vZoom  int64
+		mode   enum.CoveringMode
+		expect []string
+	}{
+		{1, 1, enum.OuterCovering, []string{"1/0/0/1/0", "1/1/0/1/0"}},
+		{1, 1, enum.InnerCovering, []string{"1/0/0/1/0"}},
+		{1, 2, enum.InnerCovering, []string{"1/0/0/2/0", "1/0/0/2/1"}},
+		{0, 1, enum.InnerCovering, []string{}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := ChangeExtendedSpatialIdsZoomWithMode(ids, testCase.hZoom, testCase.vZoom, testCase.mode)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+
+		//戻り値要素数と期待値の比較
+		if len(resultVal) != len(testCase.expect) {
+			t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", testCase.expect, resultVal)
+			continue
+		}
+
+		//戻り値の空間IDと期待値の比較
+		for _, exp := range testCase.expect {
+			if !contains(resultVal, exp) {
+				t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", testCase.expect, resultVal)
+			}
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestChangeExtendedSpatialIdsZoomWithMode02 近似方法指定の拡張空間IDの精度変換関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：未対応の近似方法
+//   - パターン2：InnerCovering、精度閾値超過
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestChangeExtendedSpatialIdsZoomWithMode02(t *testing.T) {
+	_, resultErr := ChangeExtendedSpatialIdsZoomWithMode([]string{"2/0/0/1/0"}, 1, 1, enum.CoveringMode(2))
+	expectErr := "InputValueError,入力チェックエラー,unsupported covering mode: 2"
+	if resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	_, resultErr = ChangeExtendedSpatialIdsZoomWithMode([]string{"2/0/0/1/0"}, 36, 1, enum.InnerCovering)
+	expectErr = "InputValueError,入力チェックエラー"
+	if resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestChangeSpatialIdsZoomWithMode01 近似方法指定の空間IDの精度変換関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：精度1の"1/0/0/0"を満たす精度2の8つの空間IDと"2/2/0/0"、InnerCovering、精度1
+//
+// + 確認内容
+//   - 満たされる空間ID"1/0/0/0"のみ返却されること
+func TestChangeSpatialIdsZoomWithMode01(t *testing.T) {
+	ids := []string{
+		"2/0/0/0", "2/0/1/0", "2/0/0/1", "2/0/1/1", "2/1/0/0", "2/1/1/0", "2/1/0/1", "2/1/1/1",
+		"2/0/2/0",
+	}
+
+	resultVal, resultErr := ChangeSpatialIdsZoomWithMode(ids, 1, enum.InnerCovering)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	expectVal := []string{"1/0/0/0"}
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
 // stringスライスの中に指定文字列を含むか判定する
 //
 // 引数：
@@ -0,0 +1,319 @@
+package integrate
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// SpatialIDComponent 拡張空間IDの連結成分の構造体
+type SpatialIDComponent struct {
+	ExtendedSpatialIds []string      // 連結成分に含まれる入力の拡張空間ID(入力順、重複なし)
+	Volume             float64       // 連結成分の体積(単位:m³)
+	Min                *object.Point // 連結成分を包含する最小の経度、緯度、高さを持つ座標(南西下端)
+	Max                *object.Point // 連結成分を包含する最大の経度、緯度、高さを持つ座標(北東上端)
+}
+
+// cellBox 拡張空間IDの最も細かい精度のインデックスでの範囲
+//
+// 各成分は下端を含み上端を含まない区間とする。
+type cellBox struct {
+	x0, x1 int64 // x成分の範囲
+	y0, y1 int64 // y成分の範囲
+	z0, z1 int64 // 高さ成分の範囲
+}
+
+// GetConnectedComponentsOnExtendedSpatialIds 拡張空間IDの連結成分取得関数
+//
+// 拡張空間ID配列が表す空間を、連結した空間ごとの連結成分に分割する。
+// 独立した障害物の分離や、到達できない空間の検出に使用する。
+//
+// 連結の判定は拡張空間IDの空間(体積)の隣接により行うため、精度の混在した拡張空間IDも判定できる。
+// 連結の種別は以下とする。
+//
+//	・6 ：面を共有する場合に連結
+//	・18：辺以上を共有する場合に連結
+//	・26：頂点以上を共有する場合に連結
+//
+// 経度方向は東経180度と西経180度の境界を越えて連結する。
+// 入力の拡張空間IDは1つの連結成分に含まれる。
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間ID文字列配列
+//	connectivity      ：連結の種別。6、18、26 のいずれかを指定可能。
+//
+// 戻り値：
+//
+//	連結成分のスライス。連結成分に含まれる拡張空間IDが入力に現れる順で返却される。
+//	体積は GetVolumeOnExtendedSpatialIds、範囲は GetBoundsOnExtendedSpatialIds と同じ方法で求める。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"拡張空間ID配列"に入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのx成分、y成分が精度の範囲外の場合。
+//	 入力値不正                ：連結の種別が未対応の場合。
+func GetConnectedComponentsOnExtendedSpatialIds(extendedSpatialIds []string, connectivity int) ([]*SpatialIDComponent, error) {
+	// 共有が必要な範囲の正の長さを持つ成分の数
+	var minOverlaps int
+	switch connectivity {
+	case 6:
+		minOverlaps = 2
+	case 18:
+		minOverlaps = 1
+	case 26:
+		minOverlaps = 0
+	default:
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("unsupported connectivity: %v", connectivity))
+	}
+
+	// 範囲は入力の最も細かい精度のインデックスで表す
+	ids, maxHZoom, maxVZoom, err := newSpatialIDsOnSet(extendedSpatialIds)
+	if err != nil {
+		return nil, err
+	}
+	set := &SpatialIDSet{root: buildHorizontalNode(ids, 0)}
+
+	// 集合の互いに重ならない拡張空間ID
+	cells := []coveringCell{}
+	cellIndexes := map[coveringCell]int{}
+	set.forEachCell(0, 0, func(hZoom, x, y, vZoom, z int64) {
+		cell := coveringCell{hZoom, x, y, vZoom, z}
+		cellIndexes[cell] = len(cells)
+		cells = append(cells, cell)
+	})
+	width := int64(1) << maxHZoom
+
+	// 隣接する拡張空間IDの連結
+	parents := make([]int, len(cells))
+	for i := range parents {
+		parents[i] = i
+	}
+	for i, cell := range cells {
+		box := cell.box(maxHZoom, maxVZoom)
+
+		// 周囲の1個分の範囲を経度方向の周回を考慮して探索
+		sizeX, sizeY, sizeZ := box.x1-box.x0, box.y1-box.y0, box.z1-box.z0
+		around := cellBox{box.x0 - sizeX, box.x1 + sizeX, box.y0 - sizeY, box.y1 + sizeY, box.z0 - sizeZ, box.z1 + sizeZ}
+		for _, shift := range []int64{-width, 0, width} {
+			shifted := around
+			shifted.x0, shifted.x1 = around.x0+shift, around.x1+shift
+			forEachCellInBox(set.getRoot(), 0, 0, 0, maxHZoom, maxVZoom, shifted, func(other coveringCell) {
+				j := cellIndexes[other]
+				if j == i {
+					return
+				}
+				otherBox := other.box(maxHZoom, maxVZoom)
+				otherBox.x0, otherBox.x1 = otherBox.x0-shift, otherBox.x1-shift
+				if box.isAdjacent(otherBox, minOverlaps) {
+					unionComponents(parents, i, j)
+				}
+			})
+		}
+	}
+
+	// 連結成分ごとの体積と範囲
+	components := map[int]*SpatialIDComponent{}
+	for i, cell := range cells {
+		root := findComponent(parents, i)
+		component, ok := components[root]
+		if !ok {
+			component = &SpatialIDComponent{}
+			components[root] = component
+		}
+		component.Volume += cell.volume()
+
+		west, south, east, north := getTileBounds(cell.hZoom, cell.x, cell.y)
+		resolution := getVerticalResolution(cell.vZoom)
+		bottom, top := float64(cell.z)*resolution, float64(cell.z+1)*resolution
+		if !ok {
+			component.Min, _ = object.NewPoint(west, south, bottom)
+			component.Max, _ = object.NewPoint(east, north, top)
+			continue
+		}
+		component.Min, _ = object.NewPoint(math.Min(component.Min.Lon(), west), math.Min(component.Min.Lat(), south), math.Min(component.Min.Alt(), bottom))
+		component.Max, _ = object.NewPoint(math.Max(component.Max.Lon(), east), math.Max(component.Max.Lat(), north), math.Max(component.Max.Alt(), top))
+	}
+
+	// 入力の拡張空間IDの振り分け
+	result := []*SpatialIDComponent{}
+	added := map[string]struct{}{}
+	for index, id := range ids {
+		if _, ok := added[extendedSpatialIds[index]]; ok {
+			continue
+		}
+		added[extendedSpatialIds[index]] = struct{}{}
+
+		// 入力の拡張空間IDの下端を含む拡張空間IDの連結成分
+		hShift, vShift := maxHZoom-id.HZoom(), maxVZoom-id.VZoom()
+		corner := cellBox{id.X() << hShift, id.X()<<hShift + 1, id.Y() << hShift, id.Y()<<hShift + 1, id.Z() << vShift, id.Z()<<vShift + 1}
+		var component *SpatialIDComponent
+		forEachCellInBox(set.getRoot(), 0, 0, 0, maxHZoom, maxVZoom, corner, func(cell coveringCell) {
+			component = components[findComponent(parents, cellIndexes[cell])]
+		})
+
+		if len(component.ExtendedSpatialIds) == 0 {
+			result = append(result, component)
+		}
+		component.ExtendedSpatialIds = append(component.ExtendedSpatialIds, extendedSpatialIds[index])
+	}
+
+	return result, nil
+}
+
+// forEachCellInBox 範囲と重なる集合の拡張空間IDの走査
+//
+// 引数：
+//
+//	node    ：4分木のノード
+//	zoom    ：ノードの水平方向精度
+//	x, y    ：ノードのx成分、y成分
+//	maxHZoom：範囲の水平方向精度
+//	maxVZoom：範囲の垂直方向精度
+//	box     ：範囲
+//	f       ：範囲と正の体積で重なる拡張空間IDを受け取る関数
+func forEachCellInBox(node *horizontalNode, zoom, x, y, maxHZoom, maxVZoom int64, box cellBox, f func(cell coveringCell)) {
+	if node == nil {
+		return
+	}
+	shift := maxHZoom - zoom
+	if !isIntervalOverlapped(x<<shift, (x+1)<<shift, box.x0, box.x1) || !isIntervalOverlapped(y<<shift, (y+1)<<shift, box.y0, box.y1) {
+		return
+	}
+
+	if node.children != nil {
+		for index, child := range node.children {
+			forEachCellInBox(child, zoom+1, 2*x+int64(index%2), 2*y+int64(index/2), maxHZoom, maxVZoom, box, f)
+		}
+		return
+	}
+
+	for _, key := range getSortedVerticalKeys(node.vertical) {
+		forEachVerticalNodeInBox(node.vertical[key], 0, key, maxVZoom, box, func(vZoom, z int64) {
+			f(coveringCell{zoom, x, y, vZoom, z})
+		})
+	}
+}
+
+// forEachVerticalNodeInBox 範囲と重なる2分木の全体を満たすノードの走査
+//
+// 引数：
+//
+//	node    ：2分木のノード
+//	zoom    ：ノードの垂直方向精度
+//	z       ：ノードの高さ成分
+//	maxVZoom：範囲の垂直方向精度
+//	box     ：範囲
+//	f       ：範囲と正の長さで重なる全体を満たすノードの精度と高さ成分を受け取る関数
+func forEachVerticalNodeInBox(node *verticalNode, zoom, z, maxVZoom int64, box cellBox, f func(zoom, z int64)) {
+	if node == nil {
+		return
+	}
+	shift := maxVZoom - zoom
+	if !isIntervalOverlapped(z<<shift, (z+1)<<shift, box.z0, box.z1) {
+		return
+	}
+	if node.full {
+		f(zoom, z)
+		return
+	}
+	for index, child := range node.children {
+		forEachVerticalNodeInBox(child, zoom+1, 2*z+int64(index), maxVZoom, box, f)
+	}
+}
+
+// isIntervalOverlapped 区間の重なりの判定
+//
+// 引数：
+//
+//	a0, a1：区間aの下端、上端
+//	b0, b1：区間bの下端、上端
+//
+// 戻り値：
+//
+//	正の長さで重なる場合true
+func isIntervalOverlapped(a0, a1, b0, b1 int64) bool {
+	return max(a0, b0) < min(a1, b1)
+}
+
+// box 拡張空間IDの最も細かい精度のインデックスでの範囲の取得
+//
+// 引数：
+//
+//	maxHZoom：範囲の水平方向精度
+//	maxVZoom：範囲の垂直方向精度
+//
+// 戻り値：
+//
+//	範囲
+func (c coveringCell) box(maxHZoom, maxVZoom int64) cellBox {
+	hShift, vShift := maxHZoom-c.hZoom, maxVZoom-c.vZoom
+	return cellBox{
+		c.x << hShift, (c.x + 1) << hShift,
+		c.y << hShift, (c.y + 1) << hShift,
+		c.z << vShift, (c.z + 1) << vShift,
+	}
+}
+
+// isAdjacent 重ならない範囲の隣接の判定
+//
+// 引数：
+//
+//	other      ：判定対象の範囲
+//	minOverlaps：正の長さで重なる必要のある成分の数
+//
+// 戻り値：
+//
+//	全ての成分で重なる、または接し、正の長さで重なる成分の数が minOverlaps 以上の場合true
+func (b cellBox) isAdjacent(other cellBox, minOverlaps int) bool {
+	overlaps := 0
+	for _, interval := range [3][4]int64{
+		{b.x0, b.x1, other.x0, other.x1},
+		{b.y0, b.y1, other.y0, other.y1},
+		{b.z0, b.z1, other.z0, other.z1},
+	} {
+		if isIntervalOverlapped(interval[0], interval[1], interval[2], interval[3]) {
+			overlaps++
+			continue
+		}
+		if interval[1] != interval[2] && interval[3] != interval[0] {
+			return false
+		}
+	}
+	return overlaps >= minOverlaps
+}
+
+// findComponent 連結成分の代表の取得
+//
+// 引数：
+//
+//	parents：各要素の親の添字
+//	i      ：要素の添字
+//
+// 戻り値：
+//
+//	連結成分の代表の添字
+func findComponent(parents []int, i int) int {
+	for parents[i] != i {
+		parents[i] = parents[parents[i]]
+		i = parents[i]
+	}
+	return i
+}
+
+// unionComponents 連結成分の統合
+//
+// 引数：
+//
+//	parents：各要素の親の添字
+//	i, j   ：統合する要素の添字
+func unionComponents(parents []int, i, j int) {
+	rootI, rootJ := findComponent(parents, i), findComponent(parents, j)
+	if rootI != rootJ {
+		parents[max(rootI, rootJ)] = min(rootI, rootJ)
+	}
+}
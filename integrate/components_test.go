@@ -0,0 +1,157 @@
+package integrate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common"
+)
+
+// getComponentIds 試験用の連結成分ごとの拡張空間IDの取得
+//
+// 引数：
+//
+//	components：連結成分
+//
+// 戻り値：
+//
+//	連結成分ごとの拡張空間ID
+func getComponentIds(components []*SpatialIDComponent) [][]string {
+	ids := [][]string{}
+	for _, component := range components {
+		ids = append(ids, component.ExtendedSpatialIds)
+	}
+	return ids
+}
+
+// TestGetConnectedComponentsOnExtendedSpatialIds01 拡張空間IDの連結成分取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - a："10/10/10/10/10"、b："10/11/10/10/10"(aと面で接する)、c："10/12/11/10/10"(bと辺で接する)、
+//     d："10/13/12/10/11"(cと頂点で接する)、e："10/20/20/10/10"(孤立)
+//   - パターン1：連結の種別6
+//   - パターン2：連結の種別18
+//   - パターン3：連結の種別26
+//
+// + 確認内容
+//   - 連結の種別に応じた連結成分が入力順に返却されること
+func TestGetConnectedComponentsOnExtendedSpatialIds01(t *testing.T) {
+	ids := []string{"10/10/10/10/10", "10/11/10/10/10", "10/12/11/10/10", "10/13/12/10/11", "10/20/20/10/10"}
+	testCases := []struct {
+		connectivity int
+		expectVal    [][]string
+	}{
+		{6, [][]string{{ids[0], ids[1]}, {ids[2]}, {ids[3]}, {ids[4]}}},
+		{18, [][]string{{ids[0], ids[1], ids[2]}, {ids[3]}, {ids[4]}}},
+		{26, [][]string{{ids[0], ids[1], ids[2], ids[3]}, {ids[4]}}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := GetConnectedComponentsOnExtendedSpatialIds(ids, testCase.connectivity)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(getComponentIds(resultVal), testCase.expectVal) {
+			t.Errorf("連結成分(%v) - 期待値：%v, 取得値：%v", testCase.connectivity, testCase.expectVal, getComponentIds(resultVal))
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetConnectedComponentsOnExtendedSpatialIds02 拡張空間IDの連結成分取得関数 精度混在、経度方向の周回の確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1："9/5/5/9/5"、面で接する"10/12/10/10/10"、頂点で接する"10/12/12/10/12"、重複した"9/5/5/9/5"、連結の種別6
+//   - パターン2：パターン1と同じ拡張空間ID、連結の種別26
+//   - パターン3：東端の"2/3/1/0/0"と西端の"2/0/1/0/0"、連結の種別6
+//
+// + 確認内容
+//   - パターン1、2：精度の異なる拡張空間IDが空間の隣接により連結され、重複が除かれること
+//   - パターン3：東経180度の境界を越えて連結されること
+func TestGetConnectedComponentsOnExtendedSpatialIds02(t *testing.T) {
+	ids := []string{"9/5/5/9/5", "10/12/10/10/10", "10/12/12/10/12", "9/5/5/9/5"}
+	testCases := []struct {
+		ids          []string
+		connectivity int
+		expectVal    [][]string
+	}{
+		{ids, 6, [][]string{{ids[0], ids[1]}, {ids[2]}}},
+		{ids, 26, [][]string{{ids[0], ids[1], ids[2]}}},
+		{[]string{"2/3/1/0/0", "2/0/1/0/0"}, 6, [][]string{{"2/3/1/0/0", "2/0/1/0/0"}}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := GetConnectedComponentsOnExtendedSpatialIds(testCase.ids, testCase.connectivity)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(getComponentIds(resultVal), testCase.expectVal) {
+			t.Errorf("連結成分(%v) - 期待値：%v, 取得値：%v", testCase.connectivity, testCase.expectVal, getComponentIds(resultVal))
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetConnectedComponentsOnExtendedSpatialIds03 拡張空間IDの連結成分取得関数 体積、範囲の確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：{"20/524288/524287/25/0", "20/524288/524287/25/1", "20/524289/524287/24/0"}、連結の種別6
+//
+// + 確認内容
+//   - 体積、範囲が GetVolumeOnExtendedSpatialIds、GetBoundsOnExtendedSpatialIds と一致すること
+func TestGetConnectedComponentsOnExtendedSpatialIds03(t *testing.T) {
+	ids := []string{"20/524288/524287/25/0", "20/524288/524287/25/1", "20/524289/524287/24/0"}
+
+	resultVal, resultErr := GetConnectedComponentsOnExtendedSpatialIds(ids, 6)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if len(resultVal) != 1 {
+		t.Fatalf("連結成分 - 期待要素数：1, 取得要素数：%v", len(resultVal))
+	}
+
+	expectVolume, _ := GetVolumeOnExtendedSpatialIds(ids)
+	if !common.AlmostEqual(resultVal[0].Volume/expectVolume, 1, 1e-12) {
+		t.Errorf("体積 - 期待値：%v, 取得値：%v", expectVolume, resultVal[0].Volume)
+	}
+	expectMin, expectMax, _ := GetBoundsOnExtendedSpatialIds(ids)
+	if !reflect.DeepEqual(resultVal[0].Min, expectMin) || !reflect.DeepEqual(resultVal[0].Max, expectMax) {
+		t.Errorf("範囲 - 期待値：%v～%v, 取得値：%v～%v", expectMin, expectMax, resultVal[0].Min, resultVal[0].Max)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetConnectedComponentsOnExtendedSpatialIds04 拡張空間IDの連結成分取得関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：未対応の連結の種別
+//   - パターン2：拡張空間ID範囲外
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetConnectedComponentsOnExtendedSpatialIds04(t *testing.T) {
+	testCases := []struct {
+		ids          []string
+		connectivity int
+		expectErr    string
+	}{
+		{[]string{"10/10/10/10/10"}, 8, "InputValueError,入力チェックエラー,unsupported connectivity: 8"},
+		{[]string{"2/4/0/0/0"}, 6, "InputValueError,入力チェックエラー,index out of range: 2/4/0/0/0"},
+	}
+
+	for _, testCase := range testCases {
+		_, resultErr := GetConnectedComponentsOnExtendedSpatialIds(testCase.ids, testCase.connectivity)
+		if resultErr == nil || resultErr.Error() != testCase.expectErr {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expectErr, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
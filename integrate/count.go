@@ -0,0 +1,268 @@
+package integrate
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+	"github.com/trajectoryjp/spatial_id_go/v4/shape"
+)
+
+// CountSpatialIdsOnZoomChange 空間IDの精度変換結果の個数取得関数
+//
+// ChangeSpatialIdsZoom が返却する空間IDの個数を、空間IDを生成せずに取得する。
+// 精度変換前に結果の個数を確認し、過大な要求の拒否や分割を行うために使用する。
+//
+// 引数：
+//
+//	spatialIds：精度変換対象の空間ID。
+//	zoom      ：変換後の精度。空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//
+// 戻り値：
+//
+//	精度変換後の空間IDの個数
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過          ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 空間IDフォーマット不正：空間IDのフォーマットに違反する値が"変換対象空間ID"に入力されていた場合。
+//	 空間ID範囲外          ：空間IDのx成分、y成分が精度の範囲外の場合。
+//	 個数超過              ：個数がint64の範囲を超える場合。
+func CountSpatialIdsOnZoomChange(spatialIds []string, zoom int64) (int64, error) {
+	extendedSpatialIds, err := shape.ConvertSpatialIdsToExtendedSpatialIds(spatialIds)
+	if err != nil {
+		return 0, err
+	}
+
+	return CountExtendedSpatialIdsOnZoomChange(extendedSpatialIds, zoom, zoom)
+}
+
+// CountExtendedSpatialIdsOnZoomChange 拡張空間IDの精度変換結果の個数取得関数
+//
+// ChangeExtendedSpatialIdsZoom が返却する拡張空間IDの個数を、拡張空間IDを生成せずに取得する。
+//
+// 各拡張空間IDの精度変換結果の範囲を集合にまとめ、集合の体積を変換後の精度の拡張空間IDの個数として数える。
+// 結果の重複は解消された個数となる。
+//
+// 引数：
+//
+//	extendedSpatialIds：精度変換対象の拡張空間ID。
+//	hZoom             ：変換後の水平方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	vZoom             ：変換後の垂直方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//
+// 戻り値：
+//
+//	精度変換後の拡張空間IDの個数
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"変換対象の拡張空間ID"に入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのx成分、y成分が精度の範囲外の場合。
+//	 個数超過                  ：個数がint64の範囲を超える場合。
+func CountExtendedSpatialIdsOnZoomChange(extendedSpatialIds []string, hZoom, vZoom int64) (int64, error) {
+	if !shape.CheckZoom(hZoom) || !shape.CheckZoom(vZoom) {
+		return 0, errors.NewSpatialIdError(errors.InputValueErrorCode, "")
+	}
+
+	set, err := newZoomChangeRangeSet(extendedSpatialIds, hZoom, vZoom)
+	if err != nil {
+		return 0, err
+	}
+
+	return countCells(set, hZoom, vZoom)
+}
+
+// newZoomChangeRangeSet 精度変換結果の範囲の集合の作成
+//
+// 各拡張空間IDの精度変換結果は、HorizontalZoomMinMax、VerticalZoomMinMax で求まる
+// 変換後の精度のインデックスの範囲となる。この範囲を変換前後の粗い方の精度の拡張空間IDとして集合にまとめる。
+// 集合の拡張空間IDは互いに重ならず、変換後の精度より粗いため、それぞれを変換後の精度に分割すると
+// 重複のない精度変換結果となる。
+//
+// 引数：
+//
+//	extendedSpatialIds：精度変換対象の拡張空間ID。
+//	hZoom             ：変換後の水平方向精度。
+//	vZoom             ：変換後の垂直方向精度。
+//
+// 戻り値：
+//
+//	精度変換結果の範囲の集合
+//
+// 戻り値(エラー)：
+//
+//	拡張空間IDが不正な場合エラーを返却する。
+func newZoomChangeRangeSet(extendedSpatialIds []string, hZoom, vZoom int64) (*SpatialIDSet, error) {
+	ranges := make([]*object.ExtendedSpatialID, 0, len(extendedSpatialIds))
+	for _, extendedSpatialId := range extendedSpatialIds {
+		id, err := newSpatialIDOnSet(extendedSpatialId)
+		if err != nil {
+			return nil, err
+		}
+
+		// 精度を下げる方向は変換後のインデックス、上げる方向は変換前のインデックスとする
+		if id.HZoom() > hZoom {
+			x, y, _, _ := HorizontalZoomMinMax(id.HZoom(), id.X(), id.Y(), hZoom)
+			id.SetX(x)
+			id.SetY(y)
+			id.SetZoom(hZoom, id.VZoom())
+		}
+		if id.VZoom() > vZoom {
+			z, _ := VerticalZoomMinMax(id.VZoom(), id.Z(), vZoom)
+			id.SetZ(z)
+			id.SetZoom(id.HZoom(), vZoom)
+		}
+
+		ranges = append(ranges, id)
+	}
+
+	return &SpatialIDSet{root: buildHorizontalNode(ranges, 0)}, nil
+}
+
+// CountMergedSpatialIds 空間IDのマージ結果の個数取得関数
+//
+// MergeSpatialIds が返却する空間IDの個数を、単位空間IDへの分割を行わずに取得する。
+//
+// 引数：
+//
+//	spatialIds：マージ対象の空間ID文字列配列
+//	zoom      ：マージ後の精度。空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//
+// 戻り値：
+//
+//	マージ後の空間IDの個数
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過          ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 空間IDフォーマット不正：空間IDのフォーマットに違反する値が"空間ID配列"に入力されていた場合。
+func CountMergedSpatialIds(spatialIds []string, zoom int64) (int64, error) {
+	extendedSpatialIds, err := shape.ConvertSpatialIdsToExtendedSpatialIds(spatialIds)
+	if err != nil {
+		return 0, err
+	}
+
+	return CountMergedExtendedSpatialIds(extendedSpatialIds, zoom, zoom)
+}
+
+// CountMergedExtendedSpatialIds 拡張空間IDのマージ結果の個数取得関数
+//
+// MergeExtendedSpatialIds が返却する拡張空間IDの個数を、単位拡張空間IDへの分割を行わずに取得する。
+//
+// MergeExtendedSpatialIds と同じ処理でマージ対象の拡張空間IDをマージ後の精度の拡張空間IDごとにまとめ、
+// 稠密であるかを判定した結果から個数を数える。
+//
+// 引数：
+//
+//	extendedSpatialIds：マージ対象の拡張空間ID文字列配列
+//	hZoom             ：マージ後の水平方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	vZoom             ：マージ後の垂直方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//
+// 戻り値：
+//
+//	マージ後の拡張空間IDの個数
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"拡張空間ID配列"に入力されていた場合。
+func CountMergedExtendedSpatialIds(extendedSpatialIds []string, hZoom, vZoom int64) (int64, error) {
+	if !shape.CheckZoom(hZoom) || !shape.CheckZoom(vZoom) {
+		return 0, errors.NewSpatialIdError(errors.InputValueErrorCode, "")
+	}
+
+	coarseIDs, groups, err := groupMergeTargets(extendedSpatialIds, hZoom, vZoom)
+	if err != nil {
+		return 0, err
+	}
+
+	// 稠密な場合はマージ後の拡張空間ID、稠密でない場合はマージ元の拡張空間IDが返却される
+	count := int64(len(common.Unique(coarseIDs)))
+	for _, group := range groups {
+		if group.isDense() {
+			count++
+		} else {
+			count += int64(len(group.lowIDs))
+		}
+	}
+
+	return count, nil
+}
+
+// countCells 集合を指定精度で分割した拡張空間IDの個数の取得
+//
+// 引数：
+//
+//	set  ：拡張空間IDの集合
+//	hZoom：分割後の水平方向精度。集合の拡張空間IDの水平方向精度以上であること。
+//	vZoom：分割後の垂直方向精度。集合の拡張空間IDの垂直方向精度以上であること。
+//
+// 戻り値：
+//
+//	拡張空間IDの個数
+//
+// 戻り値(エラー)：
+//
+//	個数がint64の範囲を超える場合エラーを返却する。
+func countCells(set *SpatialIDSet, hZoom, vZoom int64) (int64, error) {
+	var count, carry uint64
+	var err error
+
+	set.forEachCell(0, 0, func(cellHZoom, x, y, cellVZoom, z int64) {
+		if err != nil {
+			return
+		}
+		cellCount, cellErr := getCellCount(cellHZoom, cellVZoom, hZoom, vZoom)
+		if cellErr != nil {
+			err = cellErr
+			return
+		}
+		count, carry = bits.Add64(count, uint64(cellCount), 0)
+		if carry != 0 || count > math.MaxInt64 {
+			err = newCountOverflowError()
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(count), nil
+}
+
+// getCellCount 拡張空間IDを指定精度で分割した個数の取得
+//
+// 引数：
+//
+//	fromHZoom, fromVZoom：分割前の水平方向精度、垂直方向精度
+//	toHZoom, toVZoom    ：分割後の水平方向精度、垂直方向精度
+//
+// 戻り値：
+//
+//	4^(toHZoom - fromHZoom) * 2^(toVZoom - fromVZoom)
+//
+// 戻り値(エラー)：
+//
+//	個数がint64の範囲を超える場合エラーを返却する。
+func getCellCount(fromHZoom, fromVZoom, toHZoom, toVZoom int64) (int64, error) {
+	shift := 2*(toHZoom-fromHZoom) + (toVZoom - fromVZoom)
+	if shift >= 63 {
+		return 0, newCountOverflowError()
+	}
+	return int64(1) << shift, nil
+}
+
+// newCountOverflowError 個数超過エラーの作成
+//
+// 戻り値：
+//
+//	エラーインスタンス
+func newCountOverflowError() error {
+	return errors.NewSpatialIdError(errors.InputValueErrorCode, "the number of IDs overflows int64")
+}
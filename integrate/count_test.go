@@ -0,0 +1,191 @@
+package integrate
+
+import (
+	"testing"
+)
+
+// TestCountExtendedSpatialIdsOnZoomChange01 拡張空間IDの精度変換結果の個数取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：精度を上げる場合(重なる拡張空間IDを含む)
+//   - パターン2：精度を下げる場合(変換後に重複する拡張空間IDを含む)
+//   - パターン3：水平方向精度を上げ、垂直方向精度を下げる場合(負の高さを含む)
+//
+// + 確認内容
+//   - ChangeExtendedSpatialIdsZoom の返却値の個数と一致すること
+func TestCountExtendedSpatialIdsOnZoomChange01(t *testing.T) {
+	testCases := []struct {
+		ids   []string
+		hZoom int64
+		vZoom int64
+	}{
+		{[]string{"1/0/0/1/0", "2/1/1/2/1", "2/2/2/1/0"}, 3, 3},
+		{[]string{"5/10/10/5/3", "5/11/11/5/2", "4/5/5/4/1"}, 3, 3},
+		{[]string{"2/1/1/3/-1", "2/1/1/3/1", "3/2/2/3/-3"}, 4, 1},
+	}
+
+	for _, testCase := range testCases {
+		expectVal, _ := ChangeExtendedSpatialIdsZoom(testCase.ids, testCase.hZoom, testCase.vZoom)
+
+		resultVal, resultErr := CountExtendedSpatialIdsOnZoomChange(testCase.ids, testCase.hZoom, testCase.vZoom)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if resultVal != int64(len(expectVal)) {
+			t.Errorf("個数 - 期待値：%v, 取得値：%v", len(expectVal), resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestCountExtendedSpatialIdsOnZoomChange02 拡張空間IDの精度変換結果の個数取得関数 正常系動作確認(大量の結果)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：{"0/0/0/0/0"}を水平方向精度20、垂直方向精度20に変換
+//
+// + 確認内容
+//   - 拡張空間IDを生成せずに 4^20 * 2^20 個が返却されること
+func TestCountExtendedSpatialIdsOnZoomChange02(t *testing.T) {
+	resultVal, resultErr := CountExtendedSpatialIdsOnZoomChange([]string{"0/0/0/0/0"}, 20, 20)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	expectVal := int64(1) << 60
+	if resultVal != expectVal {
+		t.Errorf("個数 - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestCountExtendedSpatialIdsOnZoomChange03 拡張空間IDの精度変換結果の個数取得関数 異常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：精度閾値超過
+//   - パターン2：個数超過({"0/0/0/0/0"}を水平方向精度35、垂直方向精度35に変換)
+//   - パターン3：拡張空間ID範囲外
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestCountExtendedSpatialIdsOnZoomChange03(t *testing.T) {
+	testCases := []struct {
+		ids    []string
+		hZoom  int64
+		vZoom  int64
+		expect string
+	}{
+		{[]string{"0/0/0/0/0"}, 36, 0, "InputValueError,入力チェックエラー"},
+		{[]string{"0/0/0/0/0"}, 35, 35, "InputValueError,入力チェックエラー,the number of IDs overflows int64"},
+		{[]string{"1/2/0/0/0"}, 1, 1, "InputValueError,入力チェックエラー,index out of range: 1/2/0/0/0"},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := CountExtendedSpatialIdsOnZoomChange(testCase.ids, testCase.hZoom, testCase.vZoom)
+		if resultVal != 0 {
+			t.Errorf("個数 - 期待値：0, 取得値：%v", resultVal)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestCountSpatialIdsOnZoomChange01 空間IDの精度変換結果の個数取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：{"1/0/0/0", "2/0/1/1"}を精度3に変換
+//
+// + 確認内容
+//   - ChangeSpatialIdsZoom の返却値の個数と一致すること
+func TestCountSpatialIdsOnZoomChange01(t *testing.T) {
+	ids := []string{"1/0/0/0", "2/0/1/1"}
+	expectVal, _ := ChangeSpatialIdsZoom(ids, 3)
+
+	resultVal, resultErr := CountSpatialIdsOnZoomChange(ids, 3)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if resultVal != int64(len(expectVal)) {
+		t.Errorf("個数 - 期待値：%v, 取得値：%v", len(expectVal), resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestCountMergedExtendedSpatialIds01 拡張空間IDのマージ結果の個数取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：稠密な拡張空間ID群と、稠密でない拡張空間ID群、マージ対象外の拡張空間IDの混在
+//   - パターン2：精度の混在した拡張空間IDで稠密となる場合
+//   - パターン3：全てマージ対象外の場合
+//
+// + 確認内容
+//   - MergeExtendedSpatialIds の返却値の個数と一致すること
+func TestCountMergedExtendedSpatialIds01(t *testing.T) {
+	testCases := []struct {
+		ids   []string
+		hZoom int64
+		vZoom int64
+	}{
+		{
+			[]string{
+				"2/0/0/2/0", "2/1/0/2/0", "2/0/1/2/0", "2/1/1/2/0",
+				"2/2/0/2/0", "2/3/0/2/0",
+				"0/0/0/0/0",
+			},
+			1, 2,
+		},
+		{[]string{"2/0/0/2/0", "3/2/0/3/0", "3/2/0/3/1", "3/3/0/2/0", "2/1/0/3/1", "2/0/1/2/0", "2/1/1/2/0"}, 1, 2},
+		{[]string{"1/0/0/1/0", "1/1/0/1/0"}, 2, 2},
+	}
+
+	for _, testCase := range testCases {
+		expectVal, _ := MergeExtendedSpatialIds(testCase.ids, testCase.hZoom, testCase.vZoom)
+
+		resultVal, resultErr := CountMergedExtendedSpatialIds(testCase.ids, testCase.hZoom, testCase.vZoom)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if resultVal != int64(len(expectVal)) {
+			t.Errorf("個数 - 期待値：%v, 取得値：%v", len(expectVal), resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestCountMergedSpatialIds01 空間IDのマージ結果の個数取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：精度1の空間IDを満たす精度2の8個の空間IDと、稠密でない精度2の空間ID
+//
+// + 確認内容
+//   - MergeSpatialIds の返却値の個数と一致すること
+func TestCountMergedSpatialIds01(t *testing.T) {
+	ids := []string{
+		"2/0/0/0", "2/0/1/0", "2/0/0/1", "2/0/1/1",
+		"2/1/0/0", "2/1/1/0", "2/1/0/1", "2/1/1/1",
+		"2/0/2/2",
+	}
+	expectVal, _ := MergeSpatialIds(ids, 1)
+
+	resultVal, resultErr := CountMergedSpatialIds(ids, 1)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if resultVal != int64(len(expectVal)) || resultVal != 2 {
+		t.Errorf("個数 - 期待値：%v, 取得値：%v", len(expectVal), resultVal)
+	}
+
+	t.Log("テスト終了")
+}
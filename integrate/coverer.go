@@ -0,0 +1,481 @@
+package integrate
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/shape"
+)
+
+// CoveringOptions 被覆の作成条件の構造体
+type CoveringOptions struct {
+	MinHZoom int64 // 被覆に使用する最小の水平方向精度
+	MaxHZoom int64 // 被覆に使用する最大の水平方向精度
+	MinVZoom int64 // 被覆に使用する最小の垂直方向精度
+	MaxVZoom int64 // 被覆に使用する最大の垂直方向精度
+	MaxCount int   // 被覆の拡張空間IDの最大個数
+}
+
+// coveringCell 被覆の拡張空間IDの成分
+type coveringCell struct {
+	hZoom int64 // 水平方向精度
+	x     int64 // 経度ID
+	y     int64 // 緯度ID
+	vZoom int64 // 垂直方向精度
+	z     int64 // 高さID
+}
+
+// coveringCandidate 被覆の拡張空間IDの分割候補
+type coveringCandidate struct {
+	cell     coveringCell   // 分割対象の拡張空間ID
+	children []coveringCell // 分割後の拡張空間IDのうち、形状と重なるもの
+	priority float64        // 増加する個数あたりの余剰体積の削減量
+}
+
+// coveringQueue 分割候補の優先度付きキュー
+//
+// container/heap で使用し、優先度の高い分割候補から取り出す。
+type coveringQueue []*coveringCandidate
+
+// GetCoveringOnGeometry ジオメトリの被覆取得関数
+//
+// ジオメトリを、精度の混在した拡張空間IDの個数上限以内の集合で被覆する。
+// 内部は粗い精度、境界は細かい精度の拡張空間IDとなる。
+//
+// ジオメトリを最大精度で拡張空間IDに変換し、GetCoveringOnExtendedSpatialIds で被覆する。
+// 最大精度での変換結果を形状とみなすため、最大精度の拡張空間IDの個数に比例したメモリを使用する。
+//
+// 引数：
+//
+//	geometry：ジオメトリ
+//	options ：被覆の作成条件
+//
+// 戻り値：
+//
+//	被覆する拡張空間IDのリスト
+//	余剰体積。被覆の体積から形状の体積を引いた値(単位:m³)
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過  ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 入力値不正    ：最小精度が最大精度より大きい場合、最大個数が1未満の場合、
+//	                 最小精度による被覆の個数が最大個数を超える場合。
+//	 ジオメトリ不正：ジオメトリがnilの場合、頂点数が不足している場合、未対応の種別の場合。
+func GetCoveringOnGeometry(geometry *shape.Geometry, options CoveringOptions) ([]string, float64, error) {
+	if err := checkCoveringOptions(options); err != nil {
+		return []string{}, 0, err
+	}
+
+	extendedSpatialIds, err := shape.GetExtendedSpatialIdsOnGeometry(geometry, options.MaxHZoom, options.MaxVZoom)
+	if err != nil {
+		return []string{}, 0, err
+	}
+
+	return GetCoveringOnExtendedSpatialIds(extendedSpatialIds, options)
+}
+
+// GetCoveringOnExtendedSpatialIds 拡張空間IDの被覆取得関数
+//
+// 拡張空間ID配列が表す形状を、精度の混在した拡張空間IDの個数上限以内の集合で被覆する。
+// 形状の各拡張空間IDの空間は、いずれかの被覆の拡張空間IDに含まれる。
+//
+// 最小精度の拡張空間IDによる被覆から開始し、形状を完全には満たさない拡張空間IDを、
+// 水平方向(4分割)または垂直方向(2分割)に分割して形状と重なるもののみを残す処理を繰り返す。
+// 分割は、増加する個数あたりの余剰体積の削減量が大きいものから、個数上限を超えない限り行う。
+// そのため、形状の内部は粗い精度、境界は細かい精度の拡張空間IDとなる。
+//
+// 最大精度より細かい拡張空間IDは、最大精度の拡張空間IDに精度を下げてから形状とみなす。
+// 最小精度による被覆が個数上限を超える場合は、個数上限以内で被覆できないためエラーとする。
+//
+// 引数：
+//
+//	extendedSpatialIds：形状を表す拡張空間ID文字列配列
+//	options           ：被覆の作成条件
+//
+// 戻り値：
+//
+//	被覆する拡張空間IDのリスト。精度、x成分、y成分、高さ成分の昇順で返却される。
+//	余剰体積。被覆の体積から形状の体積を引いた値(単位:m³)
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 入力値不正                ：最小精度が最大精度より大きい場合、最大個数が1未満の場合、
+//	                             最小精度による被覆の個数が最大個数を超える場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"拡張空間ID配列"に入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのx成分、y成分が精度の範囲外の場合。
+func GetCoveringOnExtendedSpatialIds(extendedSpatialIds []string, options CoveringOptions) ([]string, float64, error) {
+	if err := checkCoveringOptions(options); err != nil {
+		return []string{}, 0, err
+	}
+
+	set, err := newZoomChangeRangeSet(extendedSpatialIds, options.MaxHZoom, options.MaxVZoom)
+	if err != nil {
+		return []string{}, 0, err
+	}
+	root := set.getRoot()
+
+	// 最小精度による被覆
+	cells := map[coveringCell]struct{}{}
+	set.forEachCell(options.MinHZoom, options.MinVZoom, func(hZoom, x, y, vZoom, z int64) {
+		hShift := hZoom - options.MinHZoom
+		vShift := vZoom - options.MinVZoom
+		cells[coveringCell{options.MinHZoom, x >> hShift, y >> hShift, options.MinVZoom, z >> vShift}] = struct{}{}
+	})
+	if len(cells) > options.MaxCount {
+		return []string{}, 0, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("covering at min zoom exceeds max count: %v > %v", len(cells), options.MaxCount))
+	}
+
+	initialCells := make([]coveringCell, 0, len(cells))
+	for cell := range cells {
+		initialCells = append(initialCells, cell)
+	}
+	sortCoveringCells(initialCells)
+
+	queue := &coveringQueue{}
+	for _, cell := range initialCells {
+		if candidate := newCoveringCandidate(root, cell, options); candidate != nil {
+			heap.Push(queue, candidate)
+		}
+	}
+
+	// 個数上限を超えない限り、余剰体積を効率よく削減できる拡張空間IDから分割
+	for queue.Len() > 0 {
+		candidate := heap.Pop(queue).(*coveringCandidate)
+		if len(cells)+len(candidate.children)-1 > options.MaxCount {
+			continue
+		}
+
+		delete(cells, candidate.cell)
+		for _, child := range candidate.children {
+			cells[child] = struct{}{}
+			if childCandidate := newCoveringCandidate(root, child, options); childCandidate != nil {
+				heap.Push(queue, childCandidate)
+			}
+		}
+	}
+
+	coveringCells := make([]coveringCell, 0, len(cells))
+	coveringVolume := 0.0
+	for cell := range cells {
+		coveringCells = append(coveringCells, cell)
+		coveringVolume += cell.volume()
+	}
+	sortCoveringCells(coveringCells)
+
+	shapeVolume := 0.0
+	walkHorizontalNode(root, 0, 0, 0, func(zoom, x, y int64, vertical verticalSet) {
+		height := 0.0
+		for key, node := range vertical {
+			walkVerticalNode(node, 0, key, func(zoom, z int64) {
+				height += getVerticalResolution(zoom)
+			})
+		}
+		shapeVolume += getTileArea(zoom, x, y) * height
+	})
+
+	covering := make([]string, 0, len(coveringCells))
+	for _, cell := range coveringCells {
+		covering = append(covering, formatExtendedSpatialId(cell.hZoom, cell.x, cell.y, cell.vZoom, cell.z))
+	}
+
+	return covering, coveringVolume - shapeVolume, nil
+}
+
+// checkCoveringOptions 被覆の作成条件の検証
+//
+// 引数：
+//
+//	options：被覆の作成条件
+//
+// 戻り値(エラー)：
+//
+//	精度、最大個数が不正な場合エラーを返却する。
+func checkCoveringOptions(options CoveringOptions) error {
+	for _, zoom := range []int64{options.MinHZoom, options.MaxHZoom, options.MinVZoom, options.MaxVZoom} {
+		if !shape.CheckZoom(zoom) {
+			return errors.NewSpatialIdError(errors.InputValueErrorCode, "")
+		}
+	}
+	if options.MinHZoom > options.MaxHZoom || options.MinVZoom > options.MaxVZoom {
+		return errors.NewSpatialIdError(errors.InputValueErrorCode, "min zoom is greater than max zoom")
+	}
+	if options.MaxCount < 1 {
+		return errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("max count is out of range: %v", options.MaxCount))
+	}
+	return nil
+}
+
+// newCoveringCandidate 拡張空間IDの分割候補の作成
+//
+// 水平方向、垂直方向の分割のうち、増加する個数あたりの余剰体積の削減量が大きい方を分割候補とする。
+//
+// 引数：
+//
+//	root   ：形状を表す集合の4分木のルート
+//	cell   ：分割対象の拡張空間ID
+//	options：被覆の作成条件
+//
+// 戻り値：
+//
+//	分割候補。形状に満たされている場合、最大精度で分割できない場合はnil。
+func newCoveringCandidate(root *horizontalNode, cell coveringCell, options CoveringOptions) *coveringCandidate {
+	if isCellFilled(root, cell) {
+		return nil
+	}
+
+	var best *coveringCandidate
+	splits := [][]coveringCell{}
+	if cell.hZoom < options.MaxHZoom {
+		splits = append(splits, cell.horizontalChildren())
+	}
+	if cell.vZoom < options.MaxVZoom {
+		splits = append(splits, cell.verticalChildren())
+	}
+
+	for _, split := range splits {
+		candidate := &coveringCandidate{cell: cell}
+		reduction := cell.volume()
+		for _, child := range split {
+			if getCellCoverage(root, child) > 0 {
+				candidate.children = append(candidate.children, child)
+				reduction -= child.volume()
+			}
+		}
+
+		// 個数が増加しない分割は常に優先する
+		added := len(candidate.children) - 1
+		candidate.priority = math.Inf(1)
+		if added > 0 {
+			candidate.priority = reduction / float64(added)
+		}
+
+		if best == nil || candidate.priority > best.priority {
+			best = candidate
+		}
+	}
+
+	return best
+}
+
+// getCellCoverage 拡張空間IDと形状の重なる体積の取得
+//
+// 引数：
+//
+//	root：形状を表す集合の4分木のルート
+//	cell：拡張空間ID
+//
+// 戻り値：
+//
+//	重なる体積(単位:m³)
+func getCellCoverage(root *horizontalNode, cell coveringCell) float64 {
+	node := getHorizontalNodeOnCell(root, cell)
+	if node == nil {
+		return 0
+	}
+
+	if node.children == nil {
+		return getTileArea(cell.hZoom, cell.x, cell.y) * getVerticalCoverage(node.vertical, cell)
+	}
+
+	covered := 0.0
+	walkHorizontalNode(node, cell.hZoom, cell.x, cell.y, func(zoom, x, y int64, vertical verticalSet) {
+		covered += getTileArea(zoom, x, y) * getVerticalCoverage(vertical, cell)
+	})
+	return covered
+}
+
+// isCellFilled 拡張空間IDが形状に満たされているかの判定
+//
+// 引数：
+//
+//	root：形状を表す集合の4分木のルート
+//	cell：拡張空間ID
+//
+// 戻り値：
+//
+//	拡張空間IDの空間全体が形状に含まれる場合true
+func isCellFilled(root *horizontalNode, cell coveringCell) bool {
+	return isHorizontalNodeFilled(getHorizontalNodeOnCell(root, cell), cell)
+}
+
+// isHorizontalNodeFilled 4分木のノードが高さ方向の区間を満たすかの判定
+//
+// 引数：
+//
+//	node：4分木のノード
+//	cell：高さ方向の区間を表す拡張空間ID
+//
+// 戻り値：
+//
+//	ノードのタイル全体で区間を満たす場合true
+func isHorizontalNodeFilled(node *horizontalNode, cell coveringCell) bool {
+	if node == nil {
+		return false
+	}
+	if node.children == nil {
+		return isVerticalNodeFull(getVerticalNodeOnCell(node.vertical, cell))
+	}
+	for _, child := range node.children {
+		if !isHorizontalNodeFilled(child, cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// getHorizontalNodeOnCell 拡張空間IDのタイルに対応する4分木のノードの取得
+//
+// 引数：
+//
+//	root：4分木のルート
+//	cell：拡張空間ID
+//
+// 戻り値：
+//
+//	タイルに対応するノード。タイルを含む葉がある場合は葉、形状と重ならない場合はnil。
+func getHorizontalNodeOnCell(root *horizontalNode, cell coveringCell) *horizontalNode {
+	node := root
+	for depth := int64(0); depth < cell.hZoom && !isHorizontalLeaf(node); depth++ {
+		shift := cell.hZoom - depth - 1
+		node = node.children[((cell.x>>shift)&1)+2*((cell.y>>shift)&1)]
+	}
+	return node
+}
+
+// getVerticalNodeOnCell 拡張空間IDの高さ方向の区間に対応する2分木のノードの取得
+//
+// 引数：
+//
+//	vertical：2分木の森
+//	cell    ：拡張空間ID
+//
+// 戻り値：
+//
+//	区間に対応するノード。区間を含む全体を満たすノードがある場合はそのノード、重ならない場合はnil。
+func getVerticalNodeOnCell(vertical verticalSet, cell coveringCell) *verticalNode {
+	node := vertical[cell.z>>cell.vZoom]
+	for depth := int64(0); depth < cell.vZoom && !isVerticalLeaf(node); depth++ {
+		node = node.children[(cell.z>>(cell.vZoom-depth-1))&1]
+	}
+	return node
+}
+
+// isVerticalNodeFull 2分木のノードが区間全体を満たすかの判定
+//
+// 引数：
+//
+//	node：2分木のノード
+//
+// 戻り値：
+//
+//	区間全体を満たす場合true
+func isVerticalNodeFull(node *verticalNode) bool {
+	return node != nil && node.full
+}
+
+// getVerticalCoverage 拡張空間IDの高さ方向の区間と2分木の森の重なる高さの取得
+//
+// 引数：
+//
+//	vertical：2分木の森
+//	cell    ：拡張空間ID
+//
+// 戻り値：
+//
+//	重なる高さ(単位:m)
+func getVerticalCoverage(vertical verticalSet, cell coveringCell) float64 {
+	node := getVerticalNodeOnCell(vertical, cell)
+	if isVerticalNodeFull(node) {
+		return getVerticalResolution(cell.vZoom)
+	}
+
+	height := 0.0
+	walkVerticalNode(node, cell.vZoom, cell.z, func(zoom, z int64) {
+		height += getVerticalResolution(zoom)
+	})
+	return height
+}
+
+// sortCoveringCells 被覆の拡張空間IDの整列
+//
+// 引数：
+//
+//	cells：被覆の拡張空間ID。精度、x成分、y成分、高さ成分の昇順に整列される。
+func sortCoveringCells(cells []coveringCell) {
+	sort.Slice(cells, func(i, j int) bool {
+		a, b := cells[i], cells[j]
+		if a.hZoom != b.hZoom {
+			return a.hZoom < b.hZoom
+		}
+		if a.vZoom != b.vZoom {
+			return a.vZoom < b.vZoom
+		}
+		if a.x != b.x {
+			return a.x < b.x
+		}
+		if a.y != b.y {
+			return a.y < b.y
+		}
+		return a.z < b.z
+	})
+}
+
+// volume 拡張空間IDの体積の取得
+//
+// 戻り値：
+//
+//	体積(単位:m³)
+func (c coveringCell) volume() float64 {
+	return getTileArea(c.hZoom, c.x, c.y) * getVerticalResolution(c.vZoom)
+}
+
+// horizontalChildren 水平方向に分割した拡張空間IDの取得
+//
+// 戻り値：
+//
+//	水平方向精度を1つ上げた4つの拡張空間ID
+func (c coveringCell) horizontalChildren() []coveringCell {
+	children := make([]coveringCell, 0, 4)
+	for index := int64(0); index < 4; index++ {
+		children = append(children, coveringCell{c.hZoom + 1, 2*c.x + index%2, 2*c.y + index/2, c.vZoom, c.z})
+	}
+	return children
+}
+
+// verticalChildren 垂直方向に分割した拡張空間IDの取得
+//
+// 戻り値：
+//
+//	垂直方向精度を1つ上げた2つの拡張空間ID
+func (c coveringCell) verticalChildren() []coveringCell {
+	return []coveringCell{
+		{c.hZoom, c.x, c.y, c.vZoom + 1, 2 * c.z},
+		{c.hZoom, c.x, c.y, c.vZoom + 1, 2*c.z + 1},
+	}
+}
+
+// Len heap.Interface の要素数
+func (q coveringQueue) Len() int { return len(q) }
+
+// Less heap.Interface の比較。優先度の高い分割候補を先とする。
+func (q coveringQueue) Less(i, j int) bool { return q[i].priority > q[j].priority }
+
+// Swap heap.Interface の要素の交換
+func (q coveringQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+// Push heap.Interface の要素の追加
+func (q *coveringQueue) Push(x any) { *q = append(*q, x.(*coveringCandidate)) }
+
+// Pop heap.Interface の末尾の要素の取り出し
+func (q *coveringQueue) Pop() any {
+	old := *q
+	candidate := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return candidate
+}
@@ -0,0 +1,145 @@
+package integrate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+	"github.com/trajectoryjp/spatial_id_go/v4/shape"
+)
+
+// TestGetCoveringOnExtendedSpatialIds01 拡張空間IDの被覆取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：{"2/0/0/2/0", "2/1/0/2/0"}、精度0～2、最大個数1
+//   - パターン2：パターン1と同じ拡張空間ID、最大個数2
+//
+// + 確認内容
+//   - パターン1：個数の増加しない分割のみ行われ、2つを含む"1/0/0/2/0"と余剰体積が返却されること
+//   - パターン2：入力と同じ拡張空間IDと余剰体積0が返却されること
+func TestGetCoveringOnExtendedSpatialIds01(t *testing.T) {
+	ids := []string{"2/0/0/2/0", "2/1/0/2/0"}
+	options := CoveringOptions{MinHZoom: 0, MaxHZoom: 2, MinVZoom: 0, MaxVZoom: 2, MaxCount: 1}
+
+	// パターン1
+	resultVal, resultExcess, resultErr := GetCoveringOnExtendedSpatialIds(ids, options)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	expectVal := []string{"1/0/0/2/0"}
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("被覆 - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+	expectExcess := (getTileArea(1, 0, 0) - getTileArea(2, 0, 0) - getTileArea(2, 1, 0)) * getVerticalResolution(2)
+	if !common.AlmostEqual(resultExcess/expectExcess, 1, 1e-9) {
+		t.Errorf("余剰体積 - 期待値：%v, 取得値：%v", expectExcess, resultExcess)
+	}
+
+	// パターン2
+	options.MaxCount = 2
+	resultVal, resultExcess, resultErr = GetCoveringOnExtendedSpatialIds(ids, options)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	if !reflect.DeepEqual(resultVal, ids) {
+		t.Errorf("被覆 - 期待値：%v, 取得値：%v", ids, resultVal)
+	}
+	if resultExcess != 0 {
+		t.Errorf("余剰体積 - 期待値：0, 取得値：%v", resultExcess)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetCoveringOnExtendedSpatialIds02 拡張空間IDの被覆取得関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：最大個数0
+//   - パターン2：最小精度が最大精度より大きい
+//   - パターン3：精度閾値超過
+//   - パターン4：{"2/0/0/2/0", "2/3/3/2/0"}、精度2、最大個数1(最小精度による被覆が最大個数超過)
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetCoveringOnExtendedSpatialIds02(t *testing.T) {
+	testCases := []struct {
+		options   CoveringOptions
+		expectErr string
+	}{
+		{CoveringOptions{MaxHZoom: 2, MaxVZoom: 2, MaxCount: 0}, "InputValueError,入力チェックエラー,max count is out of range: 0"},
+		{CoveringOptions{MinHZoom: 3, MaxHZoom: 2, MaxVZoom: 2, MaxCount: 1}, "InputValueError,入力チェックエラー,min zoom is greater than max zoom"},
+		{CoveringOptions{MaxHZoom: 36, MaxVZoom: 2, MaxCount: 1}, "InputValueError,入力チェックエラー"},
+	}
+
+	for _, testCase := range testCases {
+		_, _, resultErr := GetCoveringOnExtendedSpatialIds([]string{"2/0/0/2/0"}, testCase.options)
+		if resultErr == nil || resultErr.Error() != testCase.expectErr {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expectErr, resultErr)
+		}
+	}
+
+	options := CoveringOptions{MinHZoom: 2, MaxHZoom: 2, MinVZoom: 2, MaxVZoom: 2, MaxCount: 1}
+	_, _, resultErr := GetCoveringOnExtendedSpatialIds([]string{"2/0/0/2/0", "2/3/3/2/0"}, options)
+	if expectErr := "InputValueError,入力チェックエラー,covering at min zoom exceeds max count: 2 > 1"; resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetCoveringOnGeometry01 ジオメトリの被覆取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：高さ0m～100mの三角形、水平方向精度14～18、垂直方向精度20～23、最大個数100
+//
+// + 確認内容
+//   - 最大個数以内の拡張空間IDが返却されること
+//   - 最大精度で変換した形状を包含すること
+//   - 精度の混在した被覆であること
+//   - 余剰体積が被覆の体積と形状の体積の差であること
+func TestGetCoveringOnGeometry01(t *testing.T) {
+	p1, _ := object.NewPoint(139.75, 35.68, 0)
+	p2, _ := object.NewPoint(139.76, 35.68, 100)
+	p3, _ := object.NewPoint(139.75, 35.69, 0)
+	geometry := &shape.Geometry{Type: shape.PolygonGeometry, Polygons: [][][]*object.Point{{{p1, p2, p3}}}}
+	options := CoveringOptions{MinHZoom: 14, MaxHZoom: 18, MinVZoom: 20, MaxVZoom: 23, MaxCount: 100}
+
+	resultVal, resultExcess, resultErr := GetCoveringOnGeometry(geometry, options)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	if len(resultVal) == 0 || len(resultVal) > options.MaxCount {
+		t.Errorf("個数 - 期待値：1～%v, 取得値：%v", options.MaxCount, len(resultVal))
+	}
+
+	shapeIds, _ := shape.GetExtendedSpatialIdsOnGeometry(geometry, options.MaxHZoom, options.MaxVZoom)
+	shapeSet, _ := NewSpatialIDSet(shapeIds)
+	coveringSet, _ := NewSpatialIDSet(resultVal)
+	if !coveringSet.Contains(shapeSet) {
+		t.Errorf("被覆 - 形状を包含していない：%v", resultVal)
+	}
+
+	zooms := map[[2]int64]struct{}{}
+	for _, id := range resultVal {
+		extendedSpatialID, _ := object.NewExtendedSpatialID(id)
+		zooms[[2]int64{extendedSpatialID.HZoom(), extendedSpatialID.VZoom()}] = struct{}{}
+	}
+	if len(zooms) < 2 {
+		t.Errorf("精度 - 期待値：複数, 取得値：%v", zooms)
+	}
+
+	shapeVolume, _ := GetVolumeOnExtendedSpatialIds(shapeIds)
+	coveringVolume, _ := GetVolumeOnExtendedSpatialIds(resultVal)
+	if !common.AlmostEqual(resultExcess, coveringVolume-shapeVolume, 1e-3) {
+		t.Errorf("余剰体積 - 期待値：%v, 取得値：%v", coveringVolume-shapeVolume, resultExcess)
+	}
+
+	t.Log("テスト終了")
+}
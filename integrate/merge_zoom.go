@@ -1,12 +1,15 @@
 package integrate
 
 import (
+	"fmt"
 	"math"
+	"math/big"
 	"strconv"
 	"strings"
 
 	"github.com/trajectoryjp/spatial_id_go/v4/common"
 	"github.com/trajectoryjp/spatial_id_go/v4/common/consts"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
 	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
 	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
 	"github.com/trajectoryjp/spatial_id_go/v4/shape"
@@ -176,14 +179,37 @@ func (r HighSpatialID) IsDense() bool {
 //
 // 補足事項：
 //
-//	多数の空間IDのマージを行う場合について：
-//	 空間IDのマージ処理では内部で空間IDを配列内の一番小さい空間IDに合わせて精度を上げて分割する処理を行っている。
-//	 空間IDにおける精度を上げる場合、精度が1上がるごとに8のべき乗で空間ID数が増加する。
-//	 そのため、以下のような場合に分割後の空間ID数は大幅に増大する。
-//	  * 入力の空間ID配列全体での精度の幅が大きい場合。
-//	  * 空間IDが大量に入力された場合。
-//	 動作環境によってはメモリ不足となる可能性があるため、注意すること。
+//	稠密であるかの判定について：
+//	 マージ後の空間IDごとに、マージ元の空間IDを4分木、2分木の集合にまとめ、集合の体積から判定する。
+//	 単位空間IDへの分割は行わないため、計算量は入力の精度の差ではなく、空間IDの個数と精度に比例する。
+//	 マージ前に結果の個数を確認する場合は CountMergedSpatialIds を使用すること。
 func MergeSpatialIds(spatialIds []string, zoom int64) ([]string, error) {
+	return MergeSpatialIdsWithOption(spatialIds, zoom, enum.ExactMerge, 100)
+}
+
+// MergeSpatialIdsWithOption オプション指定の空間IDの最適化（マージ）関数
+//
+// MergeSpatialIds のマージ条件をオプションで指定する。
+// オプションごとのマージ条件は MergeExtendedSpatialIdsWithOption のドキュメントを参照。
+//
+// 引数：
+//
+//	spatialIds：マージ対象の空間ID文字列配列
+//	zoom      ：マージ後の精度。空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	option    ：マージオプション
+//	fillRatio ：FillRatioMerge の場合のマージする充填率の閾値(単位:%)。0 より大きく 100 以下の値を指定可能。
+//
+// 戻り値：
+//
+//	マージ後の空間IDを格納した配列が返却される。IDの重複は解消された形で返却される。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過          ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 空間IDフォーマット不正：空間IDのフォーマットに違反する値が"空間ID配列"に入力されていた場合。
+//	 オプション不正        ：未対応のマージオプション、範囲外の充填率の閾値が入力されていた場合。
+func MergeSpatialIdsWithOption(spatialIds []string, zoom int64, option enum.MergeOption, fillRatio float64) ([]string, error) {
 	// マージ後の空間ID格納用のスライス
 	resultIDs := []string{}
 
@@ -197,10 +223,12 @@ func MergeSpatialIds(spatialIds []string, zoom int64) ([]string, error) {
 	}
 
 	// 拡張空間IDのマージを実行
-	mergeIDs, mergeZoomErr := MergeExtendedSpatialIds(
+	mergeIDs, mergeZoomErr := MergeExtendedSpatialIdsWithOption(
 		extendedSpatialIds,
 		zoom,
 		zoom,
+		option,
+		fillRatio,
 	)
 
 	if mergeZoomErr != nil {
@@ -250,19 +278,58 @@ func MergeSpatialIds(spatialIds []string, zoom int64) ([]string, error) {
 //
 // 補足事項：
 //
-//	多数の拡張空間IDのマージを行う場合について：
-//	 拡張空間IDのマージ処理では内部で拡張空間IDを配列内の一番小さい拡張空間IDに合わせて精度を上げて分割する処理を行っている。
-//	 拡張空間IDにおける精度を上げる場合、水平精度が1上がるごとに4のべき乗、垂直方向精度が1上がるごとに2のべき乗で拡張空間ID数が増加する。
-//	 そのため、以下のような場合に分割後の拡張空間ID数は大幅に増大する。
-//	  * 入力の拡張空間ID配列全体での精度の幅が大きい場合。
-//	  * 入力の拡張空間ID内の水平精度と垂直精度に大きい差分がある場合。
-//	  * 拡張空間IDが大量に入力された場合。
-//	 動作環境によってはメモリ不足となる可能性があるため、注意すること。
+//	稠密であるかの判定について：
+//	 マージ後の拡張空間IDごとに、マージ元の拡張空間IDを4分木、2分木の集合にまとめ、集合の体積から判定する。
+//	 単位拡張空間IDへの分割は行わないため、計算量は入力の精度の差ではなく、拡張空間IDの個数と精度に比例する。
+//	 マージ前に結果の個数を確認する場合は CountMergedExtendedSpatialIds を使用すること。
 func MergeExtendedSpatialIds(extendedSpatialIds []string, hZoom, vZoom int64) ([]string, error) {
+	return MergeExtendedSpatialIdsWithOption(extendedSpatialIds, hZoom, vZoom, enum.ExactMerge, 100)
+}
+
+// MergeExtendedSpatialIdsWithOption オプション指定の拡張空間IDの最適化（マージ）関数
+//
+// MergeExtendedSpatialIds のマージ条件をオプションで指定する。
+// マージ対象となる拡張空間IDの条件、マージ対象外の拡張空間IDの扱いは MergeExtendedSpatialIds と同じとなる。
+//
+// マージ後の精度の拡張空間IDボクセルごとの、マージ条件とマージ条件に合致しなかった拡張空間IDの扱いは以下となる。
+//
+//	・ExactMerge    ：ボクセル内を完全に満たす場合にマージする。合致しなかった拡張空間IDはそのまま返却する(MergeExtendedSpatialIds と同じ)。
+//	・FillRatioMerge：ボクセル内の体積のうち fillRatio % 以上を満たす場合にマージする。合致しなかった拡張空間IDはそのまま返却する。
+//	                  結果は入力の空間を包含する(過大近似)。粗い表示用途に使用する。
+//	・DenseOnlyMerge：ボクセル内を完全に満たす場合にマージする。合致しなかった拡張空間IDは除去する。
+//	                  結果は入力の空間に包含される(過小近似)。安全側の判定用途に使用する。
+//
+// 引数：
+//
+//	extendedSpatialIds：マージ対象の拡張空間ID文字列配列
+//	hZoom             ：マージ後の水平方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	vZoom             ：マージ後の垂直方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	option            ：マージオプション
+//	fillRatio         ：FillRatioMerge の場合のマージする充填率の閾値(単位:%)。0 より大きく 100 以下の値を指定可能。
+//	                    その他のオプションの場合は使用しない。
+//
+// 戻り値：
+//
+//	マージ後の拡張空間IDを格納した配列が返却される。IDの重複は解消された形で返却される。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"拡張空間ID配列"に入力されていた場合。
+//	 オプション不正            ：未対応のマージオプション、範囲外の充填率の閾値が入力されていた場合。
+//
+// 補足事項：
+//
+//	稠密であるかの判定は MergeExtendedSpatialIds と同様に、単位拡張空間IDへの分割を行わずに集合の体積から行う。
+func MergeExtendedSpatialIdsWithOption(
+	extendedSpatialIds []string,
+	hZoom, vZoom int64,
+	option enum.MergeOption,
+	fillRatio float64,
+) ([]string, error) {
 	// 変換後の拡張空間ID格納用のスライス
 	resultIDs := []string{}
-	// 拡張空間ID配列
-	spatialIDs := []*object.ExtendedSpatialID{}
 
 	// 入力値チェック
 	// 水平、垂直方向精度のどちらかが範囲外の場合、空配列とエラーインスタンスを返却
@@ -270,80 +337,151 @@ func MergeExtendedSpatialIds(extendedSpatialIds []string, hZoom, vZoom int64) ([
 		return resultIDs, errors.NewSpatialIdError(errors.InputValueErrorCode, "")
 	}
 
-	// 最大水平精度
-	maxHZoom := int64(0)
-	// 最大垂直精度
-	maxVZoom := int64(0)
-	// 拡張空間ID配列を作成しつつ、最大水平/垂直精度を算出
-	for _, extendedSpatialID := range extendedSpatialIds {
-		spatialID, err := object.NewExtendedSpatialID(extendedSpatialID)
-
-		if err != nil {
-			// 拡張空間ID初期化時にエラーが発生した場合、フォーマットチェックエラーとしてエラーインスタンスを返却
-			return resultIDs, err
+	// マージオプションのチェック
+	switch option {
+	case enum.ExactMerge, enum.DenseOnlyMerge:
+	case enum.FillRatioMerge:
+		if !(fillRatio > 0 && fillRatio <= 100) {
+			return resultIDs, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("fill ratio is out of range: %v", fillRatio))
 		}
+	default:
+		return resultIDs, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("unsupported merge option: %v", option))
+	}
 
-		// 入力精度より細かい精度もしくは等しい精度の場合はマージ対象の空間IDとする。
-		if spatialID.HZoom() >= hZoom && spatialID.VZoom() >= vZoom {
-			spatialIDs = append(spatialIDs, spatialID)
+	// マージ条件
+	isMerged := (*mergeGroup).isDense
+	if option == enum.FillRatioMerge {
+		isMerged = func(group *mergeGroup) bool { return group.fillRatio() >= fillRatio }
+	}
 
-			// 入力精度より粗い精度の場合は処理を行わず、結果空間IDに格納
-		} else {
-			resultIDs = append(resultIDs, spatialID.ID())
-		}
+	// 過小近似の場合はマージ条件に合致しない拡張空間IDを除去
+	return mergeExtendedSpatialIds(extendedSpatialIds, hZoom, vZoom, isMerged, option != enum.DenseOnlyMerge)
+}
 
-		// 最大水平/垂直精度を更新
-		if spatialID.HZoom() > maxHZoom {
-			maxHZoom = spatialID.HZoom()
-		}
-		if spatialID.VZoom() > maxVZoom {
-			maxVZoom = spatialID.VZoom()
-		}
+// MergeSpatialIdsWithMode 近似方法指定の空間IDの最適化（マージ）関数
+//
+// MergeSpatialIds のマージ条件を近似方法で指定する。
+// 近似方法ごとのマージ条件は MergeExtendedSpatialIdsWithMode のドキュメントを参照。
+//
+// 引数：
+//
+//	spatialIds：マージ対象の空間ID文字列配列
+//	zoom      ：マージ後の精度。空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	mode      ：近似方法
+//
+// 戻り値：
+//
+//	マージ後の空間IDを格納した配列が返却される。IDの重複は解消された形で返却される。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過          ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 空間IDフォーマット不正：空間IDのフォーマットに違反する値が"空間ID配列"に入力されていた場合。
+//	 近似方法不正          ：未対応の近似方法が入力されていた場合。
+func MergeSpatialIdsWithMode(spatialIds []string, zoom int64, mode enum.CoveringMode) ([]string, error) {
+	extendedSpatialIds, err := shape.ConvertSpatialIdsToExtendedSpatialIds(spatialIds)
+	if err != nil {
+		return []string{}, err
 	}
 
-	unitSpatialIDs := []*UnitDividedSpatialID{}
-	for _, spatialID := range spatialIDs {
-		// 最大水平/垂直精度を単位分割の単位になるようにして、単位分割空間ID作成
-		unitSpatialID := NewUnitDividedSpatialID(
-			spatialID,
-			maxHZoom-spatialID.HZoom(),
-			maxVZoom-spatialID.VZoom(),
-		)
-		unitSpatialIDs = append(unitSpatialIDs, unitSpatialID)
+	mergeIDs, err := MergeExtendedSpatialIdsWithMode(extendedSpatialIds, zoom, zoom, mode)
+	if err != nil {
+		return []string{}, err
 	}
 
-	// 最適化後空間ID辞書
-	highSpatialIDs := map[string]*HighSpatialID{}
-	for _, unitSpatialID := range unitSpatialIDs {
+	resultIDs, _ := shape.ConvertExtendedSpatialIdsToSpatialIds(mergeIDs)
 
-		// 最適化後空間ID
-		highSpatialID := NewHighSpatialID(
-			unitSpatialID,
-			unitSpatialID.HZoom()-hZoom,
-			unitSpatialID.VZoom()-vZoom,
-		)
+	return resultIDs, nil
+}
 
-		// 最適化後空間IDが辞書に登録済みの場合は最適化後空間ID構造体を結合
-		if val, ok := highSpatialIDs[highSpatialID.ID()]; ok {
-			val.Merge(highSpatialID)
+// MergeExtendedSpatialIdsWithMode 近似方法指定の拡張空間IDの最適化（マージ）関数
+//
+// ChangeExtendedSpatialIdsZoomWithMode と同じ近似方法で MergeExtendedSpatialIdsWithOption を実行する。
+// 近似方法ごとに、以下のマージオプションを指定した MergeExtendedSpatialIdsWithOption と同じ結果となる。
+//
+//	・OuterCovering：充填率の閾値を最小の正の値とした FillRatioMerge。
+//	                 ボクセル内にマージ元の拡張空間IDがある場合は常にマージする。結果は入力の空間を包含する(過大近似)。
+//	・InnerCovering：DenseOnlyMerge。
+//	                 ボクセル内を完全に満たす場合のみマージし、満たさない場合はマージ元の拡張空間IDを除去する。結果は入力の空間に包含される(過小近似)。
+//
+// 引数：
+//
+//	extendedSpatialIds：マージ対象の拡張空間ID文字列配列
+//	hZoom             ：マージ後の水平方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	vZoom             ：マージ後の垂直方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	mode              ：近似方法
+//
+// 戻り値：
+//
+//	マージ後の拡張空間IDを格納した配列が返却される。IDの重複は解消された形で返却される。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"拡張空間ID配列"に入力されていた場合。
+//	 近似方法不正              ：未対応の近似方法が入力されていた場合。
+func MergeExtendedSpatialIdsWithMode(
+	extendedSpatialIds []string,
+	hZoom, vZoom int64,
+	mode enum.CoveringMode,
+) ([]string, error) {
+	switch mode {
+	case enum.OuterCovering:
+		return MergeExtendedSpatialIdsWithOption(extendedSpatialIds, hZoom, vZoom, enum.FillRatioMerge, math.SmallestNonzeroFloat64)
+	case enum.InnerCovering:
+		return MergeExtendedSpatialIdsWithOption(extendedSpatialIds, hZoom, vZoom, enum.DenseOnlyMerge, 100)
+	default:
+		return []string{}, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("unsupported covering mode: %v", mode))
+	}
+}
 
-			// 未登録の場合は辞書に登録
-		} else {
-			highSpatialIDs[highSpatialID.ID()] = highSpatialID
-		}
+// mergeExtendedSpatialIds マージ条件指定の拡張空間IDのマージ
+//
+// 引数：
+//
+//	extendedSpatialIds：マージ対象の拡張空間ID文字列配列
+//	hZoom             ：マージ後の水平方向精度
+//	vZoom             ：マージ後の垂直方向精度
+//	isMerged          ：マージ後の拡張空間IDごとのマージ条件
+//	keepsUnmerged     ：マージ条件に合致しなかったマージ元の拡張空間IDを返却する場合true
+//
+// 戻り値：
+//
+//	マージ後の拡張空間IDを格納した配列
+//
+// 戻り値(エラー)：
+//
+//	拡張空間IDが不正な場合エラーを返却する。
+func mergeExtendedSpatialIds(
+	extendedSpatialIds []string,
+	hZoom, vZoom int64,
+	isMerged func(group *mergeGroup) bool,
+	keepsUnmerged bool,
+) ([]string, error) {
+	// 変換後の拡張空間ID格納用のスライス
+	resultIDs := []string{}
+
+	// マージ対象外の拡張空間IDとマージ後の拡張空間IDごとのマージ元の拡張空間ID
+	coarseIDs, groups, err := groupMergeTargets(extendedSpatialIds, hZoom, vZoom)
+	if err != nil {
+		return resultIDs, err
 	}
+	// 入力精度より粗い精度の場合は処理を行わず、結果空間IDに格納
+	resultIDs = append(resultIDs, coarseIDs...)
 
 	// 最適化後空間IDごとに処理
-	for _, highSpatialID := range highSpatialIDs {
-		// 単位分割数が単位空間ID集合の数に等しい場合
-		if highSpatialID.IsDense() {
+	for _, group := range groups {
+		// マージ条件に合致する場合
+		if isMerged(group) {
 			// 最適化後空間IDを結果空間ID配列に格納
-			resultIDs = append(resultIDs, highSpatialID.ID())
+			resultIDs = append(resultIDs, group.highID)
 
-			// 単位分割数が単位空間ID集合の数と異なる場合
-		} else {
+			// マージ条件に合致しない場合(過小近似の場合は除去)
+		} else if keepsUnmerged {
 			// 最適化元空間ID配列を結果空間ID配列に格納
-			resultIDs = append(resultIDs, highSpatialID.lowIDs...)
+			resultIDs = append(resultIDs, group.lowIDs...)
 		}
 	}
 
@@ -352,3 +490,121 @@ func MergeExtendedSpatialIds(extendedSpatialIds []string, hZoom, vZoom int64) ([
 
 	return resultIDs, nil
 }
+
+// mergeGroup マージ後の拡張空間IDごとのマージ元の拡張空間IDの構造体
+type mergeGroup struct {
+	highID    string   // マージ後の拡張空間ID
+	lowIDs    []string // マージ元の拡張空間ID(重複なし)
+	unitCount *big.Int // マージ元の拡張空間IDが覆う単位拡張空間IDの個数
+	threshold *big.Int // マージ後の拡張空間IDに含まれる単位拡張空間IDの個数
+}
+
+// isDense マージ後の拡張空間IDが稠密であるかの判定
+//
+// 戻り値：
+//
+//	稠密な場合true
+func (g *mergeGroup) isDense() bool {
+	return g.unitCount.Cmp(g.threshold) == 0
+}
+
+// fillRatio マージ後の拡張空間IDの充填率の取得
+//
+// 戻り値：
+//
+//	充填率(単位:%)
+func (g *mergeGroup) fillRatio() float64 {
+	ratio, _ := new(big.Rat).SetFrac(g.unitCount, g.threshold).Float64()
+	return ratio * 100
+}
+
+// groupMergeTargets マージ対象の拡張空間IDのマージ後の拡張空間IDごとの振り分け
+//
+// マージ後の精度より粗い拡張空間IDはマージ対象外とし、それ以外の拡張空間IDは
+// ExtendedSpatialID.Higher で求まるマージ後の拡張空間IDごとにまとめる。
+//
+// 単位拡張空間IDの個数は、まとめた拡張空間IDの4分木、2分木の集合の体積から求めるため、
+// 計算量は入力の精度の差ではなく、拡張空間IDの個数と精度に比例する。
+//
+// 引数：
+//
+//	extendedSpatialIds：マージ対象の拡張空間ID文字列配列
+//	hZoom             ：マージ後の水平方向精度
+//	vZoom             ：マージ後の垂直方向精度
+//
+// 戻り値：
+//
+//	マージ対象外の拡張空間ID
+//	マージ後の拡張空間IDごとのマージ元の拡張空間ID
+//
+// 戻り値(エラー)：
+//
+//	拡張空間IDが不正な場合エラーを返却する。
+func groupMergeTargets(extendedSpatialIds []string, hZoom, vZoom int64) ([]string, []*mergeGroup, error) {
+	coarseIDs := []string{}
+	// マージ後の拡張空間IDごとのマージ元の拡張空間ID
+	members := map[string]map[string]*object.ExtendedSpatialID{}
+	// マージ後の拡張空間IDの出現順
+	highIDs := []string{}
+
+	for _, extendedSpatialID := range extendedSpatialIds {
+		spatialID, err := object.NewExtendedSpatialID(extendedSpatialID)
+		if err != nil {
+			// 拡張空間ID初期化時にエラーが発生した場合、フォーマットチェックエラーとしてエラーインスタンスを返却
+			return nil, nil, err
+		}
+
+		// 入力精度より細かい精度もしくは等しい精度の場合はマージ対象の空間IDとする。
+		if spatialID.HZoom() >= hZoom && spatialID.VZoom() >= vZoom {
+			highID := spatialID.Higher(spatialID.HZoom()-hZoom, spatialID.VZoom()-vZoom).ID()
+			if _, ok := members[highID]; !ok {
+				members[highID] = map[string]*object.ExtendedSpatialID{}
+				highIDs = append(highIDs, highID)
+			}
+			members[highID][spatialID.ID()] = spatialID
+		} else {
+			coarseIDs = append(coarseIDs, spatialID.ID())
+		}
+	}
+
+	groups := make([]*mergeGroup, 0, len(highIDs))
+	for _, highID := range highIDs {
+		group := &mergeGroup{highID: highID, unitCount: new(big.Int)}
+
+		// 単位拡張空間IDの精度はまとめた拡張空間IDの最大精度とする
+		maxHZoom, maxVZoom := hZoom, vZoom
+		// マージ後の精度での水平方向の原点(まとめた拡張空間IDを含む最小のインデックス)
+		originX, originY := int64(math.MaxInt64), int64(math.MaxInt64)
+		for lowID, spatialID := range members[highID] {
+			group.lowIDs = append(group.lowIDs, lowID)
+			maxHZoom = max(maxHZoom, spatialID.HZoom())
+			maxVZoom = max(maxVZoom, spatialID.VZoom())
+			originX = min(originX, spatialID.X()>>(spatialID.HZoom()-hZoom))
+			originY = min(originY, spatialID.Y()>>(spatialID.HZoom()-hZoom))
+		}
+
+		// 水平方向はマージ後の拡張空間IDの2×2個分の範囲を4分木のルートとする局所的な座標に変換する。
+		// Higher は切り捨て除算のため、負のインデックスの拡張空間IDは隣接するタイルにまとめられる場合がある。
+		localIDs := make([]*object.ExtendedSpatialID, 0, len(group.lowIDs))
+		for _, spatialID := range members[highID] {
+			shift := spatialID.HZoom() - hZoom
+			localID := *spatialID
+			localID.SetZoom(shift+1, spatialID.VZoom())
+			localID.SetX(spatialID.X() - originX<<shift)
+			localID.SetY(spatialID.Y() - originY<<shift)
+			localIDs = append(localIDs, &localID)
+		}
+
+		// 単位拡張空間IDの個数は集合の体積から求める
+		set := &SpatialIDSet{root: buildHorizontalNode(localIDs, 0)}
+		set.forEachCell(0, 0, func(cellHZoom, x, y, cellVZoom, z int64) {
+			shift := 2*(maxHZoom-(cellHZoom+hZoom-1)) + (maxVZoom - cellVZoom)
+			group.unitCount.Add(group.unitCount, new(big.Int).Lsh(big.NewInt(1), uint(shift)))
+		})
+		group.threshold = new(big.Int).Lsh(big.NewInt(1), uint(2*(maxHZoom-hZoom)+(maxVZoom-vZoom)))
+
+		groups = append(groups, group)
+	}
+
+	return coarseIDs, groups, nil
+}
@@ -1,9 +1,11 @@
 package integrate
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
 	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
 )
 
@@ -945,3 +947,291 @@ func TestMergeExtendedSpatialIds11(t *testing.T) {
 
 	t.Log("テスト終了")
 }
+
+// mergeOptionTestIDs マージオプションの試験データ
+//
+// "1/0/0/1/0"の75%、"1/1/0/1/0"の100%、"1/1/1/1/0"の12.5%を満たす拡張空間IDと、マージ対象外の"0/0/0/0/1"
+var mergeOptionTestIDs = []string{
+	"2/0/0/2/0", "2/0/0/2/1", "2/0/1/2/0", "2/0/1/2/1", "2/1/0/2/0", "2/1/0/2/1",
+	"2/2/0/2/0", "2/2/0/2/1", "2/2/1/2/0", "2/2/1/2/1", "2/3/0/2/0", "2/3/0/2/1", "2/3/1/2/0", "2/3/1/2/1",
+	"2/2/2/2/0",
+	"0/0/0/0/1",
+}
+
+// TestMergeExtendedSpatialIdsWithOption01 オプション指定の拡張空間IDの最適化（マージ）関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：ExactMerge
+//   - パターン2：FillRatioMerge(閾値75%)
+//   - パターン3：FillRatioMerge(閾値80%)
+//   - パターン4：DenseOnlyMerge
+//     拡張空間ID：mergeOptionTestIDs, 水平方向精度：1, 垂直方向精度：1
+//
+// + 確認内容
+//   - オプションごとのマージ条件でマージされ、合致しなかった拡張空間IDが返却、または除去されること
+func TestMergeExtendedSpatialIdsWithOption01(t *testing.T) {
+	exactIDs := []string{
+		"2/0/0/2/0", "2/0/0/2/1", "2/0/1/2/0", "2/0/1/2/1", "2/1/0/2/0", "2/1/0/2/1",
+		"1/1/0/1/0", "2/2/2/2/0", "0/0/0/0/1",
+	}
+	testCases := []struct {
+		option    enum.MergeOption
+		fillRatio float64
+		expect    []string
+	}{
+		{enum.ExactMerge, 0, exactIDs},
+		{enum.FillRatioMerge, 75, []string{"1/0/0/1/0", "1/1/0/1/0", "2/2/2/2/0", "0/0/0/0/1"}},
+		{enum.FillRatioMerge, 80, exactIDs},
+		{enum.DenseOnlyMerge, 0, []string{"1/1/0/1/0", "0/0/0/0/1"}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := MergeExtendedSpatialIdsWithOption(mergeOptionTestIDs, 1, 1, testCase.option, testCase.fillRatio)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+
+		//戻り値要素数と期待値の比較
+		if len(resultVal) != len(testCase.expect) {
+			t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", testCase.expect, resultVal)
+			continue
+		}
+
+		//戻り値の空間IDと期待値の比較
+		for _, exp := range testCase.expect {
+			if !contains(resultVal, exp) {
+				t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", testCase.expect, resultVal)
+			}
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestMergeExtendedSpatialIdsWithOption02 オプション指定の拡張空間IDの最適化（マージ）関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：未対応のマージオプション
+//   - パターン2：充填率の閾値が0
+//   - パターン3：充填率の閾値が100超過
+//
+// + 確認内容
+//   - 空配列とエラーインスタンス（InputValueErrorCode）が返却されること
+func TestMergeExtendedSpatialIdsWithOption02(t *testing.T) {
+	testCases := []struct {
+		option    enum.MergeOption
+		fillRatio float64
+		expect    string
+	}{
+		{enum.MergeOption(3), 50, "InputValueError,入力チェックエラー,unsupported merge option: 3"},
+		{enum.FillRatioMerge, 0, "InputValueError,入力チェックエラー,fill ratio is out of range: 0"},
+		{enum.FillRatioMerge, 100.5, "InputValueError,入力チェックエラー,fill ratio is out of range: 100.5"},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := MergeExtendedSpatialIdsWithOption(mergeOptionTestIDs, 1, 1, testCase.option, testCase.fillRatio)
+		if len(resultVal) != 0 {
+			t.Errorf("拡張空間ID - 期待値：[], 取得値：%v", resultVal)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestMergeSpatialIdsWithOption01 オプション指定の空間IDの最適化（マージ）関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     空間ID：精度1の空間ID"1/0/0/0"の8個の子のうち7個と"2/0/3/3", 精度：1, FillRatioMerge(閾値50%)
+//   - パターン2：パターン1と同じ空間ID, 精度：1, DenseOnlyMerge
+//
+// + 確認内容
+//   - パターン1："1/0/0/0"と"2/0/3/3"が返却されること
+//   - パターン2：空配列が返却されること
+func TestMergeSpatialIdsWithOption01(t *testing.T) {
+	//入力値
+	SpatialIDs := []string{
+		"2/0/0/0", "2/0/1/0", "2/0/0/1", "2/0/1/1",
+		"2/1/0/0", "2/1/1/0", "2/1/0/1",
+		"2/0/3/3",
+	}
+
+	resultVal, resultErr := MergeSpatialIdsWithOption(SpatialIDs, 1, enum.FillRatioMerge, 50)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	expectVal := []string{"1/0/0/0", "2/0/3/3"}
+	if len(resultVal) != len(expectVal) || !contains(resultVal, expectVal[0]) || !contains(resultVal, expectVal[1]) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	resultVal, resultErr = MergeSpatialIdsWithOption(SpatialIDs, 1, enum.DenseOnlyMerge, 0)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if len(resultVal) != 0 {
+		t.Errorf("空間ID - 期待値：[], 取得値：%v", resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestMergeExtendedSpatialIds12 拡張空間IDの最適化（マージ）関数 正常系動作確認(精度の差が大きい場合)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     拡張空間ID："14/50/50/14/50"を満たす精度15の8個の拡張空間IDと、それに内包される精度35の拡張空間ID、
+//     "14/60/60/14/60"に含まれる精度35の拡張空間ID
+//     水平方向精度：14, 垂直方向精度：14
+//
+// + 確認内容
+//   - 単位拡張空間IDへの分割を行わずにマージ結果が返却されること
+func TestMergeExtendedSpatialIds12(t *testing.T) {
+	//入力値
+	SpatialIDs := []string{
+		"15/100/100/15/100", "15/100/100/15/101", "15/100/101/15/100", "15/100/101/15/101",
+		"15/101/100/15/100", "15/101/100/15/101", "15/101/101/15/100", "15/101/101/15/101",
+		"35/104857600/104857600/35/104857600",
+		"35/125829120/125829120/35/125829120",
+	}
+	resultVal, resultErr := MergeExtendedSpatialIds(SpatialIDs, 14, 14)
+
+	//期待値
+	expectVal := []string{"14/50/50/14/50", "35/125829120/125829120/35/125829120"}
+
+	//戻り値要素数と期待値の比較
+	if len(resultVal) != len(expectVal) {
+		t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	//戻り値の空間IDと期待値の比較
+	for _, exp := range expectVal {
+		if !contains(resultVal, exp) {
+			t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+		}
+	}
+	if resultErr != nil {
+		t.Errorf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestMergeExtendedSpatialIdsWithMode01 近似方法指定の拡張空間IDの最適化（マージ）関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：OuterCovering
+//   - パターン2：InnerCovering
+//     拡張空間ID：mergeOptionTestIDs, 水平方向精度：1, 垂直方向精度：1
+//
+// + 確認内容
+//   - パターン1：マージ元の拡張空間IDを含むボクセルが全てマージされること
+//   - パターン2：稠密なボクセルのみマージされ、合致しなかった拡張空間IDが除去されること
+//   - 対応するマージオプションの MergeExtendedSpatialIdsWithOption と同じ結果となること
+func TestMergeExtendedSpatialIdsWithMode01(t *testing.T) {
+	testCases := []struct {
+		mode      enum.CoveringMode
+		option    enum.MergeOption
+		fillRatio float64
+		expect    []string
+	}{
+		{enum.OuterCovering, enum.FillRatioMerge, math.SmallestNonzeroFloat64, []string{"1/0/0/1/0", "1/1/0/1/0", "1/1/1/1/0", "0/0/0/0/1"}},
+		{enum.InnerCovering, enum.DenseOnlyMerge, 100, []string{"1/1/0/1/0", "0/0/0/0/1"}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := MergeExtendedSpatialIdsWithMode(mergeOptionTestIDs, 1, 1, testCase.mode)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+
+		//戻り値要素数と期待値の比較
+		if len(resultVal) != len(testCase.expect) {
+			t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", testCase.expect, resultVal)
+			continue
+		}
+
+		//戻り値の空間IDと期待値の比較
+		for _, exp := range testCase.expect {
+			if !contains(resultVal, exp) {
+				t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", testCase.expect, resultVal)
+			}
+		}
+
+		//対応するマージオプションの結果との比較
+		optionVal, _ := MergeExtendedSpatialIdsWithOption(mergeOptionTestIDs, 1, 1, testCase.option, testCase.fillRatio)
+		if len(optionVal) != len(resultVal) {
+			t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", optionVal, resultVal)
+		}
+		for _, exp := range optionVal {
+			if !contains(resultVal, exp) {
+				t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", optionVal, resultVal)
+			}
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestMergeExtendedSpatialIdsWithMode02 近似方法指定の拡張空間IDの最適化（マージ）関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：未対応の近似方法
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestMergeExtendedSpatialIdsWithMode02(t *testing.T) {
+	_, resultErr := MergeExtendedSpatialIdsWithMode(mergeOptionTestIDs, 1, 1, enum.CoveringMode(2))
+
+	expectErr := "InputValueError,入力チェックエラー,unsupported covering mode: 2"
+	if resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestMergeExtendedSpatialIds13 拡張空間IDの最適化（マージ）関数 正常系動作確認(入力の精度が0～35の範囲外の場合)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     拡張空間ID："36/0/0/25/0"、"40/1/0/40/0"
+//     水平方向精度：14, 垂直方向精度：14
+//
+// + 確認内容
+//   - エラーとならず、入力の拡張空間IDがそのまま返却されること
+func TestMergeExtendedSpatialIds13(t *testing.T) {
+	//入力値
+	SpatialIDs := []string{"36/0/0/25/0", "40/1/0/40/0"}
+	resultVal, resultErr := MergeExtendedSpatialIds(SpatialIDs, 14, 14)
+
+	//期待値
+	expectVal := []string{"36/0/0/25/0", "40/1/0/40/0"}
+
+	//戻り値要素数と期待値の比較
+	if len(resultVal) != len(expectVal) {
+		t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	//戻り値の空間IDと期待値の比較
+	for _, exp := range expectVal {
+		if !contains(resultVal, exp) {
+			t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+		}
+	}
+	if resultErr != nil {
+		t.Errorf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	t.Log("テスト終了")
+}
@@ -0,0 +1,222 @@
+package integrate
+
+import (
+	"math"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/consts"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// 面積計算に使用するWGS84楕円体の定数
+const (
+	ellipsoidSemiMajorAxis = 6378137.0         // 長半径(単位:m)
+	ellipsoidFlattening    = 1 / 298.257223563 // 扁平率
+)
+
+// GetVolumeOnExtendedSpatialIds 拡張空間IDの体積取得関数
+//
+// 拡張空間ID配列が表す空間の体積を取得する。
+//
+// 精度の混在や重複により拡張空間IDの空間が重なる場合でも、重なった空間は1回のみ計上する。
+// 各ボクセルの体積は、楕円体面上のタイルの面積(Webメルカトルのタイルの緯度による大きさの違いを考慮)に
+// ボクセルの高さを乗じた値とする。高度による面積の拡大は考慮しない。
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間ID文字列配列
+//
+// 戻り値：
+//
+//	体積(単位:m³)
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"拡張空間ID配列"に入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのx成分、y成分が精度の範囲外の場合。
+func GetVolumeOnExtendedSpatialIds(extendedSpatialIds []string) (float64, error) {
+	set, err := NewSpatialIDSet(extendedSpatialIds)
+	if err != nil {
+		return 0, err
+	}
+
+	volume := 0.0
+	walkHorizontalNode(set.getRoot(), 0, 0, 0, func(zoom, x, y int64, vertical verticalSet) {
+		height := 0.0
+		for key, node := range vertical {
+			walkVerticalNode(node, 0, key, func(zoom, z int64) {
+				height += getVerticalResolution(zoom)
+			})
+		}
+		volume += getTileArea(zoom, x, y) * height
+	})
+
+	return volume, nil
+}
+
+// GetAreaOnExtendedSpatialIds 拡張空間IDの水平投影面積取得関数
+//
+// 拡張空間ID配列が表す空間を水平面に投影した範囲(フットプリント)の面積を取得する。
+//
+// 高さの異なる拡張空間IDや精度の混在した拡張空間IDが同じ範囲に重なる場合でも、重なった範囲は1回のみ計上する。
+// 各タイルの面積は、Webメルカトルのタイルの緯度、経度の範囲に対応する楕円体面上の面積とする。
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間ID文字列配列
+//
+// 戻り値：
+//
+//	面積(単位:m²)
+//
+// 戻り値(エラー)：
+//
+//	GetVolumeOnExtendedSpatialIds と同じ条件でエラーインスタンスが返却される。
+func GetAreaOnExtendedSpatialIds(extendedSpatialIds []string) (float64, error) {
+	set, err := NewSpatialIDSet(extendedSpatialIds)
+	if err != nil {
+		return 0, err
+	}
+
+	// 4分木の葉のタイルは互いに重ならない
+	area := 0.0
+	walkHorizontalNode(set.getRoot(), 0, 0, 0, func(zoom, x, y int64, vertical verticalSet) {
+		area += getTileArea(zoom, x, y)
+	})
+
+	return area, nil
+}
+
+// GetBoundsOnExtendedSpatialIds 拡張空間IDの外接直方体取得関数
+//
+// 拡張空間ID配列が表す空間を包含する、経度、緯度、高さの範囲を取得する。
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間ID文字列配列
+//
+// 戻り値：
+//
+//	最小の経度、緯度、高さを持つ座標(南西下端)
+//	最大の経度、緯度、高さを持つ座標(北東上端)
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"拡張空間ID配列"に入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのx成分、y成分が精度の範囲外の場合。
+//	 入力値不正                ：拡張空間ID配列が空の場合。
+func GetBoundsOnExtendedSpatialIds(extendedSpatialIds []string) (*object.Point, *object.Point, error) {
+	set, err := NewSpatialIDSet(extendedSpatialIds)
+	if err != nil {
+		return nil, nil, err
+	}
+	if set.IsEmpty() {
+		return nil, nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "extendedSpatialIds is empty")
+	}
+
+	minLon, minLat, minAlt := math.Inf(1), math.Inf(1), math.Inf(1)
+	maxLon, maxLat, maxAlt := math.Inf(-1), math.Inf(-1), math.Inf(-1)
+	walkHorizontalNode(set.getRoot(), 0, 0, 0, func(zoom, x, y int64, vertical verticalSet) {
+		west, south, east, north := getTileBounds(zoom, x, y)
+		minLon, minLat = math.Min(minLon, west), math.Min(minLat, south)
+		maxLon, maxLat = math.Max(maxLon, east), math.Max(maxLat, north)
+
+		for key, node := range vertical {
+			walkVerticalNode(node, 0, key, func(zoom, z int64) {
+				resolution := getVerticalResolution(zoom)
+				minAlt = math.Min(minAlt, float64(z)*resolution)
+				maxAlt = math.Max(maxAlt, float64(z+1)*resolution)
+			})
+		}
+	})
+
+	minPoint, err := object.NewPoint(minLon, minLat, minAlt)
+	if err != nil {
+		return nil, nil, err
+	}
+	maxPoint, err := object.NewPoint(maxLon, maxLat, maxAlt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return minPoint, maxPoint, nil
+}
+
+// getVerticalResolution 垂直方向の分解能の取得
+//
+// 引数：
+//
+//	vZoom：垂直方向精度
+//
+// 戻り値：
+//
+//	ボクセルの高さ(単位:m)
+func getVerticalResolution(vZoom int64) float64 {
+	return math.Ldexp(1, consts.ZOriginValue-int(vZoom))
+}
+
+// getTileBounds タイルの経度、緯度の範囲の取得
+//
+// 引数：
+//
+//	zoom：水平方向精度
+//	x, y：タイルのx成分、y成分
+//
+// 戻り値：
+//
+//	西端の経度、南端の緯度、東端の経度、北端の緯度
+func getTileBounds(zoom, x, y int64) (float64, float64, float64, float64) {
+	limit := math.Ldexp(1, int(zoom))
+
+	west := float64(x)*360/limit - 180
+	east := float64(x+1)*360/limit - 180
+	north := common.RadianToDegree(math.Atan(math.Sinh(math.Pi * (1 - 2*float64(y)/limit))))
+	south := common.RadianToDegree(math.Atan(math.Sinh(math.Pi * (1 - 2*float64(y+1)/limit))))
+
+	return west, south, east, north
+}
+
+// getTileArea タイルの楕円体面上の面積の取得
+//
+// 緯度φ1～φ2、経度幅Δλの範囲の楕円体面上の面積 a²Δλ(q(φ2) - q(φ1))/2 を計算する。
+// qは以下の関数である(eは離心率)。
+//
+//	q(φ) = (1 - e²)(sinφ/(1 - e²sin²φ) - ln((1 - e sinφ)/(1 + e sinφ))/(2e))
+//
+// 引数：
+//
+//	zoom：水平方向精度
+//	x, y：タイルのx成分、y成分
+//
+// 戻り値：
+//
+//	面積(単位:m²)
+func getTileArea(zoom, x, y int64) float64 {
+	west, south, east, north := getTileBounds(zoom, x, y)
+
+	return ellipsoidSemiMajorAxis * ellipsoidSemiMajorAxis *
+		common.DegreeToRadian(east-west) *
+		(getAuthalicFactor(common.DegreeToRadian(north)) - getAuthalicFactor(common.DegreeToRadian(south))) / 2
+}
+
+// getAuthalicFactor 楕円体の面積計算用の係数の取得
+//
+// 引数：
+//
+//	lat：緯度(単位:rad)
+//
+// 戻り値：
+//
+//	赤道から緯度latまでの帯の面積を求める係数q(φ)
+func getAuthalicFactor(lat float64) float64 {
+	e2 := ellipsoidFlattening * (2 - ellipsoidFlattening)
+	e := math.Sqrt(e2)
+	sin := math.Sin(lat)
+
+	return (1 - e2) * (sin/(1-e2*sin*sin) - math.Log((1-e*sin)/(1+e*sin))/(2*e))
+}
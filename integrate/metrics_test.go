@@ -0,0 +1,147 @@
+package integrate
+
+import (
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common"
+)
+
+// TestGetVolumeOnExtendedSpatialIds01 拡張空間IDの体積取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：赤道直下の水平方向精度20のタイルで、高さ2mの"20/524288/524287/24/0"、
+//     それに内包される"20/524288/524287/25/1"、高さ1mの"20/524288/524287/25/2"
+//
+// + 確認内容
+//   - 重なった空間を1回のみ計上し、タイルの面積×高さ3mの体積が返却されること
+func TestGetVolumeOnExtendedSpatialIds01(t *testing.T) {
+	ids := []string{"20/524288/524287/24/0", "20/524288/524287/25/1", "20/524288/524287/25/2"}
+
+	resultVal, resultErr := GetVolumeOnExtendedSpatialIds(ids)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	expectVal := 1450.87664484357 * 3
+	if !common.AlmostEqual(resultVal, expectVal, 1e-6) {
+		t.Errorf("体積 - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetVolumeOnExtendedSpatialIds02 拡張空間IDの体積取得関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：拡張空間IDフォーマット不正
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetVolumeOnExtendedSpatialIds02(t *testing.T) {
+	_, resultErr := GetVolumeOnExtendedSpatialIds([]string{"20/524288/524287/24"})
+
+	expectErr := "InputValueError,入力チェックエラー"
+	if resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetAreaOnExtendedSpatialIds01 拡張空間IDの水平投影面積取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：{"0/0/0/0/0"}
+//   - パターン2：{"0/0/0/0/0", "1/0/0/3/5"(水平方向で重なる)}
+//   - パターン3：水平方向精度1の4タイル
+//   - パターン4：赤道直下の水平方向精度20のタイル"20/524288/524287/25/0"
+//   - パターン5：高緯度の水平方向精度20のタイル"20/524288/100000/25/0"
+//
+// + 確認内容
+//   - パターン1～3：北緯、南緯85.0511度の間の楕円体面上の面積(5.0815e14m²)が返却されること
+//   - パターン4：赤道付近のタイルの面積(約1450.9m²)が返却されること
+//   - パターン5：高緯度ほどタイルの面積が小さくなること
+func TestGetAreaOnExtendedSpatialIds01(t *testing.T) {
+	testCases := []struct {
+		ids    []string
+		expect float64
+	}{
+		{[]string{"0/0/0/0/0"}, 5.081471301880993e+14},
+		{[]string{"0/0/0/0/0", "1/0/0/3/5"}, 5.081471301880993e+14},
+		{[]string{"1/0/0/0/0", "1/1/0/0/0", "1/0/1/0/0", "1/1/1/0/0"}, 5.081471301880993e+14},
+		{[]string{"20/524288/524287/25/0"}, 1450.87664484357},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := GetAreaOnExtendedSpatialIds(testCase.ids)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !common.AlmostEqual(resultVal, testCase.expect, testCase.expect*1e-12) {
+			t.Errorf("面積 - 期待値：%v, 取得値：%v", testCase.expect, resultVal)
+		}
+	}
+
+	highLatitude, resultErr := GetAreaOnExtendedSpatialIds([]string{"20/524288/100000/25/0"})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if highLatitude <= 0 || highLatitude >= 1450.87664484357 {
+		t.Errorf("面積 - 期待値：0 < 面積 < 1450.87664484357, 取得値：%v", highLatitude)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetBoundsOnExtendedSpatialIds01 拡張空間IDの外接直方体取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：{"1/0/0/25/-1", "2/3/3/23/0"}
+//
+// + 確認内容
+//   - 全ての拡張空間IDを包含する経度、緯度、高さの範囲が返却されること
+func TestGetBoundsOnExtendedSpatialIds01(t *testing.T) {
+	minPoint, maxPoint, resultErr := GetBoundsOnExtendedSpatialIds([]string{"1/0/0/25/-1", "2/3/3/23/0"})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	expectMin := []float64{-180, -85.0511287798, -1}
+	expectMax := []float64{180, 85.0511287798, 4}
+	resultMin := []float64{minPoint.Lon(), minPoint.Lat(), minPoint.Alt()}
+	resultMax := []float64{maxPoint.Lon(), maxPoint.Lat(), maxPoint.Alt()}
+	for i := range expectMin {
+		if !common.AlmostEqual(resultMin[i], expectMin[i], 1e-9) || !common.AlmostEqual(resultMax[i], expectMax[i], 1e-9) {
+			t.Errorf("範囲 - 期待値：%v - %v, 取得値：%v - %v", expectMin, expectMax, resultMin, resultMax)
+			break
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetBoundsOnExtendedSpatialIds02 拡張空間IDの外接直方体取得関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：空の拡張空間ID配列
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetBoundsOnExtendedSpatialIds02(t *testing.T) {
+	minPoint, maxPoint, resultErr := GetBoundsOnExtendedSpatialIds([]string{})
+	if minPoint != nil || maxPoint != nil {
+		t.Errorf("座標 - 期待値：nil, 取得値：%v, %v", minPoint, maxPoint)
+	}
+
+	expectErr := "InputValueError,入力チェックエラー,extendedSpatialIds is empty"
+	if resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}
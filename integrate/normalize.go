@@ -0,0 +1,58 @@
+package integrate
+
+import (
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/shape"
+)
+
+// NormalizeExtendedSpatialIds 拡張空間IDの正規化関数
+//
+// 入力された拡張空間ID配列の空間を、互いに重ならない最小個数の拡張空間IDの配列に変換する。
+//
+// MergeExtendedSpatialIds が指定された1つの精度へのマージのみを行うのに対し、本関数は以下の処理を行う。
+//
+//	・他の拡張空間IDに包含される拡張空間IDを除去する。
+//	・兄弟の拡張空間ID(水平方向は4つ、垂直方向は2つ)が全て揃っている場合は親の拡張空間IDにマージし、
+//	  マージ後の拡張空間IDについても同様のマージを、指定された最も粗い精度に達するまで繰り返す。
+//	・指定された最も粗い精度より粗い拡張空間IDは、最も粗い精度の拡張空間IDに分割する。
+//
+// 同じ空間を表す拡張空間ID配列からは、入力の順序や分割のされ方によらず同じ結果が返却される。
+// 返却される拡張空間IDは水平方向のタイル順(4分木の深さ優先順)、高さの昇順に並ぶ。
+//
+// 引数：
+//
+//	extendedSpatialIds：正規化対象の拡張空間ID文字列配列
+//	hZoom             ：マージ可能な最も粗い水平方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//	vZoom             ：マージ可能な最も粗い垂直方向精度。拡張空間IDの精度の閾値である 0 ～ 35 の整数値を指定可能。
+//
+// 戻り値：
+//
+//	正規化後の拡張空間IDを格納した配列
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"拡張空間ID配列"に入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのx成分、y成分が精度の範囲外の場合。
+func NormalizeExtendedSpatialIds(extendedSpatialIds []string, hZoom, vZoom int64) ([]string, error) {
+	resultIDs := []string{}
+
+	// 水平、垂直方向精度のどちらかが範囲外の場合、空配列とエラーインスタンスを返却
+	if !shape.CheckZoom(hZoom) || !shape.CheckZoom(vZoom) {
+		return resultIDs, errors.NewSpatialIdError(errors.InputValueErrorCode, "")
+	}
+
+	set, err := NewSpatialIDSet(extendedSpatialIds)
+	if err != nil {
+		return resultIDs, err
+	}
+
+	// 集合の4分木、2分木は兄弟が揃ったノードを親にまとめた正規形で保持されているため、
+	// 最も粗い精度で分割しながら走査すれば正規化後の拡張空間IDとなる
+	set.forEachCell(hZoom, vZoom, func(hZoom, x, y, vZoom, z int64) {
+		resultIDs = append(resultIDs, formatExtendedSpatialId(hZoom, x, y, vZoom, z))
+	})
+
+	return resultIDs, nil
+}
@@ -0,0 +1,120 @@
+package integrate
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestNormalizeExtendedSpatialIds01 拡張空間IDの正規化関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：水平方向精度2の16タイル("2/x/y/0/0")と、それに内包される"3/0/0/1/0"
+//     マージ可能な最も粗い精度：水平方向精度0、垂直方向精度0
+//   - パターン2：パターン1と同じ拡張空間ID
+//     マージ可能な最も粗い精度：水平方向精度1、垂直方向精度0
+//
+// + 確認内容
+//   - 内包される拡張空間IDが除去され、兄弟のマージが指定精度まで繰り返されること
+func TestNormalizeExtendedSpatialIds01(t *testing.T) {
+	ids := []string{"3/0/0/1/0"}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			ids = append(ids, fmt.Sprintf("2/%d/%d/0/0", x, y))
+		}
+	}
+
+	testCases := []struct {
+		hZoom  int64
+		vZoom  int64
+		expect []string
+	}{
+		{0, 0, []string{"0/0/0/0/0"}},
+		{1, 0, []string{"1/0/0/0/0", "1/1/0/0/0", "1/0/1/0/0", "1/1/1/0/0"}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := NormalizeExtendedSpatialIds(ids, testCase.hZoom, testCase.vZoom)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultVal, testCase.expect) {
+			t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", testCase.expect, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestNormalizeExtendedSpatialIds02 拡張空間IDの正規化関数 正常系動作確認(分割と一意性)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：{"0/0/0/0/0"}
+//   - パターン2：{"1/0/0/1/0", "1/1/0/1/0", "1/0/1/1/0", "1/1/1/1/0", "0/0/0/1/1"}
+//   - パターン3：{"1/0/0/0/0", "1/1/0/0/0", "1/0/1/0/0", "1/1/1/0/0"}
+//     マージ可能な最も粗い精度：水平方向精度1、垂直方向精度1
+//
+// + 確認内容
+//   - 指定精度より粗い拡張空間IDが分割され、同じ空間を表す入力から同じ結果が返却されること
+func TestNormalizeExtendedSpatialIds02(t *testing.T) {
+	inputs := [][]string{
+		{"0/0/0/0/0"},
+		{"1/0/0/1/0", "1/1/0/1/0", "1/0/1/1/0", "1/1/1/1/0", "0/0/0/1/1"},
+		{"1/0/0/0/0", "1/1/0/0/0", "1/0/1/0/0", "1/1/1/0/0"},
+	}
+	expectVal := []string{
+		"1/0/0/1/0", "1/0/0/1/1",
+		"1/1/0/1/0", "1/1/0/1/1",
+		"1/0/1/1/0", "1/0/1/1/1",
+		"1/1/1/1/0", "1/1/1/1/1",
+	}
+
+	for _, ids := range inputs {
+		resultVal, resultErr := NormalizeExtendedSpatialIds(ids, 1, 1)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultVal, expectVal) {
+			t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestNormalizeExtendedSpatialIds03 拡張空間IDの正規化関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：水平方向精度閾値超過
+//   - パターン2：垂直方向精度閾値超過
+//   - パターン3：拡張空間IDフォーマット不正
+//
+// + 確認内容
+//   - 空配列とエラーインスタンス（InputValueErrorCode）が返却されること
+func TestNormalizeExtendedSpatialIds03(t *testing.T) {
+	testCases := []struct {
+		ids    []string
+		hZoom  int64
+		vZoom  int64
+		expect string
+	}{
+		{[]string{"1/0/0/1/0"}, 36, 0, "InputValueError,入力チェックエラー"},
+		{[]string{"1/0/0/1/0"}, 0, -1, "InputValueError,入力チェックエラー"},
+		{[]string{"1/0/0/1"}, 0, 0, "InputValueError,入力チェックエラー"},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := NormalizeExtendedSpatialIds(testCase.ids, testCase.hZoom, testCase.vZoom)
+		if len(resultVal) != 0 {
+			t.Errorf("拡張空間ID - 期待値：[], 取得値：%v", resultVal)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
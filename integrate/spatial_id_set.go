@@ -0,0 +1,849 @@
+package integrate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/consts"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+	"github.com/trajectoryjp/spatial_id_go/v4/shape"
+)
+
+// SpatialIDSet 精度の混在した拡張空間IDの集合
+//
+// 拡張空間IDが表す空間(体積)の集合として、和集合、積集合、差集合、対称差を計算する。
+// 精度の異なる拡張空間IDも、内包関係を考慮して比較される。
+//
+// 内部では水平方向を4分木、垂直方向を2分木で保持する。
+// 4分木の葉は垂直方向の2分木(垂直方向精度0の高さIDごとの森)を持ち、
+// 以下の規則で常に正規化された形で保持される。
+//
+//   - 2分木の2つの子が共に全体を満たす場合は親にまとめる。
+//   - 4分木の4つの子が全て葉であり、垂直方向の集合が等しい場合は親にまとめる。
+//
+// そのため、同じ空間を表す集合は入力の精度や順序に関わらず同じ拡張空間IDの列に変換される。
+//
+// ゼロ値は空集合として使用できる。集合演算は引数の集合を変更せず、新しい集合を返却する。
+type SpatialIDSet struct {
+	root *horizontalNode // 水平方向精度0の4分木のルート。nilの場合は空集合。
+}
+
+// horizontalNode 水平方向の4分木のノード
+//
+// childrenがnilの場合は葉であり、verticalの空間を水平方向のタイル全体に持つ。
+// ノードは作成後に変更しないため、複数の木で共有される。
+type horizontalNode struct {
+	vertical verticalSet         // 葉の垂直方向の集合
+	children *[4]*horizontalNode // 子ノード。添字は x成分の下位ビット + 2 × y成分の下位ビット
+}
+
+// verticalSet 垂直方向精度0の高さIDをキーとする2分木の森
+type verticalSet map[int64]*verticalNode
+
+// verticalNode 垂直方向の2分木のノード
+//
+// fullがtrueの場合は高さ方向の区間全体を満たす。
+// ノードは作成後に変更しないため、複数の木で共有される。
+type verticalNode struct {
+	full     bool             // 区間全体を満たすかどうか
+	children [2]*verticalNode // 子ノード。添字は高さ成分の下位ビット
+}
+
+// setOperation 集合演算の種別の型
+//
+// 各点が2つの集合に含まれるかどうかから、結果に含まれるかどうかを返却する。
+type setOperation func(inA, inB bool) bool
+
+// 集合演算の種別
+var (
+	unionOperation               setOperation = func(inA, inB bool) bool { return inA || inB }
+	intersectOperation           setOperation = func(inA, inB bool) bool { return inA && inB }
+	differenceOperation          setOperation = func(inA, inB bool) bool { return inA && !inB }
+	symmetricDifferenceOperation setOperation = func(inA, inB bool) bool { return inA != inB }
+)
+
+// fullVerticalNode 区間全体を満たす2分木のノード
+var fullVerticalNode = &verticalNode{full: true}
+
+// NewSpatialIDSet 拡張空間IDの集合の初期化関数
+//
+// 引数：
+//
+//	extendedSpatialIds：集合に含める拡張空間ID。精度が異なっている入力も許容。
+//
+// 戻り値：
+//
+//	拡張空間IDの集合
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 インデックス範囲外        ：x成分、y成分が 0 ～ 2^水平方向精度-1 の範囲外の場合。
+func NewSpatialIDSet(extendedSpatialIds []string) (*SpatialIDSet, error) {
+	ids := make([]*object.ExtendedSpatialID, 0, len(extendedSpatialIds))
+	for _, extendedSpatialId := range extendedSpatialIds {
+		id, err := newSpatialIDOnSet(extendedSpatialId)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return &SpatialIDSet{root: buildHorizontalNode(ids, 0)}, nil
+}
+
+// Add 拡張空間IDの追加
+//
+// 引数：
+//
+//	extendedSpatialId：追加する拡張空間ID
+//
+// 戻り値(エラー)：
+//
+//	NewSpatialIDSet と同じ条件でエラーインスタンスが返却される。エラーの場合は集合を変更しない。
+func (s *SpatialIDSet) Add(extendedSpatialId string) error {
+	id, err := newSpatialIDOnSet(extendedSpatialId)
+	if err != nil {
+		return err
+	}
+
+	s.root = operateHorizontalNode(s.root, newHorizontalPath(id), unionOperation)
+	return nil
+}
+
+// Union 和集合
+//
+// 引数：
+//
+//	other：もう一方の集合
+//
+// 戻り値：
+//
+//	2つの集合のいずれかに含まれる空間の集合
+func (s *SpatialIDSet) Union(other *SpatialIDSet) *SpatialIDSet {
+	return &SpatialIDSet{root: operateHorizontalNode(s.getRoot(), other.getRoot(), unionOperation)}
+}
+
+// Intersect 積集合
+//
+// 精度が異なる拡張空間IDが重なる場合は、細かい方の拡張空間IDが結果に含まれる。
+//
+// 引数：
+//
+//	other：もう一方の集合
+//
+// 戻り値：
+//
+//	2つの集合の両方に含まれる空間の集合
+func (s *SpatialIDSet) Intersect(other *SpatialIDSet) *SpatialIDSet {
+	return &SpatialIDSet{root: operateHorizontalNode(s.getRoot(), other.getRoot(), intersectOperation)}
+}
+
+// Difference 差集合
+//
+// 引数：
+//
+//	other：差し引く集合
+//
+// 戻り値：
+//
+//	この集合に含まれ、otherに含まれない空間の集合
+func (s *SpatialIDSet) Difference(other *SpatialIDSet) *SpatialIDSet {
+	return &SpatialIDSet{root: operateHorizontalNode(s.getRoot(), other.getRoot(), differenceOperation)}
+}
+
+// SymmetricDifference 対称差
+//
+// 引数：
+//
+//	other：もう一方の集合
+//
+// 戻り値：
+//
+//	2つの集合のいずれか一方のみに含まれる空間の集合
+func (s *SpatialIDSet) SymmetricDifference(other *SpatialIDSet) *SpatialIDSet {
+	return &SpatialIDSet{root: operateHorizontalNode(s.getRoot(), other.getRoot(), symmetricDifferenceOperation)}
+}
+
+// IsEmpty 空集合の判定
+//
+// 戻り値：
+//
+//	空集合の場合true
+func (s *SpatialIDSet) IsEmpty() bool {
+	return s.getRoot() == nil
+}
+
+// Equal 集合の比較
+//
+// 引数：
+//
+//	other：比較対象の集合
+//
+// 戻り値：
+//
+//	2つの集合が同じ空間を表す場合true
+func (s *SpatialIDSet) Equal(other *SpatialIDSet) bool {
+	return s.SymmetricDifference(other).IsEmpty()
+}
+
+// Contains 部分集合の判定
+//
+// 引数：
+//
+//	other：判定対象の集合
+//
+// 戻り値：
+//
+//	otherの空間が全てこの集合に含まれる場合true
+func (s *SpatialIDSet) Contains(other *SpatialIDSet) bool {
+	return other.Difference(s).IsEmpty()
+}
+
+// ExtendedSpatialIds 拡張空間IDの取得
+//
+// 集合を正規化された最小の拡張空間IDの列として返却する。
+// 返却される拡張空間IDは互いに重ならず、水平方向のタイル順(4分木の深さ優先順)、高さの昇順に並ぶ。
+//
+// 戻り値：
+//
+//	拡張空間IDのスライス
+func (s *SpatialIDSet) ExtendedSpatialIds() []string {
+	ids := []string{}
+	s.forEachCell(0, 0, func(hZoom, x, y, vZoom, z int64) {
+		ids = append(ids, formatExtendedSpatialId(hZoom, x, y, vZoom, z))
+	})
+	return ids
+}
+
+// getRoot 4分木のルートの取得
+//
+// 戻り値：
+//
+//	4分木のルート。集合がnilの場合はnil。
+func (s *SpatialIDSet) getRoot() *horizontalNode {
+	if s == nil {
+		return nil
+	}
+	return s.root
+}
+
+// forEachCell 集合の拡張空間IDの走査
+//
+// 正規化された拡張空間IDを順に走査する。
+// 指定された最小精度より粗い拡張空間IDは、最小精度の拡張空間IDに分割して走査する。
+//
+// 引数：
+//
+//	minHZoom：最小の水平方向精度
+//	minVZoom：最小の垂直方向精度
+//	f       ：拡張空間IDの成分を受け取る関数
+func (s *SpatialIDSet) forEachCell(minHZoom, minVZoom int64, f func(hZoom, x, y, vZoom, z int64)) {
+	walkHorizontalNode(s.getRoot(), 0, 0, 0, func(hZoom, x, y int64, vertical verticalSet) {
+		forEachSubdivision(hZoom, x, y, minHZoom, func(hZoom, x, y int64) {
+			for _, key := range getSortedVerticalKeys(vertical) {
+				walkVerticalNode(vertical[key], 0, key, func(vZoom, z int64) {
+					forEachVerticalSubdivision(vZoom, z, minVZoom, func(vZoom, z int64) {
+						f(hZoom, x, y, vZoom, z)
+					})
+				})
+			}
+		})
+	})
+}
+
+// newSpatialIDOnSet 集合に追加する拡張空間IDの読み込み
+//
+// 引数：
+//
+//	extendedSpatialId：拡張空間ID
+//
+// 戻り値：
+//
+//	拡張空間IDオブジェクト
+//
+// 戻り値(エラー)：
+//
+//	NewSpatialIDSet と同じ条件でエラーインスタンスが返却される。
+func newSpatialIDOnSet(extendedSpatialId string) (*object.ExtendedSpatialID, error) {
+	id, err := object.NewExtendedSpatialID(extendedSpatialId)
+	if err != nil {
+		return nil, err
+	}
+	if !shape.CheckZoom(id.HZoom()) || !shape.CheckZoom(id.VZoom()) {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "")
+	}
+
+	maxIndex := int64(1) << id.HZoom()
+	if id.X() < 0 || id.X() >= maxIndex || id.Y() < 0 || id.Y() >= maxIndex {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("index out of range: %v", extendedSpatialId))
+	}
+
+	return id, nil
+}
+
+// newSpatialIDsOnSet 集合に追加する拡張空間IDの一括読み込み
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間ID文字列配列
+//
+// 戻り値：
+//
+//	拡張空間IDオブジェクトのスライス(入力と同じ順)
+//	最も細かい水平方向精度
+//	最も細かい垂直方向精度
+//
+// 戻り値(エラー)：
+//
+//	NewSpatialIDSet と同じ条件でエラーインスタンスが返却される。
+func newSpatialIDsOnSet(extendedSpatialIds []string) ([]*object.ExtendedSpatialID, int64, int64, error) {
+	ids := make([]*object.ExtendedSpatialID, 0, len(extendedSpatialIds))
+	var maxHZoom, maxVZoom int64
+	for _, extendedSpatialId := range extendedSpatialIds {
+		id, err := newSpatialIDOnSet(extendedSpatialId)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		ids = append(ids, id)
+		maxHZoom, maxVZoom = max(maxHZoom, id.HZoom()), max(maxVZoom, id.VZoom())
+	}
+	return ids, maxHZoom, maxVZoom, nil
+}
+
+// newHorizontalPath 1つの拡張空間IDのみを含む4分木の作成
+//
+// 引数：
+//
+//	id：拡張空間ID
+//
+// 戻り値：
+//
+//	4分木のルート
+func newHorizontalPath(id *object.ExtendedSpatialID) *horizontalNode {
+	// 垂直方向の2分木を葉から作成
+	vertical := fullVerticalNode
+	for depth := int64(0); depth < id.VZoom(); depth++ {
+		bit := (id.Z() >> depth) & 1
+		parent := &verticalNode{}
+		parent.children[bit] = vertical
+		vertical = parent
+	}
+
+	// 水平方向の4分木を葉から作成
+	node := &horizontalNode{vertical: verticalSet{id.Z() >> id.VZoom(): vertical}}
+	for depth := int64(0); depth < id.HZoom(); depth++ {
+		index := ((id.X() >> depth) & 1) + 2*((id.Y()>>depth)&1)
+		parent := &horizontalNode{children: &[4]*horizontalNode{}}
+		parent.children[index] = node
+		node = parent
+	}
+
+	return node
+}
+
+// buildHorizontalNode 拡張空間IDからの4分木の一括作成
+//
+// 引数：
+//
+//	ids  ：ノードのタイルに含まれる拡張空間ID
+//	depth：ノードの水平方向精度
+//
+// 戻り値：
+//
+//	正規化された4分木のノード
+func buildHorizontalNode(ids []*object.ExtendedSpatialID, depth int64) *horizontalNode {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// タイル全体を覆う拡張空間IDと、子のタイルに含まれる拡張空間IDに振り分け
+	covering := []*object.ExtendedSpatialID{}
+	parts := [4][]*object.ExtendedSpatialID{}
+	for _, id := range ids {
+		if id.HZoom() == depth {
+			covering = append(covering, id)
+			continue
+		}
+		shift := id.HZoom() - depth - 1
+		index := ((id.X() >> shift) & 1) + 2*((id.Y()>>shift)&1)
+		parts[index] = append(parts[index], id)
+	}
+
+	var leaf *horizontalNode
+	if len(covering) > 0 {
+		leaf = &horizontalNode{vertical: buildVerticalSet(covering)}
+	}
+	if len(covering) == len(ids) {
+		return leaf
+	}
+
+	children := [4]*horizontalNode{}
+	for i, part := range parts {
+		children[i] = operateHorizontalNode(buildHorizontalNode(part, depth+1), leaf, unionOperation)
+	}
+
+	return newHorizontalNode(children)
+}
+
+// buildVerticalSet 拡張空間IDからの2分木の森の一括作成
+//
+// 引数：
+//
+//	ids：拡張空間ID
+//
+// 戻り値：
+//
+//	拡張空間IDの高さ方向の区間を表す2分木の森
+func buildVerticalSet(ids []*object.ExtendedSpatialID) verticalSet {
+	groups := map[int64][]*object.ExtendedSpatialID{}
+	for _, id := range ids {
+		key := id.Z() >> id.VZoom()
+		groups[key] = append(groups[key], id)
+	}
+
+	vertical := verticalSet{}
+	for key, group := range groups {
+		vertical[key] = buildVerticalNode(group, 0)
+	}
+
+	return vertical
+}
+
+// buildVerticalNode 拡張空間IDからの2分木の一括作成
+//
+// 引数：
+//
+//	ids  ：ノードの区間に含まれる拡張空間ID
+//	depth：ノードの垂直方向精度
+//
+// 戻り値：
+//
+//	正規化された2分木のノード
+func buildVerticalNode(ids []*object.ExtendedSpatialID, depth int64) *verticalNode {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	parts := [2][]*object.ExtendedSpatialID{}
+	for _, id := range ids {
+		// 区間全体を覆う場合
+		if id.VZoom() == depth {
+			return fullVerticalNode
+		}
+		bit := (id.Z() >> (id.VZoom() - depth - 1)) & 1
+		parts[bit] = append(parts[bit], id)
+	}
+
+	return newVerticalNode(buildVerticalNode(parts[0], depth+1), buildVerticalNode(parts[1], depth+1))
+}
+
+// operateHorizontalNode 4分木の集合演算
+//
+// 引数：
+//
+//	a, b：演算対象の4分木のノード。nilは空集合を表す。
+//	op  ：集合演算の種別
+//
+// 戻り値：
+//
+//	演算結果の正規化された4分木のノード
+func operateHorizontalNode(a, b *horizontalNode, op setOperation) *horizontalNode {
+	// 一方が空の場合は、もう一方をそのまま、または空とする
+	if b == nil {
+		if op(true, false) {
+			return a
+		}
+		return nil
+	}
+	if a == nil {
+		if op(false, true) {
+			return b
+		}
+		return nil
+	}
+
+	if isHorizontalLeaf(a) && isHorizontalLeaf(b) {
+		vertical := operateVerticalSet(getVerticalSet(a), getVerticalSet(b), op)
+		if len(vertical) == 0 {
+			return nil
+		}
+		return &horizontalNode{vertical: vertical}
+	}
+
+	children := [4]*horizontalNode{}
+	for i := range children {
+		children[i] = operateHorizontalNode(getHorizontalChild(a, i), getHorizontalChild(b, i), op)
+	}
+
+	return newHorizontalNode(children)
+}
+
+// newHorizontalNode 正規化した4分木のノードの作成
+//
+// 子が全て空の場合は空、子が全て葉で垂直方向の集合が等しい場合は1つの葉にまとめる。
+//
+// 引数：
+//
+//	children：子ノード
+//
+// 戻り値：
+//
+//	4分木のノード
+func newHorizontalNode(children [4]*horizontalNode) *horizontalNode {
+	isEmpty := true
+	isUniform := true
+	for _, child := range children {
+		isEmpty = isEmpty && child == nil
+		isUniform = isUniform && child != nil && child.children == nil &&
+			equalVerticalSet(child.vertical, children[0].vertical)
+	}
+
+	if isEmpty {
+		return nil
+	}
+	if isUniform {
+		return children[0]
+	}
+	return &horizontalNode{children: &children}
+}
+
+// isHorizontalLeaf 4分木の葉の判定
+//
+// 引数：
+//
+//	node：4分木のノード
+//
+// 戻り値：
+//
+//	空、または葉の場合true
+func isHorizontalLeaf(node *horizontalNode) bool {
+	return node == nil || node.children == nil
+}
+
+// getVerticalSet 4分木の葉の垂直方向の集合の取得
+//
+// 引数：
+//
+//	node：4分木の葉
+//
+// 戻り値：
+//
+//	垂直方向の集合。空の場合はnil。
+func getVerticalSet(node *horizontalNode) verticalSet {
+	if node == nil {
+		return nil
+	}
+	return node.vertical
+}
+
+// getHorizontalChild 4分木の子の取得
+//
+// 葉の子は、葉自身と同じ垂直方向の集合を持つ葉とする。
+//
+// 引数：
+//
+//	node ：4分木のノード
+//	index：子の添字
+//
+// 戻り値：
+//
+//	子ノード
+func getHorizontalChild(node *horizontalNode, index int) *horizontalNode {
+	if isHorizontalLeaf(node) {
+		return node
+	}
+	return node.children[index]
+}
+
+// operateVerticalSet 2分木の森の集合演算
+//
+// 引数：
+//
+//	a, b：演算対象の2分木の森
+//	op  ：集合演算の種別
+//
+// 戻り値：
+//
+//	演算結果の2分木の森。空のキーは含まない。
+func operateVerticalSet(a, b verticalSet, op setOperation) verticalSet {
+	result := verticalSet{}
+
+	for key, nodeA := range a {
+		if node := operateVerticalNode(nodeA, b[key], op); node != nil {
+			result[key] = node
+		}
+	}
+	for key, nodeB := range b {
+		if _, ok := a[key]; ok {
+			continue
+		}
+		if node := operateVerticalNode(nil, nodeB, op); node != nil {
+			result[key] = node
+		}
+	}
+
+	return result
+}
+
+// operateVerticalNode 2分木の集合演算
+//
+// 引数：
+//
+//	a, b：演算対象の2分木のノード。nilは空集合を表す。
+//	op  ：集合演算の種別
+//
+// 戻り値：
+//
+//	演算結果の正規化された2分木のノード
+func operateVerticalNode(a, b *verticalNode, op setOperation) *verticalNode {
+	// 一方が空の場合は、もう一方をそのまま、または空とする
+	if b == nil {
+		if op(true, false) {
+			return a
+		}
+		return nil
+	}
+	if a == nil {
+		if op(false, true) {
+			return b
+		}
+		return nil
+	}
+
+	if isVerticalLeaf(a) && isVerticalLeaf(b) {
+		if op(a != nil, b != nil) {
+			return fullVerticalNode
+		}
+		return nil
+	}
+
+	return newVerticalNode(
+		operateVerticalNode(getVerticalChild(a, 0), getVerticalChild(b, 0), op),
+		operateVerticalNode(getVerticalChild(a, 1), getVerticalChild(b, 1), op),
+	)
+}
+
+// newVerticalNode 正規化した2分木のノードの作成
+//
+// 引数：
+//
+//	lower：下側の子ノード
+//	upper：上側の子ノード
+//
+// 戻り値：
+//
+//	2分木のノード。子が共に空の場合は空、共に全体を満たす場合は全体を満たすノード。
+func newVerticalNode(lower, upper *verticalNode) *verticalNode {
+	if lower == nil && upper == nil {
+		return nil
+	}
+	if lower != nil && lower.full && upper != nil && upper.full {
+		return fullVerticalNode
+	}
+	return &verticalNode{children: [2]*verticalNode{lower, upper}}
+}
+
+// isVerticalLeaf 2分木の葉の判定
+//
+// 引数：
+//
+//	node：2分木のノード
+//
+// 戻り値：
+//
+//	空、または全体を満たす場合true
+func isVerticalLeaf(node *verticalNode) bool {
+	return node == nil || node.full
+}
+
+// getVerticalChild 2分木の子の取得
+//
+// 引数：
+//
+//	node ：2分木のノード
+//	index：子の添字
+//
+// 戻り値：
+//
+//	子ノード。葉の子は葉自身とする。
+func getVerticalChild(node *verticalNode, index int) *verticalNode {
+	if isVerticalLeaf(node) {
+		return node
+	}
+	return node.children[index]
+}
+
+// equalVerticalSet 2分木の森の比較
+//
+// 引数：
+//
+//	a, b：比較対象の2分木の森
+//
+// 戻り値：
+//
+//	同じ区間を表す場合true
+func equalVerticalSet(a, b verticalSet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, nodeA := range a {
+		nodeB, ok := b[key]
+		if !ok || !equalVerticalNode(nodeA, nodeB) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalVerticalNode 2分木の比較
+//
+// 正規化された2分木は、同じ区間を表す場合に同じ構造となる。
+//
+// 引数：
+//
+//	a, b：比較対象の2分木のノード
+//
+// 戻り値：
+//
+//	同じ区間を表す場合true
+func equalVerticalNode(a, b *verticalNode) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil || a.full != b.full {
+		return false
+	}
+	return equalVerticalNode(a.children[0], b.children[0]) && equalVerticalNode(a.children[1], b.children[1])
+}
+
+// walkHorizontalNode 4分木の葉の走査
+//
+// 引数：
+//
+//	node：4分木のノード
+//	zoom：ノードの水平方向精度
+//	x, y：ノードのx成分、y成分
+//	f   ：葉のタイルと垂直方向の集合を受け取る関数
+func walkHorizontalNode(node *horizontalNode, zoom, x, y int64, f func(zoom, x, y int64, vertical verticalSet)) {
+	if node == nil {
+		return
+	}
+	if node.children == nil {
+		f(zoom, x, y, node.vertical)
+		return
+	}
+	for index, child := range node.children {
+		walkHorizontalNode(child, zoom+1, 2*x+int64(index%2), 2*y+int64(index/2), f)
+	}
+}
+
+// walkVerticalNode 2分木の全体を満たすノードの走査
+//
+// 引数：
+//
+//	node：2分木のノード
+//	zoom：ノードの垂直方向精度
+//	z   ：ノードの高さ成分
+//	f   ：全体を満たすノードの精度と高さ成分を受け取る関数
+func walkVerticalNode(node *verticalNode, zoom, z int64, f func(zoom, z int64)) {
+	if node == nil {
+		return
+	}
+	if node.full {
+		f(zoom, z)
+		return
+	}
+	for index, child := range node.children {
+		walkVerticalNode(child, zoom+1, 2*z+int64(index), f)
+	}
+}
+
+// getSortedVerticalKeys 2分木の森のキーの昇順での取得
+//
+// 引数：
+//
+//	vertical：2分木の森
+//
+// 戻り値：
+//
+//	昇順のキー
+func getSortedVerticalKeys(vertical verticalSet) []int64 {
+	keys := make([]int64, 0, len(vertical))
+	for key := range vertical {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// forEachSubdivision タイルの分割
+//
+// 指定された最小精度より粗いタイルを、最小精度のタイルに分割して走査する。
+//
+// 引数：
+//
+//	zoom    ：タイルの水平方向精度
+//	x, y    ：タイルのx成分、y成分
+//	minZoom ：最小の水平方向精度
+//	f       ：分割後のタイルを受け取る関数
+func forEachSubdivision(zoom, x, y, minZoom int64, f func(zoom, x, y int64)) {
+	if zoom >= minZoom {
+		f(zoom, x, y)
+		return
+	}
+	scale := int64(1) << (minZoom - zoom)
+	for j := y * scale; j < (y+1)*scale; j++ {
+		for i := x * scale; i < (x+1)*scale; i++ {
+			f(minZoom, i, j)
+		}
+	}
+}
+
+// forEachVerticalSubdivision 高さ方向の区間の分割
+//
+// 指定された最小精度より粗い区間を、最小精度の区間に分割して走査する。
+//
+// 引数：
+//
+//	zoom   ：区間の垂直方向精度
+//	z      ：区間の高さ成分
+//	minZoom：最小の垂直方向精度
+//	f      ：分割後の区間を受け取る関数
+func forEachVerticalSubdivision(zoom, z, minZoom int64, f func(zoom, z int64)) {
+	if zoom >= minZoom {
+		f(zoom, z)
+		return
+	}
+	scale := int64(1) << (minZoom - zoom)
+	for k := z * scale; k < (z+1)*scale; k++ {
+		f(minZoom, k)
+	}
+}
+
+// formatExtendedSpatialId 拡張空間IDの文字列化
+//
+// 引数：
+//
+//	hZoom：水平方向精度
+//	x, y ：x成分、y成分
+//	vZoom：垂直方向精度
+//	z    ：高さ成分
+//
+// 戻り値：
+//
+//	拡張空間ID
+func formatExtendedSpatialId(hZoom, x, y, vZoom, z int64) string {
+	return strings.Join([]string{
+		strconv.FormatInt(hZoom, 10),
+		strconv.FormatInt(x, 10),
+		strconv.FormatInt(y, 10),
+		strconv.FormatInt(vZoom, 10),
+		strconv.FormatInt(z, 10),
+	}, consts.SpatialIDDelimiter)
+}
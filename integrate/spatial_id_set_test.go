@@ -0,0 +1,166 @@
+package integrate
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// TestNewSpatialIDSet01 拡張空間IDの集合の初期化関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     拡張空間ID：{"20/10/10/20/10", "21/20/20/21/20"(1つ目に内包), "20/11/10/20/10",
+//     "20/10/11/20/10", "20/11/11/20/10"}
+//
+// + 確認内容
+//   - 内包される拡張空間IDが除去され、4つの兄弟が親にまとめられること
+func TestNewSpatialIDSet01(t *testing.T) {
+	ids := []string{"20/10/10/20/10", "21/20/20/21/20", "20/11/10/20/10", "20/10/11/20/10", "20/11/11/20/10"}
+
+	resultSet, resultErr := NewSpatialIDSet(ids)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	expectVal := []string{"19/5/5/20/10"}
+	if resultVal := resultSet.ExtendedSpatialIds(); !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestNewSpatialIDSet02 拡張空間IDの集合の初期化関数 正常系動作確認(負の高さ)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     拡張空間ID：{"3/1/1/3/-1", "3/1/1/3/-2", "3/1/1/4/-1"}
+//
+// + 確認内容
+//   - 負の高さ成分の兄弟が親(2/-1)にまとめられ、内包される拡張空間IDが除去されること
+func TestNewSpatialIDSet02(t *testing.T) {
+	resultSet, resultErr := NewSpatialIDSet([]string{"3/1/1/3/-1", "3/1/1/3/-2", "3/1/1/4/-1"})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	expectVal := []string{"3/1/1/2/-1"}
+	if resultVal := resultSet.ExtendedSpatialIds(); !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestNewSpatialIDSet03 拡張空間IDの集合の初期化関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：フォーマット不正
+//   - パターン2：精度閾値超過
+//   - パターン3：インデックス範囲外
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestNewSpatialIDSet03(t *testing.T) {
+	testCases := []struct {
+		id     string
+		expect string
+	}{
+		{"20/10/10/20", "InputValueError,入力チェックエラー"},
+		{"36/10/10/20/0", "InputValueError,入力チェックエラー"},
+		{"2/4/0/20/0", "InputValueError,入力チェックエラー,index out of range: 2/4/0/20/0"},
+	}
+
+	for _, testCase := range testCases {
+		resultSet, resultErr := NewSpatialIDSet([]string{testCase.id})
+		if resultSet != nil {
+			t.Errorf("集合 - 期待値：nil, 取得値：%v", resultSet)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestSpatialIDSetOperation01 集合演算 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     集合A：{"1/0/0/1/0"}, 集合B：{"2/1/1/2/1", "2/2/2/2/0"}
+//
+// + 確認内容
+//   - 積集合が細かい方の拡張空間IDとなること
+//   - 和集合、差集合、対称差が体積として計算されること
+//   - 和集合から差集合を除くと積集合に等しいこと
+func TestSpatialIDSetOperation01(t *testing.T) {
+	a, _ := NewSpatialIDSet([]string{"1/0/0/1/0"})
+	b, _ := NewSpatialIDSet([]string{"2/1/1/2/1", "2/2/2/2/0"})
+
+	testCases := []struct {
+		name   string
+		result *SpatialIDSet
+		expect []string
+	}{
+		{"積集合", a.Intersect(b), []string{"2/1/1/2/1"}},
+		{"和集合", a.Union(b), []string{"1/0/0/1/0", "2/2/2/2/0"}},
+		{"差集合", a.Difference(b), []string{
+			"2/0/0/1/0", "2/1/0/1/0", "2/0/1/1/0", "2/1/1/2/0",
+		}},
+		{"対称差", a.SymmetricDifference(b), []string{
+			"2/0/0/1/0", "2/1/0/1/0", "2/0/1/1/0", "2/1/1/2/0", "2/2/2/2/0",
+		}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal := testCase.result.ExtendedSpatialIds()
+		sort.Strings(resultVal)
+		sort.Strings(testCase.expect)
+		if !reflect.DeepEqual(resultVal, testCase.expect) {
+			t.Errorf("%s - 期待値：%v, 取得値：%v", testCase.name, testCase.expect, resultVal)
+		}
+	}
+
+	if !a.Union(b).Difference(a.SymmetricDifference(b)).Equal(a.Intersect(b)) {
+		t.Errorf("和集合 - 対称差 ≠ 積集合")
+	}
+	if !a.Contains(a.Intersect(b)) || a.Contains(b) {
+		t.Errorf("部分集合の判定が不正")
+	}
+	if !a.Difference(a).IsEmpty() || !(&SpatialIDSet{}).IsEmpty() {
+		t.Errorf("空集合の判定が不正")
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestSpatialIDSetOperation02 集合演算 正常系動作確認(正規形)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     集合A：{"10/0/0/10/0"}
+//     集合B："10/0/0/10/0" を精度11に分割した8つの拡張空間ID
+//
+// + 確認内容
+//   - 同じ空間を表す集合が同じ拡張空間IDに変換されること
+func TestSpatialIDSetOperation02(t *testing.T) {
+	a, _ := NewSpatialIDSet([]string{"10/0/0/10/0"})
+	children, _ := ChangeExtendedSpatialIdsZoom([]string{"10/0/0/10/0"}, 11, 11)
+	b, _ := NewSpatialIDSet(children)
+
+	if !reflect.DeepEqual(a.ExtendedSpatialIds(), b.ExtendedSpatialIds()) {
+		t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", a.ExtendedSpatialIds(), b.ExtendedSpatialIds())
+	}
+	if !a.Equal(b) {
+		t.Errorf("集合の比較 - 期待値：true, 取得値：false")
+	}
+
+	t.Log("テスト終了")
+}
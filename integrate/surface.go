@@ -0,0 +1,239 @@
+package integrate
+
+import (
+	"fmt"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+)
+
+// faceDirections 面で接する拡張空間IDへの移動方向(経度方向、緯度方向、高さ方向)
+var faceDirections = [6][3]int64{{-1, 0, 0}, {1, 0, 0}, {0, -1, 0}, {0, 1, 0}, {0, 0, -1}, {0, 0, 1}}
+
+// 囲まれた空間の充填で判定する直方体の体積(最も細かい精度の拡張空間IDの個数)の上限
+const maxEnclosingVolume = 1 << 24
+
+// GetSurfaceOnExtendedSpatialIds 拡張空間IDの表面取得関数
+//
+// 拡張空間ID配列のうち、いずれかの面の外側が拡張空間ID配列の空間に含まれない拡張空間IDを取得する。
+// 可視化や衝突判定の事前確認で、立体の表面のみを使用する場合に使用する。
+//
+// 面の外側は入力の最も細かい精度の厚さで判定するため、精度の混在した拡張空間IDも判定できる。
+// 精度の粗い拡張空間IDの面が、精度の細かい複数の拡張空間IDで覆われている場合は外側が含まれるとみなす。
+//
+// 経度方向は東経180度と西経180度の境界を越えて判定する。
+// 緯度方向の範囲外(極を越える範囲)は拡張空間ID配列の空間に含まれないとみなす。
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間ID文字列配列
+//
+// 戻り値：
+//
+//	表面の拡張空間IDのスライス。入力に現れる順で、重複を除いて返却される。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"拡張空間ID配列"に入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのx成分、y成分が精度の範囲外の場合。
+func GetSurfaceOnExtendedSpatialIds(extendedSpatialIds []string) ([]string, error) {
+	ids, maxHZoom, maxVZoom, err := newSpatialIDsOnSet(extendedSpatialIds)
+	if err != nil {
+		return nil, err
+	}
+	root := buildHorizontalNode(ids, 0)
+
+	surface := []string{}
+	added := map[string]struct{}{}
+	for index, id := range ids {
+		if _, ok := added[extendedSpatialIds[index]]; ok {
+			continue
+		}
+		added[extendedSpatialIds[index]] = struct{}{}
+
+		cell := coveringCell{id.HZoom(), id.X(), id.Y(), id.VZoom(), id.Z()}
+		for _, direction := range faceDirections {
+			neighbor, ok := cell.neighbor(direction)
+			if !ok || !isFaceFilled(root, neighbor, direction, maxHZoom, maxVZoom) {
+				surface = append(surface, extendedSpatialIds[index])
+				break
+			}
+		}
+	}
+	return surface, nil
+}
+
+// FillEnclosedExtendedSpatialIds 拡張空間IDに囲まれた空間の充填関数
+//
+// 拡張空間ID配列に囲まれ、外部と面で連結していない空間を充填した立体を取得する。
+// 表面のみの拡張空間ID配列(殻)から、内部を含む立体を作成する場合に使用する。
+//
+// 入力の最も細かい精度の拡張空間IDを単位として、入力の範囲を1個分広げた直方体の中で、
+// 直方体の外周から面で連結して到達できない空間を囲まれた空間とする。
+// 計算量とメモリ使用量は、最も細かい精度での直方体の体積に比例するため、体積の上限を 16777216 個とする。
+// 経度方向の周回は考慮しない。
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間ID文字列配列
+//
+// 戻り値：
+//
+//	入力の拡張空間ID(入力に現れる順で重複を除いたもの)に、囲まれた空間の拡張空間IDを追加したスライス。
+//	囲まれた空間の拡張空間IDは、正規化された最小の拡張空間IDの列として SpatialIDSet.ExtendedSpatialIds と同じ順で追加される。
+//
+// 戻り値(エラー)：
+//
+//	GetSurfaceOnExtendedSpatialIds と同じ条件に加え、以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 入力値不正                ：直方体の体積が最も細かい精度の拡張空間IDで 16777216 個を超える場合。
+func FillEnclosedExtendedSpatialIds(extendedSpatialIds []string) ([]string, error) {
+	ids, maxHZoom, maxVZoom, err := newSpatialIDsOnSet(extendedSpatialIds)
+	if err != nil {
+		return nil, err
+	}
+
+	filled := []string{}
+	added := map[string]struct{}{}
+	for _, extendedSpatialId := range extendedSpatialIds {
+		if _, ok := added[extendedSpatialId]; !ok {
+			added[extendedSpatialId] = struct{}{}
+			filled = append(filled, extendedSpatialId)
+		}
+	}
+	if len(ids) == 0 {
+		return filled, nil
+	}
+
+	// 入力の範囲を1個分広げた直方体
+	var bounds cellBox
+	for i, id := range ids {
+		box := coveringCell{id.HZoom(), id.X(), id.Y(), id.VZoom(), id.Z()}.box(maxHZoom, maxVZoom)
+		if i == 0 {
+			bounds = box
+			continue
+		}
+		bounds = cellBox{min(bounds.x0, box.x0), max(bounds.x1, box.x1), min(bounds.y0, box.y0), max(bounds.y1, box.y1), min(bounds.z0, box.z0), max(bounds.z1, box.z1)}
+	}
+	bounds = cellBox{bounds.x0 - 1, bounds.x1 + 1, bounds.y0 - 1, bounds.y1 + 1, bounds.z0 - 1, bounds.z1 + 1}
+	sizeX, sizeY, sizeZ := bounds.x1-bounds.x0, bounds.y1-bounds.y0, bounds.z1-bounds.z0
+	// 桁あふれしないよう、浮動小数点数で上限と比較する
+	if float64(sizeX)*float64(sizeY)*float64(sizeZ) > maxEnclosingVolume {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("enclosing volume exceeds %d cells: %vx%vx%v", maxEnclosingVolume, sizeX, sizeY, sizeZ))
+	}
+	offset := func(x, y, z int64) int64 {
+		return ((x-bounds.x0)*sizeY+(y-bounds.y0))*sizeZ + (z - bounds.z0)
+	}
+
+	// 入力の空間に含まれる範囲、外周から到達できる範囲
+	volume := sizeX * sizeY * sizeZ
+	isInside, isReached := make([]bool, volume), make([]bool, volume)
+	set := &SpatialIDSet{root: buildHorizontalNode(ids, 0)}
+	set.forEachCell(maxHZoom, maxVZoom, func(_, x, y, _, z int64) {
+		isInside[offset(x, y, z)] = true
+	})
+
+	queue := [][3]int64{{bounds.x0, bounds.y0, bounds.z0}}
+	isReached[0] = true
+	for len(queue) > 0 {
+		current := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		for _, direction := range faceDirections {
+			x, y, z := current[0]+direction[0], current[1]+direction[1], current[2]+direction[2]
+			if x < bounds.x0 || x >= bounds.x1 || y < bounds.y0 || y >= bounds.y1 || z < bounds.z0 || z >= bounds.z1 {
+				continue
+			}
+			if i := offset(x, y, z); !isInside[i] && !isReached[i] {
+				isReached[i] = true
+				queue = append(queue, [3]int64{x, y, z})
+			}
+		}
+	}
+
+	enclosed := []string{}
+	for x := bounds.x0; x < bounds.x1; x++ {
+		for y := bounds.y0; y < bounds.y1; y++ {
+			for z := bounds.z0; z < bounds.z1; z++ {
+				if i := offset(x, y, z); !isInside[i] && !isReached[i] {
+					enclosed = append(enclosed, formatExtendedSpatialId(maxHZoom, x, y, maxVZoom, z))
+				}
+			}
+		}
+	}
+	if len(enclosed) == 0 {
+		return filled, nil
+	}
+
+	enclosedSet, err := NewSpatialIDSet(enclosed)
+	if err != nil {
+		return nil, err
+	}
+	return append(filled, enclosedSet.ExtendedSpatialIds()...), nil
+}
+
+// isFaceFilled 面で接する拡張空間IDの面に接する部分が満たされているかの判定
+//
+// 拡張空間IDが満たされていない場合、面に接する側の子に分割して判定する。
+//
+// 引数：
+//
+//	root     ：集合の4分木のルート
+//	cell     ：面で接する拡張空間ID
+//	direction：元の拡張空間IDから cell への移動方向
+//	maxHZoom ：分割する最大の水平方向精度
+//	maxVZoom ：分割する最大の垂直方向精度
+//
+// 戻り値：
+//
+//	元の拡張空間IDの面に接する部分が全て集合に含まれる場合true
+func isFaceFilled(root *horizontalNode, cell coveringCell, direction [3]int64, maxHZoom, maxVZoom int64) bool {
+	if isCellFilled(root, cell) {
+		return true
+	}
+	if getCellCoverage(root, cell) == 0 {
+		return false
+	}
+
+	children := []coveringCell{}
+	if direction[2] == 0 {
+		if cell.hZoom >= maxHZoom {
+			return false
+		}
+		for _, child := range cell.horizontalChildren() {
+			// 移動方向と逆側の子が元の拡張空間IDの面に接する
+			if direction[0] != 0 && child.x%2 == (1-direction[0])/2 || direction[1] != 0 && child.y%2 == (1-direction[1])/2 {
+				children = append(children, child)
+			}
+		}
+	} else {
+		if cell.vZoom >= maxVZoom {
+			return false
+		}
+		children = append(children, cell.verticalChildren()[(1-direction[2])/2])
+	}
+
+	for _, child := range children {
+		if !isFaceFilled(root, child, direction, maxHZoom, maxVZoom) {
+			return false
+		}
+	}
+	return true
+}
+
+// neighbor 面で接する拡張空間IDの取得
+//
+// 経度方向は範囲を超えた場合に周回させる。
+//
+// 引数：
+//
+//	direction：移動方向
+//
+// 戻り値：
+//
+//	移動後の拡張空間ID
+//	緯度方向の範囲外となる場合false
+func (c coveringCell) neighbor(direction [3]int64) (coveringCell, bool) {
+	limit := int64(1) << c.hZoom
+	shifted := coveringCell{c.hZoom, ((c.x+direction[0])%limit + limit) % limit, c.y + direction[1], c.vZoom, c.z + direction[2]}
+	return shifted, shifted.y >= 0 && shifted.y < limit
+}
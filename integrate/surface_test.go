@@ -0,0 +1,168 @@
+package integrate
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// getCubeIds 試験用の立方体の拡張空間IDの取得
+//
+// 引数：
+//
+//	center：中心の拡張空間ID("10/x/y/10/z")のx成分、y成分、高さ成分
+//	hollow：中心を除く場合true
+//
+// 戻り値：
+//
+//	中心の周囲3×3×3の精度10の拡張空間ID
+func getCubeIds(center [3]int64, hollow bool) []string {
+	ids := []string{}
+	for x := center[0] - 1; x <= center[0]+1; x++ {
+		for y := center[1] - 1; y <= center[1]+1; y++ {
+			for z := center[2] - 1; z <= center[2]+1; z++ {
+				if hollow && x == center[0] && y == center[1] && z == center[2] {
+					continue
+				}
+				ids = append(ids, fmt.Sprintf("10/%v/%v/10/%v", x, y, z))
+			}
+		}
+	}
+	return ids
+}
+
+// removeId 試験用の拡張空間IDの除去
+//
+// 引数：
+//
+//	ids：拡張空間IDのスライス
+//	id ：除去する拡張空間ID
+//
+// 戻り値：
+//
+//	id を除いた拡張空間IDのスライス
+func removeId(ids []string, id string) []string {
+	removed := []string{}
+	for _, other := range ids {
+		if other != id {
+			removed = append(removed, other)
+		}
+	}
+	return removed
+}
+
+// TestGetSurfaceOnExtendedSpatialIds01 拡張空間IDの表面取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1："10/10/10/10/10"を中心とする3×3×3の立方体
+//   - パターン2：パターン1の"10/11/10/10/10"を精度11の4個に分割し、中心に接しない"11/23/20/10/10"を除いたもの
+//   - パターン3：パターン1の"10/11/10/10/10"を精度11の4個に分割し、中心に接する"11/22/20/10/10"を除いたもの
+//   - パターン4：経度方向の境界を挟む"1/0/0/0/0"と"1/1/0/0/0"
+//
+// + 確認内容
+//   - パターン1：中心以外の26個が入力順に返却されること
+//   - パターン2：中心は表面とならないこと
+//   - パターン3：中心が表面となること
+//   - パターン4：北端と上下端が露出するため、両方が返却されること
+func TestGetSurfaceOnExtendedSpatialIds01(t *testing.T) {
+	cube := getCubeIds([3]int64{10, 10, 10}, false)
+	split := append(removeId(cube, "10/11/10/10/10"), "11/22/20/10/10", "11/22/21/10/10", "11/23/21/10/10", "11/23/20/10/10")
+
+	testCases := []struct {
+		ids          []string
+		expectVal    []string
+		expectCenter bool
+	}{
+		{cube, removeId(cube, "10/10/10/10/10"), false},
+		{removeId(split, "11/23/20/10/10"), nil, false},
+		{removeId(split, "11/22/20/10/10"), nil, true},
+		{[]string{"1/0/0/0/0", "1/1/0/0/0", "1/0/0/0/0"}, []string{"1/0/0/0/0", "1/1/0/0/0"}, false},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := GetSurfaceOnExtendedSpatialIds(testCase.ids)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if testCase.expectVal != nil && !reflect.DeepEqual(resultVal, testCase.expectVal) {
+			t.Errorf("空間ID - 期待値：%v, 取得値：%v", testCase.expectVal, resultVal)
+		}
+		if isCenter := contains(resultVal, "10/10/10/10/10"); isCenter != testCase.expectCenter {
+			t.Errorf("中心 - 期待値：%v, 取得値：%v", testCase.expectCenter, isCenter)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestFillEnclosedExtendedSpatialIds01 拡張空間IDに囲まれた空間の充填関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1："10/10/10/10/10"を中心とする3×3×3の殻
+//   - パターン2：パターン1の"10/11/10/10/10"を精度11の4個に分割したもの
+//   - パターン3：パターン1の上面の中央"10/10/10/10/11"を除いたもの
+//   - パターン4：空の配列
+//
+// + 確認内容
+//   - パターン1、2：入力に中心の"10/10/10/10/10"が追加されること
+//   - パターン3、4：入力と同じ拡張空間IDが返却されること
+func TestFillEnclosedExtendedSpatialIds01(t *testing.T) {
+	shell := getCubeIds([3]int64{10, 10, 10}, true)
+	split := append(removeId(shell, "10/11/10/10/10"), "11/22/20/10/10", "11/22/21/10/10", "11/23/21/10/10", "11/23/20/10/10")
+	opened := removeId(shell, "10/10/10/10/11")
+
+	testCases := []struct {
+		ids       []string
+		expectVal []string
+	}{
+		{shell, append(append([]string{}, shell...), "10/10/10/10/10")},
+		{split, append(append([]string{}, split...), "10/10/10/10/10")},
+		{opened, opened},
+		{[]string{}, []string{}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := FillEnclosedExtendedSpatialIds(testCase.ids)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultVal, testCase.expectVal) {
+			t.Errorf("空間ID - 期待値：%v, 取得値：%v", testCase.expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestFillEnclosedExtendedSpatialIds02 拡張空間IDの表面取得関数、囲まれた空間の充填関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：拡張空間ID範囲外の"2/0/4/10/10"
+//   - パターン2：精度0と精度25の拡張空間IDの混在による体積の上限超過
+//
+// + 確認内容
+//   - パターン1：両関数でエラーインスタンス（InputValueErrorCode）が返却されること
+//   - パターン2：充填関数でエラーインスタンス（InputValueErrorCode）が返却されること
+func TestFillEnclosedExtendedSpatialIds02(t *testing.T) {
+	expectErr := "InputValueError,入力チェックエラー,index out of range: 2/0/4/10/10"
+
+	_, resultErr := GetSurfaceOnExtendedSpatialIds([]string{"10/10/10/10/10", "2/0/4/10/10"})
+	if resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+	_, resultErr = FillEnclosedExtendedSpatialIds([]string{"10/10/10/10/10", "2/0/4/10/10"})
+	if resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	expectErr = "InputValueError,入力チェックエラー,enclosing volume exceeds 16777216 cells: 33554434x33554434x33554434"
+	_, resultErr = FillEnclosedExtendedSpatialIds([]string{"0/0/0/0/0", "25/0/0/25/0"})
+	if resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}
@@ -0,0 +1,159 @@
+package operated
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+)
+
+// BufferSpatialIds 拡張空間IDの集合の距離指定の拡張関数
+//
+// 拡張空間IDの集合からの水平方向の距離が hDistance 未満、かつ垂直方向の距離が vDistance 未満の範囲と重なる拡張空間IDを取得する。
+// 拡張空間IDの集合を、半径 hDistance、高さ 2×vDistance の鉛直な円柱で膨張した範囲に相当する。
+//
+// 距離は拡張空間ID間の隙間(最も近い面の間の距離)とし、水平方向の大きさは赤道半径の球で近似する。
+// 経度方向の大きさには元の拡張空間IDの緯度における大きさを、緯度方向の大きさには間の各拡張空間IDの緯度における大きさを使用する。
+// そのため、水平方向の層目は元の拡張空間IDの緯度に応じて変化する。
+//
+// 経度方向は範囲を超えた場合に周回し、緯度方向の範囲外(極を越える範囲)は除外する。
+// 垂直方向は上下限なしとする。
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス。精度が混在していてもよい。
+//	hDistance ：水平方向の距離(単位:m、>= 0)
+//	vDistance ：垂直方向の距離(単位:m、>= 0)
+//
+// 戻り値：
+//
+//	拡張後の拡張空間IDのスライス。元の拡張空間IDを含む。
+//	入力と同じ精度の拡張空間IDが、水平方向精度、垂直方向精度、x成分、y成分、高さ成分の昇順で返却される。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのy成分が精度の範囲外の場合。
+//	 入力値不正                ：距離に負の値、無限大、NaNが入力されていた場合。
+//	                             元の拡張空間IDごとの拡張の移動量の個数が 1048576 を超える場合。
+func BufferSpatialIds(spatialIDs []string, hDistance, vDistance float64) ([]string, error) {
+	for _, distance := range []float64{hDistance, vDistance} {
+		if !(distance >= 0) || math.IsInf(distance, 1) {
+			return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("distance is out of range: %v", distance))
+		}
+	}
+
+	grids, zooms, err := newSpatialIDGridsOnZoom(spatialIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := []string{}
+	for _, zoom := range zooms {
+		dilated, err := grids[zoom].dilateOnOffsets(func(center shiftingIndex) ([][3]int64, error) {
+			return getBufferOffsets(center, hDistance, vDistance)
+		})
+		if err != nil {
+			return nil, err
+		}
+		buffered = append(buffered, dilated.spatialIDs()...)
+	}
+	return buffered, nil
+}
+
+// getBufferOffsets 距離指定の拡張の移動量の取得
+//
+// 層目は元の拡張空間IDの緯度により変化するため、移動量の個数の上限は元の拡張空間IDごとに確認する。
+//
+// 引数：
+//
+//	center   ：元の拡張空間IDの成分
+//	hDistance：水平方向の距離(単位:m)
+//	vDistance：垂直方向の距離(単位:m)
+//
+// 戻り値：
+//
+//	経度方向、緯度方向、高さ方向の移動量。中心(移動量0)を含む。
+//
+// 戻り値(エラー)：
+//
+//	移動量の個数が maxOffsetCount を超える場合エラーを返却する。
+func getBufferOffsets(center shiftingIndex, hDistance, vDistance float64) ([][3]int64, error) {
+	xLayersOnRow, vLayers, ok := getBufferLayers(center, hDistance, vDistance)
+	if !ok {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("buffer exceeds %d offsets at %v", maxOffsetCount, center))
+	}
+
+	offsets := [][3]int64{}
+	for y, xLayers := range xLayersOnRow {
+		for x := -xLayers; x <= xLayers; x++ {
+			for v := -vLayers; v <= vLayers; v++ {
+				offsets = append(offsets, [3]int64{x, y, v})
+			}
+		}
+	}
+	return offsets, nil
+}
+
+// getBufferLayers 距離指定の拡張の層目の取得
+//
+// 移動量の個数が maxOffsetCount を超えた時点で取得を打ち切る。
+//
+// 引数：
+//
+//	center   ：元の拡張空間IDの成分
+//	hDistance：水平方向の距離(単位:m)
+//	vDistance：垂直方向の距離(単位:m)
+//
+// 戻り値：
+//
+//	緯度方向の移動量ごとの経度方向の層目
+//	高さ方向の層目
+//	移動量の個数が maxOffsetCount 以下の場合 true
+func getBufferLayers(center shiftingIndex, hDistance, vDistance float64) (map[int64]int64, int64, bool) {
+	// 隙間 (k-1)×大きさ が距離未満となる最大の層目k。桁あふれしないよう上限で切り詰める。
+	getLayers := func(distance, size float64, limit int64) int64 {
+		if distance <= 0 {
+			return 0
+		}
+		return int64(min(math.Ceil(distance/size), float64(limit)))
+	}
+
+	limit := int64(1) << center.hZoom
+	hSize := center.horizontalSize()
+	vLayers := getLayers(vDistance, center.verticalSize(), maxOffsetCount)
+
+	count := 0.0
+	addRow := func(xLayers int64) bool {
+		count += float64(2*xLayers+1) * float64(2*vLayers+1)
+		return count <= maxOffsetCount
+	}
+
+	// 緯度方向の移動量ごとの経度方向の層目
+	xLayersOnRow := map[int64]int64{0: getLayers(hDistance, hSize, limit)}
+	if !addRow(xLayersOnRow[0]) {
+		return xLayersOnRow, vLayers, false
+	}
+	for _, sign := range []int64{-1, 1} {
+		gap := 0.0
+		for y := int64(1); hDistance > 0; y++ {
+			row := center.y + sign*y
+			if row < 0 || row >= limit {
+				break
+			}
+			if y > 1 {
+				gap += shiftingIndex{hZoom: center.hZoom, y: row - sign}.horizontalSize()
+			}
+			if gap >= hDistance {
+				break
+			}
+			xLayersOnRow[sign*y] = getLayers(math.Sqrt(hDistance*hDistance-gap*gap), hSize, limit)
+			if !addRow(xLayersOnRow[sign*y]) {
+				return xLayersOnRow, vLayers, false
+			}
+		}
+	}
+	return xLayersOnRow, vLayers, true
+}
@@ -0,0 +1,129 @@
+package operated
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestBufferSpatialIds01 拡張空間IDの集合の距離指定の拡張関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：赤道直下の"20/524288/524287/25/0"(水平方向の大きさ約38.2m)、水平方向50m、垂直方向1.5m
+//   - パターン2：パターン1と"10/512/511/20/0"の精度の混在、水平方向50m、垂直方向1.5m
+//   - パターン3：パターン1、水平方向0m、垂直方向0m
+//
+// + 確認内容
+//   - パターン1：水平方向は5×5から四隅を除いた21個、垂直方向は5個の105個が返却されること
+//   - パターン2：精度ごとに拡張され、水平方向精度の昇順で返却されること
+//   - パターン3：入力と同じ拡張空間IDが返却されること
+func TestBufferSpatialIds01(t *testing.T) {
+	id := "20/524288/524287/25/0"
+
+	resultVal, resultErr := BufferSpatialIds([]string{id}, 50, 1.5)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if len(resultVal) != 105 {
+		t.Errorf("空間ID - 期待要素数：105, 取得要素数：%v", len(resultVal))
+	}
+	for _, expectID := range []string{id, "20/524290/524288/25/2", "20/524287/524285/25/-2"} {
+		if !contains(resultVal, expectID) {
+			t.Errorf("空間ID - 期待値を含まない：%v", expectID)
+		}
+	}
+	for _, unexpectID := range []string{"20/524290/524289/25/0", "20/524288/524287/25/3", "20/524288/524284/25/0"} {
+		if contains(resultVal, unexpectID) {
+			t.Errorf("空間ID - 範囲外の拡張空間IDが返却された：%v", unexpectID)
+		}
+	}
+
+	resultVal, resultErr = BufferSpatialIds([]string{id, "10/512/511/20/0"}, 50, 1.5)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if len(resultVal) != 105+27 || resultVal[0] != "10/511/510/20/-1" || resultVal[27] != "20/524286/524286/25/-2" {
+		t.Errorf("空間ID - 取得値：%v", resultVal)
+	}
+
+	resultVal, resultErr = BufferSpatialIds([]string{id}, 0, 0)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if !reflect.DeepEqual(resultVal, []string{id}) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", []string{id}, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestBufferSpatialIds02 拡張空間IDの集合の距離指定の拡張関数 緯度による層目の確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：赤道直下の"20/524288/524287/25/0"、水平方向100m、垂直方向0m
+//   - パターン2：北緯約61度の"20/524288/297000/25/0"、水平方向100m、垂直方向0m
+//
+// + 確認内容
+//   - 経度方向の層目が、元の拡張空間IDの緯度における水平方向の大きさに応じて増加すること
+func TestBufferSpatialIds02(t *testing.T) {
+	testCases := []struct {
+		id        string
+		expectMax string
+		expectOut string
+	}{
+		// 赤道直下：⌈100/38.2⌉ = 3
+		{"20/524288/524287/25/0", "20/524291/524287/25/0", "20/524292/524287/25/0"},
+		// 北緯約61度：⌈100/18.3⌉ = 6
+		{"20/524288/297000/25/0", "20/524294/297000/25/0", "20/524295/297000/25/0"},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := BufferSpatialIds([]string{testCase.id}, 100, 0)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !contains(resultVal, testCase.expectMax) || contains(resultVal, testCase.expectOut) {
+			t.Errorf("空間ID - 経度方向の層目が不正：%v", testCase.id)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestBufferSpatialIds03 拡張空間IDの集合の距離指定の拡張関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：負の水平方向の距離
+//   - パターン2：NaNの垂直方向の距離
+//   - パターン3：拡張空間ID範囲外
+//   - パターン4：移動量の個数が上限を超える水平方向の距離
+//   - パターン5：移動量の個数が上限を超える垂直方向の距離
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestBufferSpatialIds03(t *testing.T) {
+	testCases := []struct {
+		spatialIDs []string
+		hDistance  float64
+		vDistance  float64
+		expectErr  string
+	}{
+		{[]string{"10/10/10/10/10"}, -1, 0, "InputValueError,入力チェックエラー,distance is out of range: -1"},
+		{[]string{"10/10/10/10/10"}, 0, math.NaN(), "InputValueError,入力チェックエラー,distance is out of range: NaN"},
+		{[]string{"2/0/4/10/10"}, 0, 0, "InputValueError,入力チェックエラー,index out of range: 2/0/4/10/10"},
+		{[]string{"25/0/16777216/25/0"}, 1e7, 0, "InputValueError,入力チェックエラー,buffer exceeds 1048576 offsets at 25/0/16777216/25/0"},
+		{[]string{"10/10/10/10/10"}, 0, 1e300, "InputValueError,入力チェックエラー,buffer exceeds 1048576 offsets at 10/10/10/10/10"},
+	}
+
+	for _, testCase := range testCases {
+		_, resultErr := BufferSpatialIds(testCase.spatialIDs, testCase.hDistance, testCase.vDistance)
+		if resultErr == nil || resultErr.Error() != testCase.expectErr {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expectErr, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
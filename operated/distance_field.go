@@ -0,0 +1,257 @@
+package operated
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+)
+
+// DistanceField 障害物からの距離場
+//
+// 対象範囲の拡張空間IDごとに、最も近い障害物の拡張空間IDまでの距離を保持する。
+// 経路探索のクリアランスのコストなどに使用する。
+type DistanceField struct {
+	hZoom     int64                     // 対象範囲の水平方向精度
+	vZoom     int64                     // 対象範囲の垂直方向精度
+	distances map[shiftingIndex]float64 // 対象範囲の拡張空間IDごとの距離(単位:m)
+}
+
+// distanceEntry 距離場の計算の優先度付きキューの要素
+type distanceEntry struct {
+	index    shiftingIndex // 拡張空間IDの成分
+	site     shiftingIndex // 最も近い障害物の拡張空間IDの成分
+	distance float64       // 障害物までの距離
+}
+
+// distanceQueue 距離場の計算の優先度付きキュー
+type distanceQueue []distanceEntry
+
+// NewDistanceField 障害物からの距離場の作成関数
+//
+// 対象範囲の拡張空間IDごとに、最も近い障害物の拡張空間IDまでの距離を計算する。
+// 距離は対象範囲の精度に変換した拡張空間IDの中心間の距離とし、障害物と重なる拡張空間IDの距離は0とする。
+// 水平方向の大きさは赤道半径の球で近似し、2つの拡張空間IDのうち小さい方を使用する。
+//
+// 障害物から周囲26個の拡張空間IDへ、最も近い障害物を伝搬して計算する。
+// 伝搬は対象範囲と障害物を包含する直方体の中で行い、経度方向の周回は考慮しない。
+//
+// 引数：
+//
+//	obstacleIDs：障害物の拡張空間IDのスライス。精度が混在していてもよい。
+//	regionIDs  ：対象範囲の拡張空間IDのスライス。全て同じ精度であること。
+//	maxDistance：計算する最大の距離(単位:m、>= 0)。超える場合の距離は math.Inf(1) とする。上限を設けない場合は math.Inf(1) を指定する。
+//
+// 戻り値：
+//
+//	距離場
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのy成分が精度の範囲外の場合。
+//	 入力値不正                ：対象範囲の精度が混在している場合、最大の距離に負の値、NaNが入力されていた場合。
+func NewDistanceField(obstacleIDs, regionIDs []string, maxDistance float64) (*DistanceField, error) {
+	if !(maxDistance >= 0) {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("distance is out of range: %v", maxDistance))
+	}
+
+	field := &DistanceField{distances: map[shiftingIndex]float64{}}
+	region := []shiftingIndex{}
+	for i, regionID := range regionIDs {
+		index, err := newShiftingIndex(regionID, enum.DropBoundary)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			field.hZoom, field.vZoom = index.hZoom, index.vZoom
+		} else if index.hZoom != field.hZoom || index.vZoom != field.vZoom {
+			return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "mixed zoom is not supported")
+		}
+		region = append(region, index)
+		field.distances[index] = math.Inf(1)
+	}
+	if len(region) == 0 {
+		return field, nil
+	}
+
+	lower := [3]int64{region[0].x, region[0].y, region[0].z}
+	upper := lower
+	for _, index := range region {
+		for i, value := range [3]int64{index.x, index.y, index.z} {
+			lower[i], upper[i] = min(lower[i], value), max(upper[i], value)
+		}
+	}
+
+	// 対象範囲の精度の障害物。対象範囲の各拡張空間IDに最も近い部分のみ残す。
+	sites := map[shiftingIndex]struct{}{}
+	for _, obstacleID := range obstacleIDs {
+		obstacle, err := newShiftingIndex(obstacleID, enum.DropBoundary)
+		if err != nil {
+			return nil, err
+		}
+		first, last := field.getObstacleRange(obstacle)
+		for i := range first {
+			first[i], last[i] = max(first[i], min(lower[i], last[i])), min(last[i], max(upper[i], first[i]))
+		}
+		for x := first[0]; x <= last[0]; x++ {
+			for y := first[1]; y <= last[1]; y++ {
+				for z := first[2]; z <= last[2]; z++ {
+					sites[shiftingIndex{hZoom: field.hZoom, x: x, y: y, vZoom: field.vZoom, z: z}] = struct{}{}
+				}
+			}
+		}
+	}
+
+	queue := &distanceQueue{}
+	for site := range sites {
+		for i, value := range [3]int64{site.x, site.y, site.z} {
+			lower[i], upper[i] = min(lower[i], value), max(upper[i], value)
+		}
+		heap.Push(queue, distanceEntry{index: site, site: site})
+	}
+
+	offsets := getNeighborOffsets()
+	closed := map[shiftingIndex]struct{}{}
+	for queue.Len() > 0 {
+		entry := heap.Pop(queue).(distanceEntry)
+		if _, ok := closed[entry.index]; ok {
+			continue
+		}
+		closed[entry.index] = struct{}{}
+		if _, ok := field.distances[entry.index]; ok {
+			field.distances[entry.index] = entry.distance
+		}
+
+		for _, offset := range offsets {
+			next := entry.index
+			next.x, next.y, next.z = next.x+offset[0], next.y+offset[1], next.z+offset[2]
+			if next.x < lower[0] || next.x > upper[0] || next.y < lower[1] || next.y > upper[1] || next.z < lower[2] || next.z > upper[2] {
+				continue
+			}
+			if _, ok := closed[next]; ok {
+				continue
+			}
+			if distance := next.distanceTo(entry.site); distance <= maxDistance {
+				heap.Push(queue, distanceEntry{index: next, site: entry.site, distance: distance})
+			}
+		}
+	}
+
+	return field, nil
+}
+
+// Distance 距離の取得
+//
+// 引数：
+//
+//	spatialID：対象範囲の拡張空間ID
+//
+// 戻り値：
+//
+//	最も近い障害物までの距離(単位:m)。最大の距離を超える場合は math.Inf(1)
+//
+// 戻り値(エラー)：
+//
+//	拡張空間IDが不正な場合、対象範囲に含まれない場合エラーを返却する。
+func (f *DistanceField) Distance(spatialID string) (float64, error) {
+	index, err := newShiftingIndex(spatialID, enum.DropBoundary)
+	if err != nil {
+		return 0, err
+	}
+	distance, ok := f.distances[index]
+	if !ok {
+		return 0, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("out of region: %v", spatialID))
+	}
+	return distance, nil
+}
+
+// Distances 全ての距離の取得
+//
+// 戻り値：
+//
+//	対象範囲の拡張空間IDをキー、最も近い障害物までの距離(単位:m)を値とするマップ
+func (f *DistanceField) Distances() map[string]float64 {
+	distances := make(map[string]float64, len(f.distances))
+	for index, distance := range f.distances {
+		distances[index.String()] = distance
+	}
+	return distances
+}
+
+// SpatialIds 距離の範囲指定の拡張空間ID取得
+//
+// 引数：
+//
+//	minDistance：最小の距離(単位:m)
+//	maxDistance：最大の距離(単位:m)
+//
+// 戻り値：
+//
+//	距離が minDistance 以上 maxDistance 以下の対象範囲の拡張空間IDのスライス。
+//	x成分、y成分、高さ成分の昇順で返却される。
+func (f *DistanceField) SpatialIds(minDistance, maxDistance float64) []string {
+	grid := spatialIDGrid{}
+	for index, distance := range f.distances {
+		if distance >= minDistance && distance <= maxDistance {
+			grid[index] = struct{}{}
+		}
+	}
+	return grid.spatialIDs()
+}
+
+// getObstacleRange 障害物と重なる対象範囲の精度の拡張空間IDの範囲の取得
+//
+// 引数：
+//
+//	obstacle：障害物の拡張空間IDの成分
+//
+// 戻り値：
+//
+//	x成分、y成分、高さ成分の最小値と最大値
+func (f *DistanceField) getObstacleRange(obstacle shiftingIndex) ([3]int64, [3]int64) {
+	getRange := func(value, zoom, targetZoom int64) (int64, int64) {
+		if zoom >= targetZoom {
+			return value >> (zoom - targetZoom), value >> (zoom - targetZoom)
+		}
+		return value << (targetZoom - zoom), (value+1)<<(targetZoom-zoom) - 1
+	}
+
+	var first, last [3]int64
+	first[0], last[0] = getRange(obstacle.x, obstacle.hZoom, f.hZoom)
+	first[1], last[1] = getRange(obstacle.y, obstacle.hZoom, f.hZoom)
+	first[2], last[2] = getRange(obstacle.z, obstacle.vZoom, f.vZoom)
+	return first, last
+}
+
+// Len heap.Interface の実装
+func (q distanceQueue) Len() int {
+	return len(q)
+}
+
+// Less heap.Interface の実装
+func (q distanceQueue) Less(i, j int) bool {
+	return q[i].distance < q[j].distance
+}
+
+// Swap heap.Interface の実装
+func (q distanceQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Push heap.Interface の実装
+func (q *distanceQueue) Push(x any) {
+	*q = append(*q, x.(distanceEntry))
+}
+
+// Pop heap.Interface の実装
+func (q *distanceQueue) Pop() any {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
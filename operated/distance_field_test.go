@@ -0,0 +1,130 @@
+package operated
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestNewDistanceField01 障害物からの距離場の作成関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 対象範囲：赤道直下の"20/524288/524287/25/0"から東に並んだ5個
+//   - パターン1：障害物"20/524288/524287/25/0"
+//   - パターン2：対象範囲の外の障害物"20/524288/524287/25/5"
+//   - パターン3：精度の粗い障害物"19/262144/262143/24/0"
+//   - パターン4：パターン1、最大の距離を拡張空間ID1.5個分
+//
+// + 確認内容
+//   - 各拡張空間IDの中心から最も近い障害物の中心までの距離が返却されること
+//   - 最大の距離を超える場合は math.Inf(1) が返却されること
+func TestNewDistanceField01(t *testing.T) {
+	region := []string{}
+	for x := int64(524288); x < 524293; x++ {
+		region = append(region, shiftingIndex{hZoom: 20, x: x, y: 524287, vZoom: 25}.String())
+	}
+	h := shiftingIndex{hZoom: 20, y: 524287}.horizontalSize()
+	inf := math.Inf(1)
+
+	testCases := []struct {
+		obstacleIDs []string
+		maxDistance float64
+		expectVal   []float64
+	}{
+		{[]string{"20/524288/524287/25/0"}, inf, []float64{0, h, 2 * h, 3 * h, 4 * h}},
+		{[]string{"20/524288/524287/25/5"}, inf, []float64{5, math.Hypot(h, 5), math.Hypot(2*h, 5), math.Hypot(3*h, 5), math.Hypot(4*h, 5)}},
+		{[]string{"19/262144/262143/24/0"}, inf, []float64{0, 0, h, 2 * h, 3 * h}},
+		{[]string{"20/524288/524287/25/0"}, 1.5 * h, []float64{0, h, inf, inf, inf}},
+	}
+
+	for _, testCase := range testCases {
+		field, resultErr := NewDistanceField(testCase.obstacleIDs, region, testCase.maxDistance)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		for i, spatialID := range region {
+			resultVal, resultErr := field.Distance(spatialID)
+			if resultErr != nil {
+				t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+			}
+			if !(resultVal == testCase.expectVal[i] || math.Abs(resultVal-testCase.expectVal[i]) < 1e-9) {
+				t.Errorf("距離 %v - 期待値：%v, 取得値：%v", spatialID, testCase.expectVal[i], resultVal)
+			}
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestNewDistanceField02 障害物からの距離場の作成関数 取得方法の確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 対象範囲：赤道直下の"20/524288/524287/25/0"から東に並んだ3個
+//   - 障害物："20/524288/524287/25/0"
+//
+// + 確認内容
+//   - Distances で対象範囲の全ての距離が返却されること
+//   - SpatialIds で距離の範囲内の拡張空間IDが昇順で返却されること
+func TestNewDistanceField02(t *testing.T) {
+	region := []string{"20/524290/524287/25/0", "20/524289/524287/25/0", "20/524288/524287/25/0"}
+	h := shiftingIndex{hZoom: 20, y: 524287}.horizontalSize()
+
+	field, resultErr := NewDistanceField([]string{"20/524288/524287/25/0"}, region, math.Inf(1))
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	distances := field.Distances()
+	if len(distances) != 3 || distances["20/524288/524287/25/0"] != 0 || math.Abs(distances["20/524290/524287/25/0"]-2*h) > 1e-9 {
+		t.Errorf("距離 - 取得値：%v", distances)
+	}
+
+	resultVal := field.SpatialIds(0.5*h, math.Inf(1))
+	expectVal := []string{"20/524289/524287/25/0", "20/524290/524287/25/0"}
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestNewDistanceField03 障害物からの距離場の作成関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：負の最大の距離
+//   - パターン2：精度の混在した対象範囲
+//   - パターン3：拡張空間ID範囲外の障害物
+//   - パターン4：対象範囲に含まれない拡張空間IDの距離の取得
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestNewDistanceField03(t *testing.T) {
+	testCases := []struct {
+		obstacleIDs []string
+		regionIDs   []string
+		maxDistance float64
+		expectErr   string
+	}{
+		{[]string{}, []string{"10/10/10/10/10"}, -1, "InputValueError,入力チェックエラー,distance is out of range: -1"},
+		{[]string{}, []string{"10/10/10/10/10", "11/10/10/10/10"}, 0, "InputValueError,入力チェックエラー,mixed zoom is not supported"},
+		{[]string{"2/0/4/10/10"}, []string{"10/10/10/10/10"}, 0, "InputValueError,入力チェックエラー,index out of range: 2/0/4/10/10"},
+	}
+
+	for _, testCase := range testCases {
+		_, resultErr := NewDistanceField(testCase.obstacleIDs, testCase.regionIDs, testCase.maxDistance)
+		if resultErr == nil || resultErr.Error() != testCase.expectErr {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expectErr, resultErr)
+		}
+	}
+
+	field, _ := NewDistanceField([]string{}, []string{"10/10/10/10/10"}, 0)
+	_, resultErr := field.Distance("10/11/10/10/10")
+	if expectErr := "InputValueError,入力チェックエラー,out of region: 10/11/10/10/10"; resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}
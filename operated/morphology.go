@@ -0,0 +1,492 @@
+package operated
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+)
+
+// 構造要素の球の大きさの計算に使用する定数
+const earthEquatorialRadius = 6378137.0 // 赤道半径(単位:m)
+
+// 構造要素、距離指定の拡張の移動量の個数の上限
+//
+// 移動量は層目の2乗と高さ方向の層目の積の個数となるため、上限を超える場合は入力値不正とする。
+const maxOffsetCount = 1 << 20
+
+// StructuringElement 形態素演算の構造要素の構造体
+//
+// 構造要素は中心に対して対称な形状とする。
+type StructuringElement struct {
+	Shape   enum.ElementShape // 構造要素の形状
+	HLayers int64             // BoxElement の水平方向の層目(>= 0)
+	VLayers int64             // BoxElement の垂直方向の層目(>= 0)
+	Radius  float64           // SphereElement の半径(単位:m、>= 0)
+}
+
+// spatialIDGrid 形態素演算用の同一精度の拡張空間IDの集合
+type spatialIDGrid map[shiftingIndex]struct{}
+
+// DilateSpatialIds 拡張空間IDの膨張関数
+//
+// 拡張空間IDの集合の各拡張空間IDに構造要素を重ねた範囲の和集合を取得する。
+//
+// 経度方向は範囲を超えた場合に周回し、緯度方向の範囲外(極を越える範囲)は除外する。
+// 垂直方向は上下限なしとする。
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス。全て同じ水平方向精度、垂直方向精度であること。
+//	element   ：構造要素
+//
+// 戻り値：
+//
+//	膨張後の拡張空間IDのスライス。x成分、y成分、高さ成分の昇順で返却される。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのy成分が精度の範囲外の場合。
+//	 入力値不正                ：精度の異なる拡張空間IDが混在している場合、構造要素が不正な場合。
+//	                             構造要素を包含する直方体の拡張空間IDの個数が 1048576 を超える場合。
+func DilateSpatialIds(spatialIDs []string, element StructuringElement) ([]string, error) {
+	grid, err := newSpatialIDGrid(spatialIDs, element)
+	if err != nil {
+		return nil, err
+	}
+	dilated, err := grid.dilate(element)
+	if err != nil {
+		return nil, err
+	}
+	return dilated.spatialIDs(), nil
+}
+
+// ErodeSpatialIds 拡張空間IDの収縮関数
+//
+// 構造要素を重ねた範囲が全て拡張空間IDの集合に含まれる拡張空間IDのみを取得する。
+//
+// 範囲の扱いは DilateSpatialIds と同じとする。
+// 構造要素のうち緯度方向の範囲外となる部分は判定に使用しない。
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス。全て同じ水平方向精度、垂直方向精度であること。
+//	element   ：構造要素
+//
+// 戻り値：
+//
+//	収縮後の拡張空間IDのスライス。x成分、y成分、高さ成分の昇順で返却される。
+//
+// 戻り値(エラー)：
+//
+//	DilateSpatialIds と同じ条件でエラーインスタンスが返却される。
+func ErodeSpatialIds(spatialIDs []string, element StructuringElement) ([]string, error) {
+	grid, err := newSpatialIDGrid(spatialIDs, element)
+	if err != nil {
+		return nil, err
+	}
+	eroded, err := grid.erode(element)
+	if err != nil {
+		return nil, err
+	}
+	return eroded.spatialIDs(), nil
+}
+
+// OpenSpatialIds 拡張空間IDのオープニング関数
+//
+// 収縮の後に膨張を行い、構造要素より小さい突起や孤立した拡張空間IDを除去する。
+// 結果は入力の拡張空間IDの集合に包含される。
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス。全て同じ水平方向精度、垂直方向精度であること。
+//	element   ：構造要素
+//
+// 戻り値：
+//
+//	オープニング後の拡張空間IDのスライス。x成分、y成分、高さ成分の昇順で返却される。
+//
+// 戻り値(エラー)：
+//
+//	DilateSpatialIds と同じ条件でエラーインスタンスが返却される。
+func OpenSpatialIds(spatialIDs []string, element StructuringElement) ([]string, error) {
+	grid, err := newSpatialIDGrid(spatialIDs, element)
+	if err != nil {
+		return nil, err
+	}
+	eroded, err := grid.erode(element)
+	if err != nil {
+		return nil, err
+	}
+	opened, err := eroded.dilate(element)
+	if err != nil {
+		return nil, err
+	}
+	return opened.spatialIDs(), nil
+}
+
+// CloseSpatialIds 拡張空間IDのクロージング関数
+//
+// 膨張の後に収縮を行い、構造要素より小さい隙間や穴を埋める。
+// 結果は入力の拡張空間IDの集合を包含する。
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス。全て同じ水平方向精度、垂直方向精度であること。
+//	element   ：構造要素
+//
+// 戻り値：
+//
+//	クロージング後の拡張空間IDのスライス。x成分、y成分、高さ成分の昇順で返却される。
+//
+// 戻り値(エラー)：
+//
+//	DilateSpatialIds と同じ条件でエラーインスタンスが返却される。
+func CloseSpatialIds(spatialIDs []string, element StructuringElement) ([]string, error) {
+	grid, err := newSpatialIDGrid(spatialIDs, element)
+	if err != nil {
+		return nil, err
+	}
+	dilated, err := grid.dilate(element)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := dilated.erode(element)
+	if err != nil {
+		return nil, err
+	}
+	return closed.spatialIDs(), nil
+}
+
+// FillSmallGapsSpatialIds 拡張空間IDの小さな隙間の充填関数
+//
+// 指定された個数以下の拡張空間IDの幅の隙間を埋める。
+// 水平方向の層目を ⌈hGap/2⌉、垂直方向の層目を ⌈vGap/2⌉ とした BoxElement によるクロージングを行う。
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス。全て同じ水平方向精度、垂直方向精度であること。
+//	hGap      ：埋める水平方向の隙間の幅(拡張空間IDの個数、>= 0)
+//	vGap      ：埋める垂直方向の隙間の幅(拡張空間IDの個数、>= 0)
+//
+// 戻り値：
+//
+//	隙間を埋めた拡張空間IDのスライス。x成分、y成分、高さ成分の昇順で返却される。
+//
+// 戻り値(エラー)：
+//
+//	DilateSpatialIds と同じ条件でエラーインスタンスが返却される。
+func FillSmallGapsSpatialIds(spatialIDs []string, hGap, vGap int64) ([]string, error) {
+	if hGap < 0 || vGap < 0 {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "both hGap and vGap parameters must be >= 0")
+	}
+
+	element := StructuringElement{Shape: enum.BoxElement, HLayers: (hGap + 1) / 2, VLayers: (vGap + 1) / 2}
+	return CloseSpatialIds(spatialIDs, element)
+}
+
+// newSpatialIDGrid 形態素演算用の拡張空間IDの集合の作成
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス
+//	element   ：構造要素
+//
+// 戻り値：
+//
+//	拡張空間IDの集合
+//
+// 戻り値(エラー)：
+//
+//	拡張空間ID、構造要素が不正な場合、精度の異なる拡張空間IDが混在している場合エラーを返却する。
+func newSpatialIDGrid(spatialIDs []string, element StructuringElement) (spatialIDGrid, error) {
+	switch element.Shape {
+	case enum.BoxElement:
+		if element.HLayers < 0 || element.VLayers < 0 {
+			return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "both HLayers and VLayers parameters must be >= 0")
+		}
+	case enum.CrossElement:
+	case enum.SphereElement:
+		if !(element.Radius >= 0) || math.IsInf(element.Radius, 1) {
+			return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("radius is out of range: %v", element.Radius))
+		}
+	default:
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("unsupported element shape: %v", element.Shape))
+	}
+
+	grid := spatialIDGrid{}
+	for _, spatialID := range spatialIDs {
+		index, err := newShiftingIndex(spatialID, enum.DropBoundary)
+		if err != nil {
+			return nil, err
+		}
+		for first := range grid {
+			if first.hZoom != index.hZoom || first.vZoom != index.vZoom {
+				return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "mixed zoom is not supported")
+			}
+			break
+		}
+		grid[index] = struct{}{}
+	}
+
+	return grid, nil
+}
+
+// newSpatialIDGridsOnZoom 精度ごとの拡張空間IDの集合の作成
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス。精度が混在していてもよい。
+//
+// 戻り値：
+//
+//	水平方向精度、垂直方向精度ごとの拡張空間IDの集合
+//	水平方向精度、垂直方向精度の昇順の精度のスライス
+//
+// 戻り値(エラー)：
+//
+//	拡張空間IDが不正な場合エラーを返却する。
+func newSpatialIDGridsOnZoom(spatialIDs []string) (map[[2]int64]spatialIDGrid, [][2]int64, error) {
+	grids := map[[2]int64]spatialIDGrid{}
+	for _, spatialID := range spatialIDs {
+		index, err := newShiftingIndex(spatialID, enum.DropBoundary)
+		if err != nil {
+			return nil, nil, err
+		}
+		zoom := [2]int64{index.hZoom, index.vZoom}
+		if _, ok := grids[zoom]; !ok {
+			grids[zoom] = spatialIDGrid{}
+		}
+		grids[zoom][index] = struct{}{}
+	}
+
+	zooms := make([][2]int64, 0, len(grids))
+	for zoom := range grids {
+		zooms = append(zooms, zoom)
+	}
+	sort.Slice(zooms, func(i, j int) bool {
+		if zooms[i][0] != zooms[j][0] {
+			return zooms[i][0] < zooms[j][0]
+		}
+		return zooms[i][1] < zooms[j][1]
+	})
+
+	return grids, zooms, nil
+}
+
+// dilate 拡張空間IDの集合の膨張
+//
+// 引数：
+//
+//	element：構造要素
+//
+// 戻り値：
+//
+//	膨張後の集合
+//
+// 戻り値(エラー)：
+//
+//	構造要素の移動量の個数が上限を超える場合エラーを返却する。
+func (g spatialIDGrid) dilate(element StructuringElement) (spatialIDGrid, error) {
+	return g.dilateOnOffsets(element.offsets)
+}
+
+// dilateOnOffsets 移動量指定の拡張空間IDの集合の膨張
+//
+// 移動量は緯度方向の位置ごとに1度だけ取得する。
+//
+// 引数：
+//
+//	getOffsets：中心の拡張空間IDの成分から、中心を含む移動量を取得する関数。移動量の個数が上限を超える場合はエラーを返却する。
+//
+// 戻り値：
+//
+//	膨張後の集合
+//
+// 戻り値(エラー)：
+//
+//	getOffsets が返却したエラー
+func (g spatialIDGrid) dilateOnOffsets(getOffsets func(center shiftingIndex) ([][3]int64, error)) (spatialIDGrid, error) {
+	offsetsOnRow := map[int64][][3]int64{}
+
+	dilated := spatialIDGrid{}
+	for index := range g {
+		offsets, ok := offsetsOnRow[index.y]
+		if !ok {
+			var err error
+			if offsets, err = getOffsets(index); err != nil {
+				return nil, err
+			}
+			offsetsOnRow[index.y] = offsets
+		}
+		for _, offset := range offsets {
+			if shifted, ok := index.shiftWithPolicy(offset[0], offset[1], offset[2], enum.DropBoundary); ok {
+				dilated[shifted] = struct{}{}
+			}
+		}
+	}
+
+	return dilated, nil
+}
+
+// erode 拡張空間IDの集合の収縮
+//
+// 引数：
+//
+//	element：構造要素
+//
+// 戻り値：
+//
+//	収縮後の集合
+//
+// 戻り値(エラー)：
+//
+//	構造要素の移動量の個数が上限を超える場合エラーを返却する。
+func (g spatialIDGrid) erode(element StructuringElement) (spatialIDGrid, error) {
+	offsetsOnRow := map[int64][][3]int64{}
+
+	eroded := spatialIDGrid{}
+	for index := range g {
+		offsets, ok := offsetsOnRow[index.y]
+		if !ok {
+			var err error
+			if offsets, err = element.offsets(index); err != nil {
+				return nil, err
+			}
+			offsetsOnRow[index.y] = offsets
+		}
+
+		isInside := true
+		for _, offset := range offsets {
+			shifted, ok := index.shiftWithPolicy(offset[0], offset[1], offset[2], enum.DropBoundary)
+			if !ok {
+				continue
+			}
+			if _, ok := g[shifted]; !ok {
+				isInside = false
+				break
+			}
+		}
+		if isInside {
+			eroded[index] = struct{}{}
+		}
+	}
+
+	return eroded, nil
+}
+
+// spatialIDs 拡張空間IDの集合の文字列化
+//
+// 戻り値：
+//
+//	x成分、y成分、高さ成分の昇順の拡張空間IDのスライス
+func (g spatialIDGrid) spatialIDs() []string {
+	indexes := make([]shiftingIndex, 0, len(g))
+	for index := range g {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		a, b := indexes[i], indexes[j]
+		if a.x != b.x {
+			return a.x < b.x
+		}
+		if a.y != b.y {
+			return a.y < b.y
+		}
+		return a.z < b.z
+	})
+
+	spatialIDs := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		spatialIDs = append(spatialIDs, index.String())
+	}
+	return spatialIDs
+}
+
+// offsetCount 構造要素を包含する直方体の移動量の個数の取得
+//
+// 層目が大きい場合に桁あふれしないよう、浮動小数点数で計算する。
+//
+// 引数：
+//
+//	center：中心の拡張空間IDの成分
+//
+// 戻り値：
+//
+//	offsets が走査する移動量の個数
+func (e StructuringElement) offsetCount(center shiftingIndex) float64 {
+	var hLayers, vLayers float64
+	switch e.Shape {
+	case enum.CrossElement:
+		hLayers, vLayers = 1, 1
+	case enum.SphereElement:
+		hLayers = math.Floor(e.Radius / center.horizontalSize())
+		vLayers = math.Floor(e.Radius / center.verticalSize())
+	default:
+		hLayers, vLayers = float64(e.HLayers), float64(e.VLayers)
+	}
+	return (2*hLayers + 1) * (2*hLayers + 1) * (2*vLayers + 1)
+}
+
+// offsets 構造要素の移動量の取得
+//
+// SphereElement の場合、中心の拡張空間IDの緯度における拡張空間IDの大きさから、
+// 拡張空間IDの中心間の距離が半径以下となる移動量を求める。
+// 水平方向の大きさは赤道半径の球で近似し、メルカトル図法の等角性から東西と南北を同じ大きさとする。
+//
+// SphereElement の層目は緯度により変化するため、移動量の個数の上限は中心の拡張空間IDごとに確認する。
+//
+// 引数：
+//
+//	center：中心の拡張空間IDの成分
+//
+// 戻り値：
+//
+//	経度方向、緯度方向、高さ方向の移動量。中心(移動量0)を含む。
+//
+// 戻り値(エラー)：
+//
+//	構造要素を包含する直方体の移動量の個数が maxOffsetCount を超える場合エラーを返却する。
+func (e StructuringElement) offsets(center shiftingIndex) ([][3]int64, error) {
+	if e.offsetCount(center) > maxOffsetCount {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("structuring element exceeds %d offsets at %v", maxOffsetCount, center))
+	}
+
+	switch e.Shape {
+	case enum.CrossElement:
+		return [][3]int64{{0, 0, 0}, {-1, 0, 0}, {1, 0, 0}, {0, -1, 0}, {0, 1, 0}, {0, 0, -1}, {0, 0, 1}}, nil
+	case enum.SphereElement:
+		hSize := center.horizontalSize()
+		vSize := center.verticalSize()
+
+		hLayers := int64(e.Radius / hSize)
+		vLayers := int64(e.Radius / vSize)
+		offsets := [][3]int64{}
+		for x := -hLayers; x <= hLayers; x++ {
+			for y := -hLayers; y <= hLayers; y++ {
+				for v := -vLayers; v <= vLayers; v++ {
+					dx, dy, dv := float64(x)*hSize, float64(y)*hSize, float64(v)*vSize
+					if dx*dx+dy*dy+dv*dv <= e.Radius*e.Radius {
+						offsets = append(offsets, [3]int64{x, y, v})
+					}
+				}
+			}
+		}
+		return offsets, nil
+	default:
+		offsets := [][3]int64{}
+		for x := -e.HLayers; x <= e.HLayers; x++ {
+			for y := -e.HLayers; y <= e.HLayers; y++ {
+				for v := -e.VLayers; v <= e.VLayers; v++ {
+					offsets = append(offsets, [3]int64{x, y, v})
+				}
+			}
+		}
+		return offsets, nil
+	}
+}
@@ -0,0 +1,256 @@
+package operated
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
+)
+
+// getCubeSpatialIds 試験用の立方体の拡張空間IDの取得
+//
+// 引数：
+//
+//	layers：中心"10/10/10/10/10"からの層目
+//
+// 戻り値：
+//
+//	中心と層目以内の拡張空間IDのスライス
+func getCubeSpatialIds(layers int64) []string {
+	ids, _ := DilateSpatialIds([]string{"10/10/10/10/10"}, StructuringElement{Shape: enum.BoxElement, HLayers: layers, VLayers: layers})
+	return ids
+}
+
+// TestDilateSpatialIds01 拡張空間IDの膨張関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1："10/10/10/10/10"、CrossElement
+//   - パターン2："10/10/10/10/10"、BoxElement(水平方向1層、垂直方向0層)
+//   - パターン3：赤道直下の"20/524288/524287/25/0"、SphereElement(半径1.5m)
+//
+// + 確認内容
+//   - 構造要素を重ねた範囲の拡張空間IDが昇順で返却されること
+func TestDilateSpatialIds01(t *testing.T) {
+	testCases := []struct {
+		spatialID string
+		element   StructuringElement
+		expectVal []string
+	}{
+		{
+			"10/10/10/10/10",
+			StructuringElement{Shape: enum.CrossElement},
+			[]string{"10/9/10/10/10", "10/10/9/10/10", "10/10/10/10/9", "10/10/10/10/10", "10/10/10/10/11", "10/10/11/10/10", "10/11/10/10/10"},
+		},
+		{
+			"10/10/10/10/10",
+			StructuringElement{Shape: enum.BoxElement, HLayers: 1},
+			[]string{
+				"10/9/9/10/10", "10/9/10/10/10", "10/9/11/10/10",
+				"10/10/9/10/10", "10/10/10/10/10", "10/10/11/10/10",
+				"10/11/9/10/10", "10/11/10/10/10", "10/11/11/10/10",
+			},
+		},
+		{
+			"20/524288/524287/25/0",
+			StructuringElement{Shape: enum.SphereElement, Radius: 1.5},
+			[]string{"20/524288/524287/25/-1", "20/524288/524287/25/0", "20/524288/524287/25/1"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := DilateSpatialIds([]string{testCase.spatialID}, testCase.element)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultVal, testCase.expectVal) {
+			t.Errorf("空間ID - 期待値：%v, 取得値：%v", testCase.expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestDilateSpatialIds02 拡張空間IDの膨張関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：精度の異なる拡張空間IDが混在
+//   - パターン2：未対応の構造要素の形状
+//   - パターン3：負の層目
+//   - パターン4：負の半径
+//   - パターン5：移動量の個数が上限を超える層目
+//   - パターン6：移動量の個数が上限を超える半径
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestDilateSpatialIds02(t *testing.T) {
+	testCases := []struct {
+		spatialIDs []string
+		element    StructuringElement
+		expectErr  string
+	}{
+		{[]string{"10/10/10/10/10", "11/10/10/10/10"}, StructuringElement{Shape: enum.CrossElement}, "InputValueError,入力チェックエラー,mixed zoom is not supported"},
+		{[]string{"10/10/10/10/10"}, StructuringElement{Shape: enum.ElementShape(3)}, "InputValueError,入力チェックエラー,unsupported element shape: 3"},
+		{[]string{"10/10/10/10/10"}, StructuringElement{Shape: enum.BoxElement, HLayers: -1}, "InputValueError,入力チェックエラー,both HLayers and VLayers parameters must be >= 0"},
+		{[]string{"10/10/10/10/10"}, StructuringElement{Shape: enum.SphereElement, Radius: -1}, "InputValueError,入力チェックエラー,radius is out of range: -1"},
+		{[]string{"10/10/10/10/10"}, StructuringElement{Shape: enum.BoxElement, HLayers: 1 << 40}, "InputValueError,入力チェックエラー,structuring element exceeds 1048576 offsets at 10/10/10/10/10"},
+		{[]string{"20/524288/524287/25/0"}, StructuringElement{Shape: enum.SphereElement, Radius: 1000}, "InputValueError,入力チェックエラー,structuring element exceeds 1048576 offsets at 20/524288/524287/25/0"},
+	}
+
+	for _, testCase := range testCases {
+		_, resultErr := DilateSpatialIds(testCase.spatialIDs, testCase.element)
+		if resultErr == nil || resultErr.Error() != testCase.expectErr {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expectErr, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestErodeSpatialIds01 拡張空間IDの収縮関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1："10/10/10/10/10"を中心とする3×3×3の立方体、CrossElement
+//   - パターン2：水平方向精度1の全タイルの高さ成分-1～1、CrossElement
+//
+// + 確認内容
+//   - パターン1：中心の拡張空間IDのみ返却されること
+//   - パターン2：経度方向は周回し、緯度方向の範囲外は判定に使用されず、高さ成分0の4個が返却されること
+func TestErodeSpatialIds01(t *testing.T) {
+	ids := getCubeSpatialIds(1)
+	resultVal, resultErr := ErodeSpatialIds(ids, StructuringElement{Shape: enum.CrossElement})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	expectVal := []string{"10/10/10/10/10"}
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	// 水平方向精度1の全タイルは、極を越える範囲を除いて判定される
+	ids = []string{}
+	for _, tile := range []string{"1/0/0", "1/1/0", "1/0/1", "1/1/1"} {
+		for _, v := range []string{"10/-1", "10/0", "10/1"} {
+			ids = append(ids, tile+"/"+v)
+		}
+	}
+	resultVal, resultErr = ErodeSpatialIds(ids, StructuringElement{Shape: enum.CrossElement})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	expectVal = []string{"1/0/0/10/0", "1/0/1/10/0", "1/1/0/10/0", "1/1/1/10/0"}
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestOpenSpatialIds01 拡張空間IDのオープニング関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：3×3×3の立方体と孤立した"10/20/20/10/10"、CrossElement
+//
+// + 確認内容
+//   - 孤立した拡張空間IDと立方体の角が除去され、7個の拡張空間IDが返却されること
+func TestOpenSpatialIds01(t *testing.T) {
+	ids := append(getCubeSpatialIds(1), "10/20/20/10/10")
+
+	resultVal, resultErr := OpenSpatialIds(ids, StructuringElement{Shape: enum.CrossElement})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	expectVal, _ := DilateSpatialIds([]string{"10/10/10/10/10"}, StructuringElement{Shape: enum.CrossElement})
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestCloseSpatialIds01 拡張空間IDのクロージング関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：中心の抜けた3×3×3の立方体、CrossElement
+//
+// + 確認内容
+//   - 中心の穴が埋められた立方体が返却されること
+func TestCloseSpatialIds01(t *testing.T) {
+	cube := getCubeSpatialIds(1)
+	ids := []string{}
+	for _, id := range cube {
+		if id != "10/10/10/10/10" {
+			ids = append(ids, id)
+		}
+	}
+
+	resultVal, resultErr := CloseSpatialIds(ids, StructuringElement{Shape: enum.CrossElement})
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if !reflect.DeepEqual(resultVal, cube) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", cube, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestCloseSpatialIds02 拡張空間IDのクロージング関数 膨張で追加された緯度方向の位置の入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1："10/0/500/0/0"、"10/0/500/0/0"の水平方向の大きさの511.5倍の半径の SphereElement
+//     (入力の緯度では移動量の個数が1023×1023で上限以下、極側の緯度では上限超過)
+//
+// + 確認内容
+//   - 膨張後の収縮でエラーインスタンス（InputValueErrorCode）が返却されること
+func TestCloseSpatialIds02(t *testing.T) {
+	element := StructuringElement{Shape: enum.SphereElement, Radius: 511.5 * shiftingIndex{hZoom: 10, y: 500}.horizontalSize()}
+
+	_, resultErr := CloseSpatialIds([]string{"10/0/500/0/0"}, element)
+	expectErr := "InputValueError,入力チェックエラー,structuring element exceeds 1048576 offsets at "
+	if resultErr == nil || !strings.HasPrefix(resultErr.Error(), expectErr) {
+		t.Errorf("error - 期待値：%s..., 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestFillSmallGapsSpatialIds01 拡張空間IDの小さな隙間の充填関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：経度方向に1個の隙間のある"10/10/10/10/10"、"10/12/10/10/10"、水平方向の隙間1
+//   - パターン2：パターン1と同じ拡張空間ID、水平方向の隙間0
+//
+// + 確認内容
+//   - パターン1：隙間の"10/11/10/10/10"が埋められること
+//   - パターン2：入力と同じ拡張空間IDが返却されること
+func TestFillSmallGapsSpatialIds01(t *testing.T) {
+	ids := []string{"10/10/10/10/10", "10/12/10/10/10"}
+
+	resultVal, resultErr := FillSmallGapsSpatialIds(ids, 1, 0)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	expectVal := []string{"10/10/10/10/10", "10/11/10/10/10", "10/12/10/10/10"}
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	resultVal, resultErr = FillSmallGapsSpatialIds(ids, 0, 0)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if !reflect.DeepEqual(resultVal, ids) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", ids, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
@@ -0,0 +1,466 @@
+package operated
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/consts"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+)
+
+// PathStep 経路探索の1回の移動
+type PathStep struct {
+	From     string  // 移動元の拡張空間ID
+	To       string  // 移動先の拡張空間ID
+	Distance float64 // 拡張空間IDの中心間の距離(単位:m)
+	Climb    float64 // 高さ方向の移動量(単位:m、上昇が正)
+}
+
+// PathCostFunc 経路探索の移動コストの関数型
+//
+// 最短経路を得るためには、移動コストが中心間の距離以上であること。
+//
+// 引数：
+//
+//	step：移動
+//
+// 戻り値：
+//
+//	移動コスト(>= 0)
+type PathCostFunc func(step PathStep) float64
+
+// PathOptions 経路探索のオプション
+//
+// 高度の制限を設けない場合は MinAltitude に math.Inf(-1)、MaxAltitude に math.Inf(1) を指定する。
+type PathOptions struct {
+	MinAltitude float64      // 最低高度(単位:m)。経路の拡張空間IDの下端はこの値以上となる。
+	MaxAltitude float64      // 最高高度(単位:m)。経路の拡張空間IDの上端はこの値以下となる。
+	MaxNodes    int          // 展開する拡張空間IDの上限数(>= 0)。0の場合は上限なし。
+	Cost        PathCostFunc // 移動コストの関数。nilの場合は中心間の距離とする。
+}
+
+// pathNode 経路探索の拡張空間ID
+type pathNode struct {
+	index  shiftingIndex // 拡張空間IDの成分
+	cost   float64       // 開始位置からの移動コスト
+	parent *pathNode     // 経路上の1つ前の拡張空間ID
+	closed bool          // 展開済みの場合true
+}
+
+// pathEntry 経路探索の優先度付きキューの要素
+type pathEntry struct {
+	node     *pathNode // 拡張空間ID
+	cost     float64   // 追加時の開始位置からの移動コスト
+	priority float64   // 移動コストと目標位置までの推定距離の和
+	sequence int       // 追加順
+}
+
+// pathQueue 経路探索の優先度付きキュー
+type pathQueue []pathEntry
+
+// spatialIDOverlapSet 精度の異なる拡張空間IDとの重なり判定用の集合
+//
+// 拡張空間IDを判定対象の精度と比べて粗い方の精度に変換し、精度の組ごとに保持する。
+type spatialIDOverlapSet map[[2]int64]map[shiftingIndex]struct{}
+
+// NewPathCostFunc 移動コストの関数の作成関数
+//
+// 中心間の距離に、上昇量に比例するコストと移動先の拡張空間IDのコストを加算する関数を作成する。
+//
+// 引数：
+//
+//	climbPenalty：上昇1mあたりの追加コスト(>= 0)
+//	voxelCost   ：移動先の拡張空間IDの追加コストを返却する関数(戻り値 >= 0)。nilの場合は0とする。
+//
+// 戻り値：
+//
+//	移動コストの関数
+func NewPathCostFunc(climbPenalty float64, voxelCost func(spatialID string) float64) PathCostFunc {
+	return func(step PathStep) float64 {
+		cost := step.Distance + climbPenalty*max(step.Climb, 0)
+		if voxelCost != nil {
+			cost += voxelCost(step.To)
+		}
+		return cost
+	}
+}
+
+// FindPathOnExtendedSpatialIds 拡張空間IDの経路探索関数
+//
+// 開始位置から目標位置までの、障害物と重ならない26近傍で連結した拡張空間IDの経路をA*探索で取得する。
+//
+// 目標位置までの推定距離には、中心間の大円距離に係数 exp(-2π/2^水平方向精度) を乗じた水平方向の距離と、
+// 高さ方向の距離の合成を使用する。隣接する拡張空間IDの間の移動の距離は、水平方向の大きさに小さい方を使用するため
+// 大円距離より最大でこの係数の割合だけ小さくなる。係数を乗じることで推定距離は最短経路の移動コストを超えない。
+// 拡張空間IDの水平方向の大きさは赤道半径の球で近似し、緯度に応じて変化させる。
+// 経度方向は範囲を超えた場合に周回し、緯度方向の範囲外(極を越える範囲)には移動しない。
+//
+// 展開する拡張空間IDごとに ctx のキャンセルを確認する。
+//
+// 引数：
+//
+//	ctx        ：コンテキスト
+//	startID    ：開始位置の拡張空間ID
+//	goalID     ：目標位置の拡張空間ID。開始位置と同じ精度であること。
+//	obstacleIDs：障害物の拡張空間IDのスライス。精度が混在していてもよい。
+//	options    ：経路探索のオプション
+//
+// 戻り値：
+//
+//	開始位置から目標位置までの拡張空間IDのスライス(開始位置、目標位置を含む)
+//	経路の移動コストの合計
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのy成分が精度の範囲外の場合。
+//	 入力値不正                ：開始位置と目標位置の精度が異なる場合、開始位置、目標位置が高度の制限外または障害物と重なる場合、
+//	                            オプションが不正な場合、移動コストが不正な値の場合。
+//	 上限超過                  ：展開した拡張空間IDが MaxNodes を超えた場合(OtherErrorCode)。
+//	 経路なし                  ：目標位置に到達できない場合(OtherErrorCode)。
+//	 キャンセル                ：ctx がキャンセルされた場合、ctx.Err() が返却される。
+func FindPathOnExtendedSpatialIds(ctx context.Context, startID, goalID string, obstacleIDs []string, options PathOptions) ([]string, float64, error) {
+	start, err := newShiftingIndex(startID, enum.DropBoundary)
+	if err != nil {
+		return nil, 0, err
+	}
+	goal, err := newShiftingIndex(goalID, enum.DropBoundary)
+	if err != nil {
+		return nil, 0, err
+	}
+	if start.hZoom != goal.hZoom || start.vZoom != goal.vZoom {
+		return nil, 0, errors.NewSpatialIdError(errors.InputValueErrorCode, "start and goal zoom must be the same")
+	}
+	if options.MaxNodes < 0 {
+		return nil, 0, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("max nodes is out of range: %v", options.MaxNodes))
+	}
+	if !(options.MinAltitude <= options.MaxAltitude) {
+		return nil, 0, errors.NewSpatialIdError(errors.InputValueErrorCode, "min altitude is greater than max altitude")
+	}
+
+	obstacles, err := newSpatialIDOverlapSet(obstacleIDs, start.hZoom, start.vZoom)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, index := range []shiftingIndex{start, goal} {
+		if !options.isInAltitude(index) {
+			return nil, 0, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("out of altitude limits: %v", index))
+		}
+		if obstacles.overlaps(index) {
+			return nil, 0, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("overlaps obstacles: %v", index))
+		}
+	}
+
+	cost := options.Cost
+	if cost == nil {
+		cost = func(step PathStep) float64 {
+			return step.Distance
+		}
+	}
+
+	offsets := getNeighborOffsets()
+	nodes := map[shiftingIndex]*pathNode{start: {index: start}}
+	queue := &pathQueue{}
+	heap.Push(queue, pathEntry{node: nodes[start], priority: start.lowerBoundTo(goal)})
+	expanded, sequence := 0, 0
+	for queue.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+
+		entry := heap.Pop(queue).(pathEntry)
+		node := entry.node
+		if node.closed || entry.cost != node.cost {
+			continue
+		}
+		node.closed = true
+		if node.index == goal {
+			return node.path(), node.cost, nil
+		}
+
+		if options.MaxNodes > 0 && expanded >= options.MaxNodes {
+			return nil, 0, errors.NewSpatialIdError(errors.OtherErrorCode, fmt.Sprintf("node budget exceeded: %v", options.MaxNodes))
+		}
+		expanded++
+
+		from := node.index.String()
+		for _, offset := range offsets {
+			next, ok := node.index.shiftWithPolicy(offset[0], offset[1], offset[2], enum.DropBoundary)
+			if !ok || !options.isInAltitude(next) || obstacles.overlaps(next) {
+				continue
+			}
+			if nextNode, ok := nodes[next]; ok && nextNode.closed {
+				continue
+			}
+
+			stepCost := cost(PathStep{
+				From:     from,
+				To:       next.String(),
+				Distance: node.index.distanceTo(next),
+				Climb:    float64(offset[2]) * next.verticalSize(),
+			})
+			if !(stepCost >= 0) || math.IsInf(stepCost, 1) {
+				return nil, 0, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("path cost is out of range: %v", stepCost))
+			}
+
+			nextCost := node.cost + stepCost
+			nextNode, ok := nodes[next]
+			if !ok {
+				nextNode = &pathNode{index: next}
+				nodes[next] = nextNode
+			} else if nextNode.cost <= nextCost {
+				continue
+			}
+			nextNode.cost = nextCost
+			nextNode.parent = node
+			sequence++
+			heap.Push(queue, pathEntry{
+				node:     nextNode,
+				cost:     nextCost,
+				priority: nextCost + next.lowerBoundTo(goal),
+				sequence: sequence,
+			})
+		}
+	}
+
+	return nil, 0, errors.NewSpatialIdError(errors.OtherErrorCode, "path is not found")
+}
+
+// getNeighborOffsets 周囲26個の拡張空間IDへの移動量の取得
+//
+// 戻り値：
+//
+//	経度方向、緯度方向、高さ方向の移動量。中心(移動量0)を含まない。
+func getNeighborOffsets() [][3]int64 {
+	offsets := make([][3]int64, 0, 26)
+	for x := int64(-1); x <= 1; x++ {
+		for y := int64(-1); y <= 1; y++ {
+			for v := int64(-1); v <= 1; v++ {
+				if x != 0 || y != 0 || v != 0 {
+					offsets = append(offsets, [3]int64{x, y, v})
+				}
+			}
+		}
+	}
+	return offsets
+}
+
+// isInAltitude 高度の制限内判定
+//
+// 引数：
+//
+//	index：拡張空間IDの成分
+//
+// 戻り値：
+//
+//	拡張空間IDの高さ方向の範囲が高度の制限内の場合true
+func (o PathOptions) isInAltitude(index shiftingIndex) bool {
+	size := index.verticalSize()
+	return float64(index.z)*size >= o.MinAltitude && float64(index.z+1)*size <= o.MaxAltitude
+}
+
+// path 経路の取得
+//
+// 戻り値：
+//
+//	開始位置からの拡張空間IDのスライス
+func (n *pathNode) path() []string {
+	path := []string{}
+	for node := n; node != nil; node = node.parent {
+		path = append(path, node.index.String())
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// newSpatialIDOverlapSet 重なり判定用の集合の作成
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス
+//	hZoom     ：判定対象の水平方向精度
+//	vZoom     ：判定対象の垂直方向精度
+//
+// 戻り値：
+//
+//	重なり判定用の集合
+//
+// 戻り値(エラー)：
+//
+//	拡張空間IDが不正な場合エラーを返却する。
+func newSpatialIDOverlapSet(spatialIDs []string, hZoom, vZoom int64) (spatialIDOverlapSet, error) {
+	set := spatialIDOverlapSet{}
+	for _, spatialID := range spatialIDs {
+		index, err := newShiftingIndex(spatialID, enum.DropBoundary)
+		if err != nil {
+			return nil, err
+		}
+
+		index = index.coarsen(min(index.hZoom, hZoom), min(index.vZoom, vZoom))
+		zoom := [2]int64{index.hZoom, index.vZoom}
+		if _, ok := set[zoom]; !ok {
+			set[zoom] = map[shiftingIndex]struct{}{}
+		}
+		set[zoom][index] = struct{}{}
+	}
+	return set, nil
+}
+
+// overlaps 重なり判定
+//
+// 引数：
+//
+//	index：判定対象の拡張空間IDの成分
+//
+// 戻り値：
+//
+//	集合のいずれかの拡張空間IDと重なる場合true
+func (s spatialIDOverlapSet) overlaps(index shiftingIndex) bool {
+	for zoom, indexes := range s {
+		if _, ok := indexes[index.coarsen(zoom[0], zoom[1])]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// coarsen 粗い精度の拡張空間IDの成分への変換
+//
+// 引数：
+//
+//	hZoom：変換後の水平方向精度(元の精度以下)
+//	vZoom：変換後の垂直方向精度(元の精度以下)
+//
+// 戻り値：
+//
+//	元の拡張空間IDを包含する拡張空間IDの成分
+func (s shiftingIndex) coarsen(hZoom, vZoom int64) shiftingIndex {
+	return shiftingIndex{
+		hZoom: hZoom,
+		x:     s.x >> (s.hZoom - hZoom),
+		y:     s.y >> (s.hZoom - hZoom),
+		vZoom: vZoom,
+		z:     s.z >> (s.vZoom - vZoom),
+	}
+}
+
+// horizontalSize 拡張空間IDの水平方向の大きさの取得
+//
+// 赤道半径の球で近似し、メルカトル図法の等角性から東西と南北を同じ大きさとする。
+//
+// 戻り値：
+//
+//	拡張空間IDの中心の緯度における水平方向の大きさ(単位:m)
+func (s shiftingIndex) horizontalSize() float64 {
+	limit := math.Ldexp(1, int(s.hZoom))
+	lat := math.Atan(math.Sinh(math.Pi * (1 - 2*(float64(s.y)+0.5)/limit)))
+	return 2 * math.Pi * earthEquatorialRadius * math.Cos(lat) / limit
+}
+
+// verticalSize 拡張空間IDの垂直方向の大きさの取得
+//
+// 戻り値：
+//
+//	拡張空間IDの垂直方向の大きさ(単位:m)
+func (s shiftingIndex) verticalSize() float64 {
+	return math.Ldexp(1, consts.ZOriginValue-int(s.vZoom))
+}
+
+// distanceTo 同じ精度の拡張空間IDの中心間の距離の取得
+//
+// 水平方向の大きさには2つの拡張空間IDのうち小さい方を使用する。
+// 経度方向は周回を考慮した近い方の差とする。
+//
+// 引数：
+//
+//	other：拡張空間IDの成分
+//
+// 戻り値：
+//
+//	中心間の距離(単位:m)
+func (s shiftingIndex) distanceTo(other shiftingIndex) float64 {
+	limit := int64(1) << s.hZoom
+	dx := ((other.x-s.x)%limit + limit) % limit
+	dx = min(dx, limit-dx)
+
+	hSize := min(s.horizontalSize(), other.horizontalSize())
+	return math.Sqrt(
+		math.Pow(float64(dx)*hSize, 2) +
+			math.Pow(float64(other.y-s.y)*hSize, 2) +
+			math.Pow(float64(other.z-s.z)*s.verticalSize(), 2),
+	)
+}
+
+// lowerBoundTo 同じ精度の拡張空間IDまでの移動の距離の下限の取得
+//
+// メルカトル図法は等角であり、緯度方向の位置による水平方向の大きさの比は隣接する拡張空間IDの間で
+// exp(-2π/2^水平方向精度) 以上となる。そのため、distanceTo による移動の距離の和は、
+// 中心間の大円距離にこの係数を乗じた値と高さ方向の距離の合成以上となる。
+//
+// 引数：
+//
+//	other：拡張空間IDの成分
+//
+// 戻り値：
+//
+//	中心間の移動の距離の下限(単位:m)
+func (s shiftingIndex) lowerBoundTo(other shiftingIndex) float64 {
+	limit := math.Ldexp(1, int(s.hZoom))
+	getLonLat := func(index shiftingIndex) (float64, float64) {
+		lon := 2 * math.Pi * (float64(index.x) + 0.5) / limit
+		lat := math.Atan(math.Sinh(math.Pi * (1 - 2*(float64(index.y)+0.5)/limit)))
+		return lon, lat
+	}
+	lon1, lat1 := getLonLat(s)
+	lon2, lat2 := getLonLat(other)
+
+	// ハバーサインの公式による大円距離
+	haversine := math.Pow(math.Sin((lat2-lat1)/2), 2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin((lon2-lon1)/2), 2)
+	hDistance := 2 * earthEquatorialRadius * math.Asin(math.Sqrt(min(haversine, 1)))
+
+	return math.Hypot(
+		hDistance*math.Exp(-2*math.Pi/limit),
+		float64(other.z-s.z)*s.verticalSize(),
+	)
+}
+
+// Len heap.Interface の実装
+func (q pathQueue) Len() int {
+	return len(q)
+}
+
+// Less heap.Interface の実装
+//
+// 優先度が同じ場合は先に追加された要素を優先する。
+func (q pathQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].sequence < q[j].sequence
+}
+
+// Swap heap.Interface の実装
+func (q pathQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Push heap.Interface の実装
+func (q *pathQueue) Push(x any) {
+	*q = append(*q, x.(pathEntry))
+}
+
+// Pop heap.Interface の実装
+func (q *pathQueue) Pop() any {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
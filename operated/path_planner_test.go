@@ -0,0 +1,253 @@
+package operated
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
+)
+
+// unlimitedPathOptions 試験用の高度の制限のない経路探索のオプション
+var unlimitedPathOptions = PathOptions{MinAltitude: math.Inf(-1), MaxAltitude: math.Inf(1)}
+
+// TestFindPathOnExtendedSpatialIds01 拡張空間IDの経路探索関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：障害物なし、"20/524288/524287/25/0"から経度方向に3個先まで
+//   - パターン2：パターン1の間に垂直方向精度24の障害物"20/524289/524287/24/0"
+//   - パターン3：パターン2に加えて高度の制限0～1m
+//
+// + 確認内容
+//   - パターン1：経度方向に直進する4個の拡張空間IDが返却されること
+//   - パターン2、3：障害物と重なる拡張空間IDを避けて4個の拡張空間IDで到達すること
+//   - パターン3：経路が高度の制限内であること
+func TestFindPathOnExtendedSpatialIds01(t *testing.T) {
+	start, goal := "20/524288/524287/25/0", "20/524291/524287/25/0"
+
+	resultVal, resultCost, resultErr := FindPathOnExtendedSpatialIds(context.Background(), start, goal, nil, unlimitedPathOptions)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	expectVal := []string{start, "20/524289/524287/25/0", "20/524290/524287/25/0", goal}
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("経路 - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+	size := shiftingIndex{hZoom: 20, y: 524287}.horizontalSize()
+	if math.Abs(resultCost-3*size) > 1e-6 {
+		t.Errorf("移動コスト - 期待値：%v, 取得値：%v", 3*size, resultCost)
+	}
+
+	obstacles := []string{"20/524289/524287/24/0"}
+	limited := PathOptions{MinAltitude: 0, MaxAltitude: 1}
+	for _, options := range []PathOptions{unlimitedPathOptions, limited} {
+		resultVal, _, resultErr = FindPathOnExtendedSpatialIds(context.Background(), start, goal, obstacles, options)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if len(resultVal) != 4 || resultVal[0] != start || resultVal[3] != goal {
+			t.Errorf("経路 - 取得値：%v", resultVal)
+		}
+		for _, id := range []string{"20/524289/524287/25/0", "20/524289/524287/25/1"} {
+			if contains(resultVal, id) {
+				t.Errorf("経路 - 障害物と重なる拡張空間IDが返却された：%v", resultVal)
+			}
+		}
+		if options.MaxAltitude == limited.MaxAltitude {
+			for _, id := range resultVal {
+				index, _ := newShiftingIndex(id, enum.DropBoundary)
+				if index.z != 0 {
+					t.Errorf("経路 - 高度の制限外の拡張空間IDが返却された：%v", resultVal)
+				}
+			}
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestFindPathOnExtendedSpatialIds02 拡張空間IDの経路探索関数 移動コストの確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1："20/524288/524287/25/0"から"20/524290/524287/25/0"まで、
+//     中間の"20/524289/524287/25/0"の追加コストが100の移動コストの関数
+//
+// + 確認内容
+//   - 追加コストのある拡張空間IDを避けた3個の拡張空間IDが返却され、移動コストが直進より大きいこと
+func TestFindPathOnExtendedSpatialIds02(t *testing.T) {
+	start, goal := "20/524288/524287/25/0", "20/524290/524287/25/0"
+	options := unlimitedPathOptions
+	options.Cost = NewPathCostFunc(1, func(spatialID string) float64 {
+		if spatialID == "20/524289/524287/25/0" {
+			return 100
+		}
+		return 0
+	})
+
+	resultVal, resultCost, resultErr := FindPathOnExtendedSpatialIds(context.Background(), start, goal, nil, options)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if len(resultVal) != 3 || contains(resultVal, "20/524289/524287/25/0") {
+		t.Errorf("経路 - 取得値：%v", resultVal)
+	}
+	size := shiftingIndex{hZoom: 20, y: 524287}.horizontalSize()
+	if !(resultCost > 2*size) || !(resultCost < 2*size+100) {
+		t.Errorf("移動コスト - 取得値：%v", resultCost)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestFindPathOnExtendedSpatialIds03 拡張空間IDの経路探索関数 異常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：展開する拡張空間IDの上限1で遠い目標位置
+//   - パターン2：キャンセル済みのコンテキスト
+//   - パターン3：高度の制限0～1mで目標位置"2/2/2/25/0"の水平方向の周囲が障害物
+//   - パターン4：開始位置と目標位置の精度が異なる
+//   - パターン5：開始位置が障害物と重なる
+//   - パターン6：開始位置が高度の制限外
+//
+// + 確認内容
+//   - パターン1、3：エラーインスタンス（OtherErrorCode）が返却されること
+//   - パターン2：context.Canceled が返却されること
+//   - パターン4～6：エラーインスタンス（InputValueErrorCode）が返却されること
+func TestFindPathOnExtendedSpatialIds03(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	limited := PathOptions{MinAltitude: 0, MaxAltitude: 1}
+
+	testCases := []struct {
+		ctx       context.Context
+		start     string
+		goal      string
+		obstacles []string
+		options   PathOptions
+		expectErr string
+	}{
+		{
+			context.Background(), "20/524288/524287/25/0", "20/524298/524287/25/0", nil,
+			PathOptions{MinAltitude: math.Inf(-1), MaxAltitude: math.Inf(1), MaxNodes: 1},
+			"OtherError,その他例外が発生,node budget exceeded: 1",
+		},
+		{
+			canceled, "20/524288/524287/25/0", "20/524298/524287/25/0", nil, unlimitedPathOptions,
+			context.Canceled.Error(),
+		},
+		{
+			context.Background(), "2/0/0/25/0", "2/2/2/25/0", Get8spatialIdsAroundHorizontal("2/2/2/25/0"), limited,
+			"OtherError,その他例外が発生,path is not found",
+		},
+		{
+			context.Background(), "20/524288/524287/25/0", "20/524298/524287/24/0", nil, unlimitedPathOptions,
+			"InputValueError,入力チェックエラー,start and goal zoom must be the same",
+		},
+		{
+			context.Background(), "20/524288/524287/25/0", "20/524298/524287/25/0", []string{"10/512/511/20/0"}, unlimitedPathOptions,
+			"InputValueError,入力チェックエラー,overlaps obstacles: 20/524288/524287/25/0",
+		},
+		{
+			context.Background(), "20/524288/524287/25/1", "20/524298/524287/25/0", nil, limited,
+			"InputValueError,入力チェックエラー,out of altitude limits: 20/524288/524287/25/1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		_, _, resultErr := FindPathOnExtendedSpatialIds(testCase.ctx, testCase.start, testCase.goal, testCase.obstacles, testCase.options)
+		if resultErr == nil || resultErr.Error() != testCase.expectErr {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expectErr, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestFindPathOnExtendedSpatialIds04 拡張空間IDの経路探索関数 高緯度の最短経路の確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 高度の制限0～131072m(高さ成分0のみ)で以下の開始位置、目標位置
+//   - パターン1："8/0/60/8/0"から経度方向に120個先の"8/120/60/8/0"まで
+//   - パターン2："8/10/20/8/0"から"8/200/30/8/0"まで
+//   - パターン3："6/0/5/8/0"から赤道を越えた"6/40/60/8/0"まで
+//
+// + 確認内容
+//   - 移動コストが同じ移動の距離によるダイクストラ法の最短経路の移動コストと一致すること
+//   - 目標位置までの推定距離がダイクストラ法の最短経路の移動コストを超えないこと
+func TestFindPathOnExtendedSpatialIds04(t *testing.T) {
+	options := PathOptions{MinAltitude: 0, MaxAltitude: 1 << 17}
+	testCases := []struct {
+		start string
+		goal  string
+	}{
+		{"8/0/60/8/0", "8/120/60/8/0"},
+		{"8/10/20/8/0", "8/200/30/8/0"},
+		{"6/0/5/8/0", "6/40/60/8/0"},
+	}
+
+	for _, testCase := range testCases {
+		_, resultCost, resultErr := FindPathOnExtendedSpatialIds(context.Background(), testCase.start, testCase.goal, nil, options)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+
+		start, _ := newShiftingIndex(testCase.start, enum.DropBoundary)
+		goal, _ := newShiftingIndex(testCase.goal, enum.DropBoundary)
+		expectCost := findHorizontalPathCost(start, goal)
+		if math.Abs(resultCost-expectCost) > 1e-6 {
+			t.Errorf("移動コスト %v - 期待値：%v, 取得値：%v", testCase.start, expectCost, resultCost)
+		}
+		if lowerBound := start.lowerBoundTo(goal); lowerBound > expectCost {
+			t.Errorf("推定距離 %v - 最短経路の移動コスト：%v, 取得値：%v", testCase.start, expectCost, lowerBound)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// findHorizontalPathCost 試験用の水平方向の移動のみのダイクストラ法による最短経路の移動コストの取得
+//
+// 引数：
+//
+//	start：開始位置の拡張空間IDの成分
+//	goal ：目標位置の拡張空間IDの成分。開始位置と同じ精度、高さ成分であること。
+//
+// 戻り値：
+//
+//	中心間の距離の和による最短経路の移動コスト
+func findHorizontalPathCost(start, goal shiftingIndex) float64 {
+	costs := map[shiftingIndex]float64{start: 0}
+	closed := map[shiftingIndex]bool{}
+	queue := &pathQueue{{node: &pathNode{index: start}}}
+	for queue.Len() > 0 {
+		entry := heap.Pop(queue).(pathEntry)
+		index := entry.node.index
+		if closed[index] {
+			continue
+		}
+		closed[index] = true
+		if index == goal {
+			return entry.cost
+		}
+
+		for _, offset := range getNeighborOffsets() {
+			next, ok := index.shiftWithPolicy(offset[0], offset[1], 0, enum.DropBoundary)
+			if !ok || offset[2] != 0 {
+				continue
+			}
+			nextCost := entry.cost + index.distanceTo(next)
+			if cost, ok := costs[next]; ok && cost <= nextCost {
+				continue
+			}
+			costs[next] = nextCost
+			heap.Push(queue, pathEntry{node: &pathNode{index: next}, cost: nextCost, priority: nextCost})
+		}
+	}
+	return math.Inf(1)
+}
@@ -16,6 +16,8 @@ import (
 //
 // 拡張空間IDの面に直接、接している6個の拡張空間IDを取得する。
 //
+// 経度方向、緯度方向ともに範囲を超えた場合は周回するため、極を越える拡張空間IDは反対側の極の拡張空間IDとなる。
+//
 // 引数：
 //
 //	spatialID： 元の位置となる拡張空間ID
@@ -23,6 +25,8 @@ import (
 // 戻り値：
 //
 //	拡張空間IDスライス： []string
+//
+// Deprecated: 緯度方向の範囲外の扱いを指定でき、不正な入力にエラーを返却する Get6spatialIdsAdjacentToFacesWithPolicy を使用すること。
 func Get6spatialIdsAdjacentToFaces(spatialID string) []string {
 	// 返却用リスト
 	spatialIDs := make([]string, 0, 6)
@@ -46,6 +50,8 @@ func Get6spatialIdsAdjacentToFaces(spatialID string) []string {
 //
 // 拡張空間IDの水平方向の周囲、一周分の8個の拡張空間IDを取得する。
 //
+// 経度方向、緯度方向ともに範囲を超えた場合は周回するため、極を越える拡張空間IDは反対側の極の拡張空間IDとなる。
+//
 // 引数：
 //
 //	spatialID： 元の位置となる拡張空間ID
@@ -53,6 +59,8 @@ func Get6spatialIdsAdjacentToFaces(spatialID string) []string {
 // 戻り値：
 //
 //	拡張空間IDスライス： []string
+//
+// Deprecated: 緯度方向の範囲外の扱いを指定でき、不正な入力にエラーを返却する Get8spatialIdsAroundHorizontalWithPolicy を使用すること。
 func Get8spatialIdsAroundHorizontal(spatialID string) []string {
 	// 返却用リスト
 	spatialIDs := make([]string, 0, 8)
@@ -79,6 +87,8 @@ func Get8spatialIdsAroundHorizontal(spatialID string) []string {
 //
 // 拡張空間IDを囲う26個の拡張空間IDを取得する。
 //
+// 経度方向、緯度方向ともに範囲を超えた場合は周回するため、極を越える拡張空間IDは反対側の極の拡張空間IDとなる。
+//
 // 引数：
 //
 //	spatialID： 元の位置となる拡張空間ID
@@ -86,6 +96,8 @@ func Get8spatialIdsAroundHorizontal(spatialID string) []string {
 // 戻り値：
 //
 //	拡張空間IDスライス： []string
+//
+// Deprecated: 緯度方向の範囲外の扱いを指定でき、不正な入力にエラーを返却する Get26spatialIdsAroundVoxelWithPolicy を使用すること。
 func Get26spatialIdsAroundVoxel(spatialID string) []string {
 	// 返却用リスト
 	spatialIDs := make([]string, 0, 26)
@@ -117,6 +129,8 @@ func Get26spatialIdsAroundVoxel(spatialID string) []string {
 //
 // 拡張空間ID（一個以上）を囲う"N"個の拡張空間IDを取得する。
 //
+// 経度方向、緯度方向ともに範囲を超えた場合は周回するため、極を越える拡張空間IDは反対側の極の拡張空間IDとなる。
+//
 // 引数：
 //
 //	spatialIDs： 元の位置となる拡張空間IDs（スライス）
@@ -127,6 +141,8 @@ func Get26spatialIdsAroundVoxel(spatialID string) []string {
 //
 //	拡張空間IDスライス： []string
 //	 error: エラー
+//
+// Deprecated: 緯度方向の範囲外の扱いを指定でき、不正な入力にエラーを返却する GetNspatialIdsAroundVoxcelsWithPolicy を使用すること。
 func GetNspatialIdsAroundVoxcels(spatialIDs []string, hLayers, vLayers int64) ([]string, error) {
 
 	// invalid input validation (both parameters must be non-negative)
@@ -180,6 +196,9 @@ func GetNspatialIdsAroundVoxcels(spatialIDs []string, hLayers, vLayers int64) ([
 // 水平方向の移動は、南緯、東経方向が正、北緯、西経方向を負とする。
 // 垂直方向の移動は、上空方向が正、地中方向を負とする。
 //
+// 経度方向、緯度方向ともに範囲を超えた場合は周回するため、極を越える移動は反対側の極の拡張空間IDとなる。
+// 極での停止、範囲外の除外を行う場合は GetShiftingSpatialIDWithPolicy を使用すること。
+//
 // 引数：
 //
 //	spatialID： 元の位置となる拡張空間ID
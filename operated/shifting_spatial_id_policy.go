@@ -0,0 +1,331 @@
+package operated
+
+import (
+	"fmt"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/consts"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// GetShiftingSpatialIDWithPolicy 範囲外の扱い指定の拡張空間IDの移動関数
+//
+// 指定の数値分、移動した場合の拡張空間IDを取得する。
+// 移動方向は GetShiftingSpatialID と同じとする。垂直方向は上下限なしとする。
+//
+// 経度方向は、範囲外(東経180度、西経180度を越える移動)の場合は周回する。
+// 緯度方向の範囲外(極を越える移動)の扱いは以下となる。範囲外の扱いを指定しない(ゼロ値の)場合は ClampBoundary となる。
+//
+//	・ClampBoundary：範囲の端のインデックスで停止する。
+//	・DropBoundary ：移動先の拡張空間IDが存在しないものとしてエラーを返却する。
+//
+// 緯度方向も周回する GetShiftingSpatialID の動作は、範囲外の扱いとして指定できない。
+//
+// 引数：
+//
+//	spatialID： 元の位置となる拡張空間ID
+//	x： 拡張空間IDを経度方向に動かす数値
+//	y： 拡張空間IDを緯度方向に動かす数値
+//	v： 拡張空間IDを高さ方向に動かす数値
+//	policy： 範囲外の扱い
+//
+// 戻り値：
+//
+//	指定の数値分、移動した場合の拡張空間ID
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのy成分が精度の範囲外の場合。
+//	                             DropBoundary の場合に、移動先のy成分が精度の範囲外の場合。
+//	 範囲外の扱い不正          ：未対応の範囲外の扱いが入力されていた場合。
+func GetShiftingSpatialIDWithPolicy(spatialID string, x, y, v int64, policy enum.BoundaryPolicy) (string, error) {
+	source, err := newShiftingIndex(spatialID, policy)
+	if err != nil {
+		return "", err
+	}
+
+	shifted, ok := source.shiftWithPolicy(x, y, v, policy)
+	if !ok {
+		return "", errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("shifted index out of range: %v", spatialID))
+	}
+
+	return shifted.String(), nil
+}
+
+// Get6spatialIdsAdjacentToFacesWithPolicy 範囲外の扱い指定の拡張空間IDの面に接する拡張空間ID取得関数
+//
+// Get6spatialIdsAdjacentToFaces の範囲外の扱いを指定する。
+// 範囲外の扱いは GetShiftingSpatialIDWithPolicy のドキュメントを参照。
+//
+// 極に接する拡張空間IDの場合、ClampBoundary では移動先が元の拡張空間IDと同じとなり、
+// DropBoundary では移動先が範囲外となるため、いずれも返却されない。
+// そのため、返却される拡張空間IDは6個以下となる。
+// また、精度が低く周回により同じ拡張空間IDとなる場合は、重複を解消して返却する。
+//
+// 引数：
+//
+//	spatialID： 元の位置となる拡張空間ID
+//	policy： 範囲外の扱い
+//
+// 戻り値：
+//
+//	拡張空間IDスライス。Get6spatialIdsAdjacentToFaces と同じ順序で返却される。
+//
+// 戻り値(エラー)：
+//
+//	GetShiftingSpatialIDWithPolicy と同じ条件でエラーインスタンスが返却される。
+func Get6spatialIdsAdjacentToFacesWithPolicy(spatialID string, policy enum.BoundaryPolicy) ([]string, error) {
+	offsets := [][3]int64{}
+	for shiftIndex := int64(-1); shiftIndex < 2; shiftIndex += 2 {
+		offsets = append(offsets,
+			[3]int64{shiftIndex, 0, 0},
+			[3]int64{0, shiftIndex, 0},
+			[3]int64{0, 0, shiftIndex},
+		)
+	}
+
+	return getShiftedSpatialIds([]string{spatialID}, offsets, policy)
+}
+
+// Get8spatialIdsAroundHorizontalWithPolicy 範囲外の扱い指定の拡張空間IDの水平方向の一周分の拡張空間ID取得関数
+//
+// Get8spatialIdsAroundHorizontal の範囲外の扱いを指定する。
+// 範囲外の扱いは GetShiftingSpatialIDWithPolicy のドキュメントを参照。
+// 極に接する場合、周回により重複する場合の扱いは Get6spatialIdsAdjacentToFacesWithPolicy と同じとなる。
+//
+// 引数：
+//
+//	spatialID： 元の位置となる拡張空間ID
+//	policy： 範囲外の扱い
+//
+// 戻り値：
+//
+//	拡張空間IDスライス。Get8spatialIdsAroundHorizontal と同じ順序で返却される。
+//
+// 戻り値(エラー)：
+//
+//	GetShiftingSpatialIDWithPolicy と同じ条件でエラーインスタンスが返却される。
+func Get8spatialIdsAroundHorizontalWithPolicy(spatialID string, policy enum.BoundaryPolicy) ([]string, error) {
+	return getShiftedSpatialIds([]string{spatialID}, getHorizontalAroundOffsets(0), policy)
+}
+
+// Get26spatialIdsAroundVoxelWithPolicy 範囲外の扱い指定の拡張空間IDを囲う拡張空間ID取得関数
+//
+// Get26spatialIdsAroundVoxel の範囲外の扱いを指定する。
+// 範囲外の扱いは GetShiftingSpatialIDWithPolicy のドキュメントを参照。
+// 極に接する場合、周回により重複する場合の扱いは Get6spatialIdsAdjacentToFacesWithPolicy と同じとなる。
+//
+// 引数：
+//
+//	spatialID： 元の位置となる拡張空間ID
+//	policy： 範囲外の扱い
+//
+// 戻り値：
+//
+//	拡張空間IDスライス。Get26spatialIdsAroundVoxel と同じ順序で返却される。
+//
+// 戻り値(エラー)：
+//
+//	GetShiftingSpatialIDWithPolicy と同じ条件でエラーインスタンスが返却される。
+func Get26spatialIdsAroundVoxelWithPolicy(spatialID string, policy enum.BoundaryPolicy) ([]string, error) {
+	offsets := [][3]int64{}
+	for shiftIndex := int64(-1); shiftIndex < 2; shiftIndex++ {
+		if shiftIndex != 0 {
+			offsets = append(offsets, [3]int64{0, 0, shiftIndex})
+		}
+		offsets = append(offsets, getHorizontalAroundOffsets(shiftIndex)...)
+	}
+
+	return getShiftedSpatialIds([]string{spatialID}, offsets, policy)
+}
+
+// GetNspatialIdsAroundVoxcelsWithPolicy 範囲外の扱い指定の拡張空間ID（複数）を囲う拡張空間ID取得関数
+//
+// GetNspatialIdsAroundVoxcels の範囲外の扱いを指定する。
+// 範囲外の扱いは GetShiftingSpatialIDWithPolicy のドキュメントを参照。
+//
+// 入力された拡張空間IDごとに、移動先が元の拡張空間IDと同じとなる場合は返却しない。
+// 移動先の重複は解消して返却する。
+//
+// 引数：
+//
+//	spatialIDs： 元の位置となる拡張空間IDs（スライス）
+//	hLayers: 水平方向の層目（>= 0）
+//	vLayers: 垂直方向の層目（>= 0）
+//	policy： 範囲外の扱い
+//
+// 戻り値：
+//
+//	拡張空間IDスライス
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 入力値不正：層目に負の値が入力されていた場合。
+//	 その他    ：GetShiftingSpatialIDWithPolicy と同じ条件。
+func GetNspatialIdsAroundVoxcelsWithPolicy(spatialIDs []string, hLayers, vLayers int64, policy enum.BoundaryPolicy) ([]string, error) {
+	if hLayers < 0 || vLayers < 0 {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "both hLayers and vLayers parameters must be >= 0")
+	}
+
+	// GetNspatialIdsAroundVoxcels と同じ順序の移動量
+	offsets := [][3]int64{}
+	for x := -hLayers; x < hLayers+1; x++ {
+		for y := -hLayers; y < hLayers+1; y++ {
+			for v := -vLayers; v < vLayers+1; v++ {
+				if x == 0 && y == 0 && v == 0 {
+					continue
+				}
+				offsets = append(offsets, [3]int64{x, y, v})
+			}
+		}
+	}
+
+	return getShiftedSpatialIds(spatialIDs, offsets, policy)
+}
+
+// getHorizontalAroundOffsets 水平方向の一周分の移動量の取得
+//
+// 引数：
+//
+//	v：高さ方向の移動量
+//
+// 戻り値：
+//
+//	Get8spatialIdsAroundHorizontal と同じ順序の移動量
+func getHorizontalAroundOffsets(v int64) [][3]int64 {
+	offsets := make([][3]int64, 0, 8)
+	for shiftIndex := int64(-1); shiftIndex < 2; shiftIndex += 2 {
+		offsets = append(offsets,
+			[3]int64{shiftIndex, 0, v},
+			[3]int64{0, shiftIndex, v},
+			[3]int64{shiftIndex, shiftIndex, v},
+			[3]int64{shiftIndex, -shiftIndex, v},
+		)
+	}
+	return offsets
+}
+
+// getShiftedSpatialIds 拡張空間IDを移動量ごとに移動した拡張空間IDの取得
+//
+// 範囲外として除外されたもの、元の拡張空間IDと同じものは返却しない。重複は解消する。
+//
+// 引数：
+//
+//	spatialIDs：元の位置となる拡張空間ID
+//	offsets   ：経度方向、緯度方向、高さ方向の移動量
+//	policy    ：範囲外の扱い
+//
+// 戻り値：
+//
+//	移動後の拡張空間ID。移動量ごと、入力順に返却される。
+//
+// 戻り値(エラー)：
+//
+//	拡張空間ID、範囲外の扱いが不正な場合エラーを返却する。
+func getShiftedSpatialIds(spatialIDs []string, offsets [][3]int64, policy enum.BoundaryPolicy) ([]string, error) {
+	sources := make([]shiftingIndex, 0, len(spatialIDs))
+	for _, spatialID := range spatialIDs {
+		source, err := newShiftingIndex(spatialID, policy)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	shiftedIDs := []string{}
+	shiftedSet := map[shiftingIndex]struct{}{}
+	for _, offset := range offsets {
+		for _, source := range sources {
+			shifted, ok := source.shiftWithPolicy(offset[0], offset[1], offset[2], policy)
+			if !ok || shifted == source {
+				continue
+			}
+			if _, ok := shiftedSet[shifted]; ok {
+				continue
+			}
+			shiftedSet[shifted] = struct{}{}
+			shiftedIDs = append(shiftedIDs, shifted.String())
+		}
+	}
+
+	return shiftedIDs, nil
+}
+
+// newShiftingIndex 移動計算用の拡張空間IDの成分の作成
+//
+// 経度方向は範囲内に周回させる。緯度方向は範囲外の場合エラーとする。
+//
+// 引数：
+//
+//	spatialID：拡張空間ID
+//	policy   ：範囲外の扱い
+//
+// 戻り値：
+//
+//	拡張空間IDの成分
+//
+// 戻り値(エラー)：
+//
+//	拡張空間ID、範囲外の扱いが不正な場合エラーを返却する。
+func newShiftingIndex(spatialID string, policy enum.BoundaryPolicy) (shiftingIndex, error) {
+	switch policy {
+	case enum.ClampBoundary, enum.DropBoundary:
+	default:
+		return shiftingIndex{}, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("unsupported boundary policy: %v", policy))
+	}
+
+	extendedSpatialID, err := object.NewExtendedSpatialID(spatialID)
+	if err != nil {
+		return shiftingIndex{}, err
+	}
+	if extendedSpatialID.HZoom() < 0 || extendedSpatialID.HZoom() > consts.MaxTileXYZZoom ||
+		extendedSpatialID.VZoom() < 0 || extendedSpatialID.VZoom() > consts.MaxTileXYZZoom {
+		return shiftingIndex{}, errors.NewSpatialIdError(errors.InputValueErrorCode, "")
+	}
+
+	source := shiftingIndex{
+		hZoom: extendedSpatialID.HZoom(),
+		x:     extendedSpatialID.X(),
+		y:     extendedSpatialID.Y(),
+		vZoom: extendedSpatialID.VZoom(),
+		z:     extendedSpatialID.Z(),
+	}
+	if source.y < 0 || source.y >= int64(1)<<source.hZoom {
+		return shiftingIndex{}, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("index out of range: %v", spatialID))
+	}
+
+	return source.shift(0, 0, 0), nil
+}
+
+// shiftWithPolicy 範囲外の扱い指定の拡張空間IDの成分の移動
+//
+// 引数：
+//
+//	x： 経度方向に動かす数値
+//	y： 緯度方向に動かす数値
+//	v： 高さ方向に動かす数値
+//	policy： 範囲外の扱い
+//
+// 戻り値：
+//
+//	移動後の拡張空間IDの成分
+//	DropBoundary で緯度方向が範囲外となる場合false
+func (s shiftingIndex) shiftWithPolicy(x, y, v int64, policy enum.BoundaryPolicy) (shiftingIndex, bool) {
+	maxIndex := int64(1)<<s.hZoom - 1
+	shiftedY := s.y + y
+	if shiftedY < 0 || shiftedY > maxIndex {
+		if policy == enum.DropBoundary {
+			return shiftingIndex{}, false
+		}
+		shiftedY = min(max(shiftedY, 0), maxIndex)
+	}
+
+	shifted := s.shift(x, 0, v)
+	shifted.y = shiftedY
+	return shifted, true
+}
@@ -0,0 +1,227 @@
+package operated
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
+)
+
+// TestGetShiftingSpatialIDWithPolicy01 範囲外の扱い指定の拡張空間IDの移動関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 北端の拡張空間ID"3/7/0/20/3"を経度方向に1、緯度方向に-2、高さ方向に1移動
+//   - パターン1：範囲外の扱いの指定なし(ゼロ値)
+//   - パターン2：ClampBoundary
+//
+// + 確認内容
+//   - 経度方向は周回し、緯度方向は極を越えて周回せずに北端で停止した拡張空間IDが返却されること
+func TestGetShiftingSpatialIDWithPolicy01(t *testing.T) {
+	testCases := []struct {
+		policy    enum.BoundaryPolicy
+		expectVal string
+	}{
+		{enum.BoundaryPolicy(0), "3/0/0/20/4"},
+		{enum.ClampBoundary, "3/0/0/20/4"},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := GetShiftingSpatialIDWithPolicy("3/7/0/20/3", 1, -2, 1, testCase.policy)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if resultVal != testCase.expectVal {
+			t.Errorf("空間ID - 期待値：%s, 取得値：%s", testCase.expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetShiftingSpatialIDWithPolicy02 範囲外の扱い指定の拡張空間IDの移動関数 異常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：DropBoundaryで緯度方向の範囲外に移動
+//   - パターン2：拡張空間IDフォーマット不正
+//   - パターン3：ClampBoundaryで入力のy成分が範囲外
+//   - パターン4：未対応の範囲外の扱い
+//   - パターン5：精度閾値超過
+//
+// + 確認内容
+//   - 空文字ではなくエラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetShiftingSpatialIDWithPolicy02(t *testing.T) {
+	testCases := []struct {
+		spatialID string
+		policy    enum.BoundaryPolicy
+		expectErr string
+	}{
+		{"3/7/0/20/3", enum.DropBoundary, "InputValueError,入力チェックエラー,shifted index out of range: 3/7/0/20/3"},
+		{"3/7/0/20", enum.ClampBoundary, "InputValueError,入力チェックエラー"},
+		{"3/7/8/20/3", enum.ClampBoundary, "InputValueError,入力チェックエラー,index out of range: 3/7/8/20/3"},
+		{"3/7/0/20/3", enum.BoundaryPolicy(2), "InputValueError,入力チェックエラー,unsupported boundary policy: 2"},
+		{"36/7/0/20/3", enum.ClampBoundary, "InputValueError,入力チェックエラー"},
+	}
+
+	for _, testCase := range testCases {
+		_, resultErr := GetShiftingSpatialIDWithPolicy(testCase.spatialID, 0, -1, 0, testCase.policy)
+		if resultErr == nil || resultErr.Error() != testCase.expectErr {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expectErr, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGet6spatialIdsAdjacentToFacesWithPolicy01 範囲外の扱い指定の面に接する拡張空間ID取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：極に接しない"16/468/95/20/3"、ClampBoundary
+//   - パターン2：北端かつ東端の"3/7/0/20/3"、ClampBoundary
+//   - パターン3：北端かつ東端の"3/7/0/20/3"、DropBoundary
+//
+// + 確認内容
+//   - パターン1：Get6spatialIdsAdjacentToFaces と同じ拡張空間IDが返却されること
+//   - パターン2、3：経度方向は周回し、極を越える拡張空間IDが返却されないこと
+func TestGet6spatialIdsAdjacentToFacesWithPolicy01(t *testing.T) {
+	testCases := []struct {
+		spatialID string
+		policy    enum.BoundaryPolicy
+		expectVal []string
+	}{
+		{"16/468/95/20/3", enum.ClampBoundary, Get6spatialIdsAdjacentToFaces("16/468/95/20/3")},
+		{"3/7/0/20/3", enum.ClampBoundary, []string{"3/6/0/20/3", "3/7/0/20/2", "3/0/0/20/3", "3/7/1/20/3", "3/7/0/20/4"}},
+		{"3/7/0/20/3", enum.DropBoundary, []string{"3/6/0/20/3", "3/7/0/20/2", "3/0/0/20/3", "3/7/1/20/3", "3/7/0/20/4"}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := Get6spatialIdsAdjacentToFacesWithPolicy(testCase.spatialID, testCase.policy)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultVal, testCase.expectVal) {
+			t.Errorf("空間ID - 期待値：%v, 取得値：%v", testCase.expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGet8spatialIdsAroundHorizontalWithPolicy01 範囲外の扱い指定の水平方向の一周分の拡張空間ID取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：南端の"2/0/3/20/3"、ClampBoundary
+//   - パターン2：南端の"2/0/3/20/3"、DropBoundary
+//
+// + 確認内容
+//   - パターン1：極を越える移動は南端で停止し、重複と元の拡張空間IDが除かれること
+//   - パターン2：極を越える拡張空間IDが除外されること
+func TestGet8spatialIdsAroundHorizontalWithPolicy01(t *testing.T) {
+	testCases := []struct {
+		policy    enum.BoundaryPolicy
+		expectVal []string
+	}{
+		{enum.ClampBoundary, []string{"2/3/3/20/3", "2/0/2/20/3", "2/3/2/20/3", "2/1/3/20/3", "2/1/2/20/3"}},
+		{enum.DropBoundary, []string{"2/3/3/20/3", "2/0/2/20/3", "2/3/2/20/3", "2/1/3/20/3", "2/1/2/20/3"}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := Get8spatialIdsAroundHorizontalWithPolicy("2/0/3/20/3", testCase.policy)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultVal, testCase.expectVal) {
+			t.Errorf("空間ID - 期待値：%v, 取得値：%v", testCase.expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGet26spatialIdsAroundVoxelWithPolicy01 範囲外の扱い指定の拡張空間IDを囲う拡張空間ID取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：極に接しない"16/468/95/20/3"、ClampBoundary
+//   - パターン2：北端の"3/4/0/20/3"、DropBoundary
+//
+// + 確認内容
+//   - パターン1：Get26spatialIdsAroundVoxel と同じ拡張空間IDが返却されること
+//   - パターン2：極を越える9個を除いた17個の拡張空間IDが返却されること
+func TestGet26spatialIdsAroundVoxelWithPolicy01(t *testing.T) {
+	resultVal, resultErr := Get26spatialIdsAroundVoxelWithPolicy("16/468/95/20/3", enum.ClampBoundary)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	expectVal := Get26spatialIdsAroundVoxel("16/468/95/20/3")
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	resultVal, resultErr = Get26spatialIdsAroundVoxelWithPolicy("3/4/0/20/3", enum.DropBoundary)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if len(resultVal) != 17 {
+		t.Errorf("空間ID - 期待要素数：17, 取得要素数：%v", len(resultVal))
+	}
+	for _, id := range resultVal {
+		if contains([]string{"3/3/7/20/2", "3/4/7/20/3", "3/5/7/20/4"}, id) {
+			t.Errorf("空間ID - 極を越える拡張空間IDが返却された：%v", id)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetNspatialIdsAroundVoxcelsWithPolicy01 範囲外の扱い指定の拡張空間ID（複数）を囲う拡張空間ID取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：極に接しない{"10/10/10/10/10", "10/11/11/10/10"}、水平方向2層、垂直方向1層、ClampBoundary
+//   - パターン2：北端の{"10/10/0/10/10"}、水平方向2層、垂直方向0層、ClampBoundary
+//
+// + 確認内容
+//   - パターン1：GetNspatialIdsAroundVoxcels と同じ拡張空間IDの集合が返却されること
+//   - パターン2：y成分が0～2の範囲の、元の拡張空間IDを除いた14個の拡張空間IDが返却されること
+//   - 異常系：層目が負の場合、エラーインスタンスが返却されること
+func TestGetNspatialIdsAroundVoxcelsWithPolicy01(t *testing.T) {
+	ids := []string{"10/10/10/10/10", "10/11/11/10/10"}
+	resultVal, resultErr := GetNspatialIdsAroundVoxcelsWithPolicy(ids, 2, 1, enum.ClampBoundary)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	expectVal, _ := GetNspatialIdsAroundVoxcels(ids, 2, 1)
+	map1, map2 := map[string]struct{}{}, map[string]struct{}{}
+	for _, value := range expectVal {
+		map1[value] = struct{}{}
+	}
+	for _, value := range resultVal {
+		map2[value] = struct{}{}
+	}
+	if !reflect.DeepEqual(map1, map2) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	resultVal, resultErr = GetNspatialIdsAroundVoxcelsWithPolicy([]string{"10/10/0/10/10"}, 2, 0, enum.ClampBoundary)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if len(resultVal) != 14 {
+		t.Errorf("空間ID - 期待要素数：14, 取得要素数：%v", len(resultVal))
+	}
+	if contains(resultVal, "10/10/0/10/10") {
+		t.Errorf("空間ID - 元の拡張空間IDが返却された：%v", resultVal)
+	}
+
+	_, resultErr = GetNspatialIdsAroundVoxcelsWithPolicy(ids, -1, 1, enum.ClampBoundary)
+	expectErr := "InputValueError,入力チェックエラー,both hLayers and vLayers parameters must be >= 0"
+	if resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}
@@ -0,0 +1,161 @@
+package operated
+
+import (
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/consts"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// shiftingIndex 移動計算用の拡張空間IDの成分
+type shiftingIndex struct {
+	hZoom int64 // 水平方向精度
+	x     int64 // 経度ID
+	y     int64 // 緯度ID
+	vZoom int64 // 垂直方向精度
+	z     int64 // 高さID
+}
+
+// GetNspatialIdsAroundVoxcelsSeq 拡張空間ID（複数）を囲う"N"個の拡張空間IDのイテレータ取得関数
+//
+// GetNspatialIdsAroundVoxcels の返却値を、1つずつ生成して返却するイテレータを取得する。
+// 返却される拡張空間IDの集合は GetNspatialIdsAroundVoxcels と同じとなる。
+//
+// 重複の解消は、生成した拡張空間IDを保持せずに行う。
+// 生成した拡張空間IDが、先に走査した移動量で入力された拡張空間IDのいずれかから得られる場合は既に返却済みとして除外する。
+// そのため、使用するメモリ量は返却される拡張空間IDの個数ではなく、入力の個数に比例する。
+// 計算量は入力の個数 × 移動量の個数の2乗に比例する。
+//
+// 入力値の検証はイテレータの取得時に行う。
+//
+// 引数：
+//
+//	spatialIDs： 元の位置となる拡張空間IDs（スライス）
+//	hLayers: 水平方向の層目（>= 0）
+//	vLayers: 垂直方向の層目（>= 0）
+//
+// 戻り値：
+//
+//	拡張空間IDのイテレータ： iter.Seq[string]
+//	 error: エラー
+func GetNspatialIdsAroundVoxcelsSeq(spatialIDs []string, hLayers, vLayers int64) (iter.Seq[string], error) {
+	// invalid input validation (both parameters must be non-negative)
+	if hLayers < 0 || vLayers < 0 {
+		return nil, fmt.Errorf("both hLayers and vLayers parameters must be >= 0")
+	}
+
+	// 入力された拡張空間ID(移動範囲内に正規化し、重複を除去)
+	sources := make([]shiftingIndex, 0, len(spatialIDs))
+	sourceSet := map[shiftingIndex]struct{}{}
+	for _, spatialID := range spatialIDs {
+		extendedSpatialID, err := object.NewExtendedSpatialID(spatialID)
+		if err != nil {
+			return nil, err
+		}
+
+		source := shiftingIndex{
+			hZoom: extendedSpatialID.HZoom(),
+			x:     extendedSpatialID.X(),
+			y:     extendedSpatialID.Y(),
+			vZoom: extendedSpatialID.VZoom(),
+			z:     extendedSpatialID.Z(),
+		}.shift(0, 0, 0)
+		if _, ok := sourceSet[source]; ok {
+			continue
+		}
+		sourceSet[source] = struct{}{}
+		sources = append(sources, source)
+	}
+
+	// GetNspatialIdsAroundVoxcels と同じ順序の移動量
+	offsets := [][3]int64{}
+	for x := -hLayers; x < hLayers+1; x++ {
+		for y := -hLayers; y < hLayers+1; y++ {
+			for v := -vLayers; v < vLayers+1; v++ {
+				if x == 0 && y == 0 && v == 0 {
+					continue
+				}
+				offsets = append(offsets, [3]int64{x, y, v})
+			}
+		}
+	}
+
+	return func(yield func(string) bool) {
+		for k, offset := range offsets {
+			for _, source := range sources {
+				shifted := source.shift(offset[0], offset[1], offset[2])
+
+				// 先に走査した移動量で返却済みの場合は除外
+				if isShiftedBefore(shifted, offsets[:k], sourceSet) {
+					continue
+				}
+
+				if !yield(shifted.String()) {
+					return
+				}
+			}
+		}
+	}, nil
+}
+
+// isShiftedBefore 移動後の拡張空間IDの返却済み判定
+//
+// 引数：
+//
+//	shifted  ：移動後の拡張空間ID
+//	offsets  ：先に走査した移動量
+//	sourceSet：入力された拡張空間ID
+//
+// 戻り値：
+//
+//	いずれかの移動量で入力された拡張空間IDから得られる場合true
+func isShiftedBefore(shifted shiftingIndex, offsets [][3]int64, sourceSet map[shiftingIndex]struct{}) bool {
+	for _, offset := range offsets {
+		if _, ok := sourceSet[shifted.shift(-offset[0], -offset[1], -offset[2])]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// shift 拡張空間IDの成分の移動
+//
+// GetShiftingSpatialID と同様に、水平方向は範囲を超えた場合に周回させ、垂直方向は上下限なしとする。
+//
+// 引数：
+//
+//	x： 経度方向に動かす数値
+//	y： 緯度方向に動かす数値
+//	v： 高さ方向に動かす数値
+//
+// 戻り値：
+//
+//	移動後の拡張空間IDの成分
+func (s shiftingIndex) shift(x, y, v int64) shiftingIndex {
+	limit := int64(1) << s.hZoom
+
+	shifted := s
+	shifted.x = ((s.x+x)%limit + limit) % limit
+	shifted.y = ((s.y+y)%limit + limit) % limit
+	shifted.z = s.z + v
+
+	return shifted
+}
+
+// String 拡張空間IDの成分の文字列化
+//
+// 戻り値：
+//
+//	拡張空間ID
+func (s shiftingIndex) String() string {
+	return strings.Join([]string{
+		strconv.FormatInt(s.hZoom, 10),
+		strconv.FormatInt(s.x, 10),
+		strconv.FormatInt(s.y, 10),
+		strconv.FormatInt(s.vZoom, 10),
+		strconv.FormatInt(s.z, 10),
+	}, consts.SpatialIDDelimiter)
+}
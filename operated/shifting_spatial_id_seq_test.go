@@ -0,0 +1,110 @@
+package operated
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// TestGetNspatialIdsAroundVoxcelsSeq01 拡張空間ID（複数）を囲う"N"個の拡張空間IDのイテレータ取得関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：隣接する2個の拡張空間ID, hLayers=1, vLayers=1
+//   - パターン2：周回が発生する水平方向精度2の拡張空間ID(重複入力を含む), hLayers=2, vLayers=1
+//   - パターン3：精度の異なる拡張空間ID, hLayers=0, vLayers=2
+//
+// + 確認内容
+//   - GetNspatialIdsAroundVoxcels の返却値と同じ拡張空間IDが重複なく返却されること
+func TestGetNspatialIdsAroundVoxcelsSeq01(t *testing.T) {
+	testCases := []struct {
+		ids     []string
+		hLayers int64
+		vLayers int64
+	}{
+		{[]string{"10/10/10/10/10", "10/11/11/10/10"}, 1, 1},
+		{[]string{"2/0/0/2/0", "2/3/3/2/-1", "2/0/0/2/0"}, 2, 1},
+		{[]string{"10/10/10/10/10", "11/20/20/11/20", "10/10/10/10/12"}, 0, 2},
+	}
+
+	for _, testCase := range testCases {
+		expectVal, _ := GetNspatialIdsAroundVoxcels(testCase.ids, testCase.hLayers, testCase.vLayers)
+		sort.Strings(expectVal)
+
+		resultSeq, resultErr := GetNspatialIdsAroundVoxcelsSeq(testCase.ids, testCase.hLayers, testCase.vLayers)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		resultVal := slices.Collect(resultSeq)
+		sort.Strings(resultVal)
+
+		if !reflect.DeepEqual(resultVal, expectVal) {
+			t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetNspatialIdsAroundVoxcelsSeq02 拡張空間ID（複数）を囲う"N"個の拡張空間IDのイテレータ取得関数 正常系動作確認(走査の打ち切り)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：{"10/10/10/10/10"}, hLayers=1, vLayers=1 を2個目で打ち切る
+//
+// + 確認内容
+//   - 先頭の2個の拡張空間IDが返却されること
+func TestGetNspatialIdsAroundVoxcelsSeq02(t *testing.T) {
+	resultSeq, resultErr := GetNspatialIdsAroundVoxcelsSeq([]string{"10/10/10/10/10"}, 1, 1)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	resultVal := []string{}
+	for id := range resultSeq {
+		resultVal = append(resultVal, id)
+		if len(resultVal) == 2 {
+			break
+		}
+	}
+
+	expectVal := []string{"10/9/9/10/9", "10/9/9/10/10"}
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetNspatialIdsAroundVoxcelsSeq03 拡張空間ID（複数）を囲う"N"個の拡張空間IDのイテレータ取得関数 異常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：hLayersが負の値
+//   - パターン2：拡張空間IDフォーマット不正
+//
+// + 確認内容
+//   - nilとエラーが返却されること
+func TestGetNspatialIdsAroundVoxcelsSeq03(t *testing.T) {
+	testCases := []struct {
+		ids     []string
+		hLayers int64
+		expect  string
+	}{
+		{[]string{"10/10/10/10/10"}, -1, "both hLayers and vLayers parameters must be >= 0"},
+		{[]string{"10/10/10/10"}, 1, "InputValueError,入力チェックエラー"},
+	}
+
+	for _, testCase := range testCases {
+		resultSeq, resultErr := GetNspatialIdsAroundVoxcelsSeq(testCase.ids, testCase.hLayers, 1)
+		if resultSeq != nil {
+			t.Errorf("イテレータ - 期待値：nil, 取得値：%v", resultSeq)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
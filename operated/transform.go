@@ -0,0 +1,400 @@
+package operated
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/spatial"
+)
+
+// geodeticTransform 経度、緯度、高さの座標変換
+//
+// 引数：
+//
+//	lon：経度(単位:度)
+//	lat：緯度(単位:度)
+//	alt：高さ(単位:m)
+//
+// 戻り値：
+//
+//	変換後の経度、緯度、高さ
+type geodeticTransform func(lon, lat, alt float64) (float64, float64, float64)
+
+// TranslateSpatialIdsByMeters 拡張空間IDの集合の距離指定の平行移動関数
+//
+// 拡張空間IDの集合が表す空間を東西、南北、上下に移動し、同じ精度の拡張空間IDで再度表現する。
+// 各点は、その点の緯度における東西、南北の距離で地表面に沿って移動する。
+// 水平方向の距離は赤道半径の球で近似する。
+//
+// 移動後の拡張空間IDは、中心を元の位置に戻した点が元の拡張空間IDの集合に含まれるものとする。
+// そのため、拡張空間IDの大きさの整数倍でない移動でも、元の集合とおよそ同じ体積の集合が返却される。
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス。精度が混在していてもよい。
+//	east      ：東方向の移動距離(単位:m)。負の値は西方向。
+//	north     ：北方向の移動距離(単位:m)。負の値は南方向。
+//	up        ：上方向の移動距離(単位:m)。負の値は下方向。
+//
+// 戻り値：
+//
+//	移動後の拡張空間IDのスライス。
+//	入力と同じ精度の拡張空間IDが、水平方向精度、垂直方向精度、x成分、y成分、高さ成分の昇順で返却される。
+//	経度方向は範囲を超えた場合に周回し、緯度方向の範囲外(極を越える範囲)は除外する。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのy成分が精度の範囲外の場合。
+//	 入力値不正                ：移動距離に無限大、NaNが入力されていた場合。
+func TranslateSpatialIdsByMeters(spatialIDs []string, east, north, up float64) ([]string, error) {
+	if err := checkTransformParameters("offset", east, north, up); err != nil {
+		return nil, err
+	}
+
+	dLat := north / earthEquatorialRadius * 180 / math.Pi
+	forward := func(lon, lat, alt float64) (float64, float64, float64) {
+		return lon + east/(earthEquatorialRadius*math.Cos(lat*math.Pi/180))*180/math.Pi, lat + dLat, alt + up
+	}
+	inverse := func(lon, lat, alt float64) (float64, float64, float64) {
+		lat -= dLat
+		return lon - east/(earthEquatorialRadius*math.Cos(lat*math.Pi/180))*180/math.Pi, lat, alt - up
+	}
+	return transformSpatialIds(spatialIDs, forward, inverse)
+}
+
+// TranslateSpatialIdsByGeodeticDelta 拡張空間IDの集合の経度、緯度、高さの差分指定の平行移動関数
+//
+// 拡張空間IDの集合が表す空間を経度、緯度、高さの差分だけ移動し、同じ精度の拡張空間IDで再度表現する。
+// 移動後の拡張空間IDの選び方は TranslateSpatialIdsByMeters と同じとする。
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス。精度が混在していてもよい。
+//	lonDelta  ：経度の差分(単位:度)
+//	latDelta  ：緯度の差分(単位:度)
+//	altDelta  ：高さの差分(単位:m)
+//
+// 戻り値：
+//
+//	移動後の拡張空間IDのスライス。並び順は TranslateSpatialIdsByMeters と同じ。
+//
+// 戻り値(エラー)：
+//
+//	TranslateSpatialIdsByMeters と同じ条件でエラーインスタンスが返却される。
+func TranslateSpatialIdsByGeodeticDelta(spatialIDs []string, lonDelta, latDelta, altDelta float64) ([]string, error) {
+	if err := checkTransformParameters("offset", lonDelta, latDelta, altDelta); err != nil {
+		return nil, err
+	}
+
+	forward := func(lon, lat, alt float64) (float64, float64, float64) {
+		return lon + lonDelta, lat + latDelta, alt + altDelta
+	}
+	inverse := func(lon, lat, alt float64) (float64, float64, float64) {
+		return lon - lonDelta, lat - latDelta, alt - altDelta
+	}
+	return transformSpatialIds(spatialIDs, forward, inverse)
+}
+
+// RotateSpatialIds 拡張空間IDの集合の鉛直軸回りの回転関数
+//
+// 拡張空間IDの集合が表す空間を、中心の座標を通る鉛直軸回りに回転し、同じ精度の拡張空間IDで再度表現する。
+// 地球を赤道半径の球で近似し、中心の座標の鉛直方向(地心方向)を回転軸とする四元数で回転するため、
+// 回転による距離と高さは保存される。
+//
+// 拡張空間IDの添字を移動するのではなく、移動後の拡張空間IDのうち、中心を逆回転した点が
+// 元の拡張空間IDの集合に含まれるものを全て返却する。
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス。精度が混在していてもよい。
+//	center    ：回転の中心の座標。高さは使用しない。
+//	angle     ：回転角度(単位:度)。上から見て時計回り(方位角の増加する向き)を正とする。
+//
+// 戻り値：
+//
+//	回転後の拡張空間IDのスライス。並び順は TranslateSpatialIdsByMeters と同じ。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が入力されていた場合。
+//	 拡張空間ID範囲外          ：拡張空間IDのy成分が精度の範囲外の場合。
+//	 入力値不正                ：回転の中心がnilの場合、回転角度に無限大、NaNが入力されていた場合。
+func RotateSpatialIds(spatialIDs []string, center *object.Point, angle float64) ([]string, error) {
+	if center == nil {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "center is nil")
+	}
+	if err := checkTransformParameters("angle", angle); err != nil {
+		return nil, err
+	}
+
+	axis := getUnitVectorOnSphere(center.Lon(), center.Lat())
+	rotate := func(rotation spatial.Quat) geodeticTransform {
+		return func(lon, lat, alt float64) (float64, float64, float64) {
+			rotated := rotation.TransformVec3(getUnitVectorOnSphere(lon, lat))
+			return math.Atan2(rotated.Y, rotated.X) * 180 / math.Pi, math.Asin(max(-1, min(rotated.Z, 1))) * 180 / math.Pi, alt
+		}
+	}
+
+	// 右ねじの向きは上から見て反時計回りとなる
+	radian := angle * math.Pi / 180
+	forward := rotate(spatial.QuatFromAxisAngle(axis, -radian))
+	inverse := rotate(spatial.QuatFromAxisAngle(axis, radian))
+	return transformSpatialIds(spatialIDs, forward, inverse)
+}
+
+// checkTransformParameters 座標変換の引数の確認
+//
+// 引数：
+//
+//	name      ：エラーに含める引数の種類
+//	parameters：引数
+//
+// 戻り値(エラー)：
+//
+//	引数に無限大、NaNが含まれる場合エラーを返却する。
+func checkTransformParameters(name string, parameters ...float64) error {
+	for _, parameter := range parameters {
+		if math.IsNaN(parameter) || math.IsInf(parameter, 0) {
+			return errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("%v is out of range: %v", name, parameter))
+		}
+	}
+	return nil
+}
+
+// transformSpatialIds 拡張空間IDの集合の座標変換
+//
+// 精度ごとに、元の拡張空間IDの境界を変換した範囲に中心が含まれる拡張空間IDのうち、
+// 中心を逆変換した点が元の集合に含まれるものを返却する。
+//
+// 引数：
+//
+//	spatialIDs：拡張空間IDのスライス
+//	forward   ：座標変換
+//	inverse   ：forward の逆変換
+//
+// 戻り値：
+//
+//	変換後の拡張空間IDのスライス
+//
+// 戻り値(エラー)：
+//
+//	拡張空間IDが不正な場合エラーを返却する。
+func transformSpatialIds(spatialIDs []string, forward, inverse geodeticTransform) ([]string, error) {
+	grids, zooms, err := newSpatialIDGridsOnZoom(spatialIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	transformed := []string{}
+	for _, zoom := range zooms {
+		transformed = append(transformed, grids[zoom].transform(forward, inverse).spatialIDs()...)
+	}
+	return transformed, nil
+}
+
+// transform 拡張空間IDの集合の座標変換
+//
+// 引数：
+//
+//	forward：座標変換
+//	inverse：forward の逆変換
+//
+// 戻り値：
+//
+//	変換後の集合
+func (g spatialIDGrid) transform(forward, inverse geodeticTransform) spatialIDGrid {
+	transformed := spatialIDGrid{}
+	for index := range g {
+		limit := int64(1) << index.hZoom
+		lower, upper := index.getTransformedRange(forward)
+
+		// 中心が範囲に含まれる拡張空間ID。境界の間の点の曲がりに対して1個分の余裕を設ける。
+		first := [3]int64{}
+		last := [3]int64{}
+		for i := range first {
+			first[i] = int64(math.Ceil(lower[i]-0.5)) - 1
+			last[i] = int64(math.Floor(upper[i]-0.5)) + 1
+		}
+		first[1], last[1] = max(first[1], 0), min(last[1], limit-1)
+		last[0] = min(last[0], first[0]+limit-1)
+
+		for x := first[0]; x <= last[0]; x++ {
+			for y := first[1]; y <= last[1]; y++ {
+				for z := first[2]; z <= last[2]; z++ {
+					candidate := shiftingIndex{hZoom: index.hZoom, x: (x%limit + limit) % limit, y: y, vZoom: index.vZoom, z: z}
+					if _, ok := transformed[candidate]; ok {
+						continue
+					}
+					lon, lat, alt := inverse(candidate.pointAt(0.5, 0.5, 0.5))
+					source, ok := newShiftingIndexOnPoint(index.hZoom, index.vZoom, lon, lat, alt)
+					if !ok {
+						continue
+					}
+					if _, ok := g[source]; ok {
+						transformed[candidate] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+
+	return transformed
+}
+
+// getTransformedRange 座標変換後の拡張空間IDを包含する添字単位の範囲の取得
+//
+// 拡張空間IDの上端、下端の境界の点を変換した位置の範囲を求める。
+// 精度が粗いほど変換後の境界の曲がりが大きいため、境界の点を密にする。
+// 経度方向は境界に沿って連続した値とし、変換後の境界が極を囲む場合は経度方向の全てと極までを範囲とする。
+//
+// 引数：
+//
+//	forward：座標変換
+//
+// 戻り値：
+//
+//	x成分、y成分、高さ成分の方向の位置の下限
+//	x成分、y成分、高さ成分の方向の位置の上限
+func (s shiftingIndex) getTransformedRange(forward geodeticTransform) ([3]float64, [3]float64) {
+	limit := math.Ldexp(1, int(s.hZoom))
+	segments := int64(1) << max(1, 8-s.hZoom)
+
+	lower := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	upper := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	var baseLon float64
+	for loop, w := range []float64{0, 1} {
+		var startLon, previousLon, latSum float64
+		// 北西の頂点から時計回りに一周し、最後に北西の頂点に戻る
+		for k := int64(0); k <= 4*segments; k++ {
+			t := float64(k%segments) / float64(segments)
+			position := [4][2]float64{{t, 0}, {1, t}, {1 - t, 1}, {0, 1 - t}}[k/segments%4]
+			lon, lat, alt := forward(s.pointAt(position[0], position[1], w))
+			switch {
+			case loop == 0 && k == 0:
+				baseLon = lon
+			case k == 0:
+				lon = baseLon + math.Remainder(lon-baseLon, 360)
+			default:
+				lon = previousLon + math.Remainder(lon-previousLon, 360)
+			}
+			if k == 0 {
+				startLon = lon
+			}
+			previousLon = lon
+			latSum += lat
+
+			coordinate := getTileCoordinate(s.hZoom, s.vZoom, lon, lat, alt)
+			for i := range coordinate {
+				lower[i], upper[i] = math.Min(lower[i], coordinate[i]), math.Max(upper[i], coordinate[i])
+			}
+		}
+
+		// 一周して経度が戻らない場合は極を囲む
+		if math.Abs(previousLon-startLon) > 180 {
+			lower[0], upper[0] = math.Min(lower[0], 0), math.Max(upper[0], limit)
+			if latSum > 0 {
+				lower[1] = 0
+			} else {
+				upper[1] = limit
+			}
+		}
+	}
+	return lower, upper
+}
+
+// pointAt 拡張空間IDの内部の座標の取得
+//
+// 引数：
+//
+//	u：西端を0、東端を1とする経度方向の位置
+//	v：北端を0、南端を1とする緯度方向の位置
+//	w：下端を0、上端を1とする高さ方向の位置
+//
+// 戻り値：
+//
+//	経度(単位:度)、緯度(単位:度)、高さ(単位:m)
+func (s shiftingIndex) pointAt(u, v, w float64) (float64, float64, float64) {
+	limit := math.Ldexp(1, int(s.hZoom))
+	lon := (float64(s.x)+u)/limit*360 - 180
+	lat := math.Atan(math.Sinh(math.Pi*(1-2*(float64(s.y)+v)/limit))) * 180 / math.Pi
+	alt := (float64(s.z) + w) * s.verticalSize()
+	return lon, lat, alt
+}
+
+// getTileCoordinate 座標の拡張空間IDの添字単位の位置の取得
+//
+// 引数：
+//
+//	hZoom：水平方向精度
+//	vZoom：垂直方向精度
+//	lon  ：経度(単位:度)。経度方向の範囲外でも周回させない。
+//	lat  ：緯度(単位:度)。±90度を超える場合は±90度とする。
+//	alt  ：高さ(単位:m)
+//
+// 戻り値：
+//
+//	x成分、y成分、高さ成分の方向の位置
+func getTileCoordinate(hZoom, vZoom int64, lon, lat, alt float64) [3]float64 {
+	limit := math.Ldexp(1, int(hZoom))
+	lat = math.Max(-90, math.Min(lat, 90))
+	return [3]float64{
+		(lon + 180) / 360 * limit,
+		(1 - math.Asinh(math.Tan(lat*math.Pi/180))/math.Pi) / 2 * limit,
+		alt / shiftingIndex{vZoom: vZoom}.verticalSize(),
+	}
+}
+
+// newShiftingIndexOnPoint 座標を含む拡張空間IDの成分の取得
+//
+// 引数：
+//
+//	hZoom：水平方向精度
+//	vZoom：垂直方向精度
+//	lon  ：経度(単位:度)
+//	lat  ：緯度(単位:度)
+//	alt  ：高さ(単位:m)
+//
+// 戻り値：
+//
+//	拡張空間IDの成分
+//	緯度方向の範囲外の場合false
+func newShiftingIndexOnPoint(hZoom, vZoom int64, lon, lat, alt float64) (shiftingIndex, bool) {
+	limit := int64(1) << hZoom
+	if math.Abs(lat) > 90 {
+		return shiftingIndex{}, false
+	}
+	coordinate := getTileCoordinate(hZoom, vZoom, lon, lat, alt)
+	if !(coordinate[1] >= 0 && coordinate[1] < float64(limit)) {
+		return shiftingIndex{}, false
+	}
+	x := int64(math.Floor(coordinate[0]))
+	return shiftingIndex{
+		hZoom: hZoom,
+		x:     (x%limit + limit) % limit,
+		y:     int64(math.Floor(coordinate[1])),
+		vZoom: vZoom,
+		z:     int64(math.Floor(coordinate[2])),
+	}, true
+}
+
+// getUnitVectorOnSphere 球面上の座標の単位ベクトルの取得
+//
+// 引数：
+//
+//	lon：経度(単位:度)
+//	lat：緯度(単位:度)
+//
+// 戻り値：
+//
+//	球の中心から座標への単位ベクトル(x軸：経度0度、緯度0度、z軸：北極)
+func getUnitVectorOnSphere(lon, lat float64) spatial.Vector3 {
+	lon, lat = lon*math.Pi/180, lat*math.Pi/180
+	return spatial.Vector3{X: math.Cos(lat) * math.Cos(lon), Y: math.Cos(lat) * math.Sin(lon), Z: math.Sin(lat)}
+}
@@ -0,0 +1,243 @@
+package operated
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/spatial"
+)
+
+// TestTranslateSpatialIdsByGeodeticDelta01 拡張空間IDの集合の経度、緯度、高さの差分指定の平行移動関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1："20/524288/524287/25/0"、経度方向に拡張空間ID1個分、高さ方向に1m
+//   - パターン2："2/3/1/0/0"、経度方向に拡張空間ID1個分(東経180度を越える)
+//   - パターン3："20/524288/524287/25/0"、経度方向に拡張空間ID0.5個分と少し
+//
+// + 確認内容
+//   - パターン1："20/524289/524287/25/1"が返却されること
+//   - パターン2：経度方向に周回した"2/0/1/0/0"が返却されること
+//   - パターン3：中心が移動後の範囲に含まれる"20/524289/524287/25/0"が返却されること
+func TestTranslateSpatialIdsByGeodeticDelta01(t *testing.T) {
+	testCases := []struct {
+		spatialIDs []string
+		lonDelta   float64
+		altDelta   float64
+		expectVal  []string
+	}{
+		{[]string{"20/524288/524287/25/0"}, 360.0 / (1 << 20), 1, []string{"20/524289/524287/25/1"}},
+		{[]string{"2/3/1/0/0"}, 90, 0, []string{"2/0/1/0/0"}},
+		{[]string{"20/524288/524287/25/0"}, 360.0 / (1 << 20) * 0.51, 0, []string{"20/524289/524287/25/0"}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := TranslateSpatialIdsByGeodeticDelta(testCase.spatialIDs, testCase.lonDelta, 0, testCase.altDelta)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultVal, testCase.expectVal) {
+			t.Errorf("空間ID - 期待値：%v, 取得値：%v", testCase.expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestTranslateSpatialIdsByMeters01 拡張空間IDの集合の距離指定の平行移動関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 赤道直下の"20/524288/524287/25/0"と精度の異なる"10/512/511/20/0"
+//   - 東方向に拡張空間ID(精度20)2個分、南方向に拡張空間ID(精度20)1個分、上方向に2.25m
+//
+// + 確認内容
+//   - 精度ごとに移動し、水平方向精度の昇順で返却されること
+//   - 精度10の拡張空間IDは移動量が大きさに満たないため、移動しないこと
+func TestTranslateSpatialIdsByMeters01(t *testing.T) {
+	hSize := shiftingIndex{hZoom: 20, y: 524287}.horizontalSize()
+
+	resultVal, resultErr := TranslateSpatialIdsByMeters([]string{"20/524288/524287/25/0", "10/512/511/20/0"}, 2*hSize, -hSize, 2.25)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	expectVal := []string{"10/512/511/20/0", "20/524290/524288/25/2"}
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestRotateSpatialIds01 拡張空間IDの集合の鉛直軸回りの回転関数 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 東西に並んだ"20/524288/524287/25/0"、"20/524289/524287/25/0"を、西側の中心を通る鉛直軸回りに回転
+//   - パターン1：90度
+//   - パターン2：180度
+//   - パターン3：-90度
+//
+// + 確認内容
+//   - 東側の拡張空間IDが時計回りに、南側、西側、北側に移動すること
+func TestRotateSpatialIds01(t *testing.T) {
+	spatialIDs := []string{"20/524288/524287/25/0", "20/524289/524287/25/0"}
+	center, _ := object.NewPoint(shiftingIndex{hZoom: 20, x: 524288, y: 524287, vZoom: 25}.pointAt(0.5, 0.5, 0))
+
+	testCases := []struct {
+		angle     float64
+		expectVal []string
+	}{
+		{90, []string{"20/524288/524287/25/0", "20/524288/524288/25/0"}},
+		{180, []string{"20/524287/524287/25/0", "20/524288/524287/25/0"}},
+		{-90, []string{"20/524288/524286/25/0", "20/524288/524287/25/0"}},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := RotateSpatialIds(spatialIDs, center, testCase.angle)
+		if resultErr != nil {
+			t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+		}
+		if !reflect.DeepEqual(resultVal, testCase.expectVal) {
+			t.Errorf("空間ID - 期待値：%v, 取得値：%v", testCase.expectVal, resultVal)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestRotateSpatialIds02 拡張空間IDの集合の鉛直軸回りの回転関数 再ボクセル化の確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 北緯約35度の8×8×2個の拡張空間IDを、中心を通る鉛直軸回りに30度回転
+//
+// + 確認内容
+//   - 個数が元の個数のおよそ(±10%)であること
+//   - 高さ成分が変化しないこと
+//   - 中心付近の拡張空間IDに欠けがないこと
+func TestRotateSpatialIds02(t *testing.T) {
+	spatialIDs := []string{}
+	for x := int64(0); x < 8; x++ {
+		for y := int64(0); y < 8; y++ {
+			for z := int64(0); z < 2; z++ {
+				spatialIDs = append(spatialIDs, shiftingIndex{hZoom: 22, x: 3728000 + x, y: 1650000 + y, vZoom: 25, z: z}.String())
+			}
+		}
+	}
+	center, _ := object.NewPoint(shiftingIndex{hZoom: 22, x: 3728004, y: 1650004, vZoom: 25}.pointAt(0.5, 0.5, 0))
+
+	resultVal, resultErr := RotateSpatialIds(spatialIDs, center, 30)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if math.Abs(float64(len(resultVal))/float64(len(spatialIDs))-1) > 0.1 {
+		t.Errorf("空間ID - 元の要素数：%v, 取得要素数：%v", len(spatialIDs), len(resultVal))
+	}
+	for _, spatialID := range resultVal {
+		index, _ := newShiftingIndex(spatialID, enum.DropBoundary)
+		if index.z != 0 && index.z != 1 {
+			t.Errorf("空間ID - 高さ成分が変化した：%v", spatialID)
+		}
+	}
+	for x := int64(3728002); x < 3728006; x++ {
+		for y := int64(1650002); y < 1650006; y++ {
+			if spatialID := (shiftingIndex{hZoom: 22, x: x, y: y, vZoom: 25}).String(); !contains(resultVal, spatialID) {
+				t.Errorf("空間ID - 期待値を含まない：%v", spatialID)
+			}
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestRotateSpatialIds03 拡張空間IDの集合の平行移動関数、回転関数 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：TranslateSpatialIdsByMeters に無限大の移動距離
+//   - パターン2：TranslateSpatialIdsByGeodeticDelta に拡張空間ID範囲外
+//   - パターン3：RotateSpatialIds にNaNの回転角度
+//   - パターン4：RotateSpatialIds にnilの回転の中心
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestRotateSpatialIds03(t *testing.T) {
+	center, _ := object.NewPoint(0, 0, 0)
+
+	_, resultErr := TranslateSpatialIdsByMeters([]string{"10/10/10/10/10"}, math.Inf(1), 0, 0)
+	if expectErr := "InputValueError,入力チェックエラー,offset is out of range: +Inf"; resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+	_, resultErr = TranslateSpatialIdsByGeodeticDelta([]string{"2/0/4/10/10"}, 0, 0, 0)
+	if expectErr := "InputValueError,入力チェックエラー,index out of range: 2/0/4/10/10"; resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+	_, resultErr = RotateSpatialIds([]string{"10/10/10/10/10"}, center, math.NaN())
+	if expectErr := "InputValueError,入力チェックエラー,angle is out of range: NaN"; resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+	_, resultErr = RotateSpatialIds([]string{"10/10/10/10/10"}, nil, 90)
+	if expectErr := "InputValueError,入力チェックエラー,center is nil"; resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestRotateSpatialIds04 拡張空間IDの集合の鉛直軸回りの回転関数 低精度での網羅性確認
+//
+// 試験詳細：
+// + 試験データ
+//   - 精度3の"3/0/4/0/0"、"3/4/3/0/0"、精度4の"4/0/9/0/0"
+//   - 北緯60度、東経30度を通る鉛直軸回りに140度から320度まで20度ずつ回転
+//
+// + 確認内容
+//   - 全ての拡張空間IDについて中心を逆回転した点を判定した結果と一致すること
+func TestRotateSpatialIds04(t *testing.T) {
+	center, _ := object.NewPoint(30, 60, 0)
+
+	for _, spatialID := range []string{"3/0/4/0/0", "3/4/3/0/0", "4/0/9/0/0"} {
+		for angle := 140.0; angle <= 320; angle += 20 {
+			resultVal, resultErr := RotateSpatialIds([]string{spatialID}, center, angle)
+			if resultErr != nil {
+				t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+			}
+			expectVal := getRotatedSpatialIdsOnAllIndexes(spatialID, center, angle)
+			sort.Strings(resultVal)
+			if !reflect.DeepEqual(resultVal, expectVal) {
+				t.Errorf("空間ID(%v, %v度) - 期待値：%v, 取得値：%v", spatialID, angle, expectVal, resultVal)
+			}
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// getRotatedSpatialIdsOnAllIndexes 同じ精度の全ての拡張空間IDについて、中心を逆回転した点が
+// 元の拡張空間IDに含まれるものを昇順で返却する。
+func getRotatedSpatialIdsOnAllIndexes(spatialID string, center *object.Point, angle float64) []string {
+	source, _ := newShiftingIndex(spatialID, enum.DropBoundary)
+	rotation := spatial.QuatFromAxisAngle(getUnitVectorOnSphere(center.Lon(), center.Lat()), angle*math.Pi/180)
+
+	result := []string{}
+	limit := int64(1) << source.hZoom
+	for x := int64(0); x < limit; x++ {
+		for y := int64(0); y < limit; y++ {
+			index := shiftingIndex{hZoom: source.hZoom, x: x, y: y, vZoom: source.vZoom, z: source.z}
+			lon, lat, alt := index.pointAt(0.5, 0.5, 0.5)
+			vector := rotation.TransformVec3(getUnitVectorOnSphere(lon, lat))
+			lon = math.Atan2(vector.Y, vector.X) * 180 / math.Pi
+			lat = math.Asin(max(-1, min(vector.Z, 1))) * 180 / math.Pi
+			if inverse, ok := newShiftingIndexOnPoint(source.hZoom, source.vZoom, lon, lat, alt); ok && inverse == source {
+				result = append(result, index.String())
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,383 @@
+package shape
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/consts"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+const (
+	// GeoJSONMinAltitudeProperty 最低高度(単位:m)を指定するFeatureのプロパティ名
+	GeoJSONMinAltitudeProperty = "minAltitude"
+	// GeoJSONMaxAltitudeProperty 最高高度(単位:m)を指定するFeatureのプロパティ名
+	GeoJSONMaxAltitudeProperty = "maxAltitude"
+)
+
+// geoJSONObject GeoJSONオブジェクト読み込み用の構造体
+//
+// FeatureCollection、Feature、ジオメトリのいずれも本構造体で読み込む。
+type geoJSONObject struct {
+	Type        string          `json:"type"`        // オブジェクトの種類
+	Features    []geoJSONObject `json:"features"`    // FeatureCollectionのFeature
+	Geometry    *geoJSONObject  `json:"geometry"`    // Featureのジオメトリ
+	Properties  map[string]any  `json:"properties"`  // Featureのプロパティ
+	Coordinates json.RawMessage `json:"coordinates"` // ジオメトリの座標
+}
+
+// GetSpatialIdsOnGeoJSON GeoJSONの空間ID変換関数
+//
+// GeoJSONに含まれるジオメトリを空間IDに変換する。
+// 詳細は GetExtendedSpatialIdsOnGeoJSON のドキュメントを参照。
+//
+// 引数：
+//
+//	geoJSON：GeoJSON文字列
+//	zoom   ：精度レベル
+//
+// 戻り値：
+//
+//	空間IDのリスト。IDの重複は解消された形で返却される。
+//
+// 戻り値(エラー)：
+//
+//	GetExtendedSpatialIdsOnGeoJSON と同様。
+func GetSpatialIdsOnGeoJSON(geoJSON []byte, zoom int64) ([]string, error) {
+	// 拡張空間IDを取得
+	ids, err := GetExtendedSpatialIdsOnGeoJSON(geoJSON, zoom, zoom)
+
+	if err != nil {
+		// エラーが発生した場合エラーインスタンスを返却
+		return ids, err
+	}
+
+	// 拡張空間IDを空間IDのフォーマットに変換
+	return ConvertExtendedSpatialIdsToSpatialIds(ids)
+}
+
+// GetExtendedSpatialIdsOnGeoJSON GeoJSONの拡張空間ID変換関数
+//
+// GeoJSONに含まれるジオメトリを、指定された水平/垂直方向精度の拡張空間IDに変換する。
+// FeatureCollection、Feature、ジオメトリ単体のいずれも入力可能。
+//
+// 対応するジオメトリと変換に使用するAPIは以下の通り。
+//
+//	Point, MultiPoint          ：GetExtendedSpatialIdsOnPoints
+//	LineString, MultiLineString：GetExtendedSpatialIdsOnLine
+//	Polygon, MultiPolygon      ：GetExtendedSpatialIdsOnPolygon
+//
+// Featureのプロパティに GeoJSONMinAltitudeProperty と GeoJSONMaxAltitudeProperty が指定されている場合、
+// 座標の高さは参照せず、ジオメトリを最低高度から最高高度まで垂直方向に押し出した範囲を変換する。
+//
+// 高度範囲が指定されていない場合は座標の高さ(未指定の場合は0m)を使用する。
+// Polygon, MultiPolygonは頂点の高さの最小値から最大値までを高度範囲とする。
+//
+// 引数：
+//
+//	geoJSON：GeoJSON文字列
+//	hZoom  ：水平方向の精度レベル
+//	vZoom  ：垂直方向の精度レベル
+//
+// 戻り値：
+//
+//	拡張空間IDのリスト。IDの重複は解消された形で返却される。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	エラー詳細には"features[Featureのインデックス]"が含まれる。
+//	 精度閾値超過    ：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 GeoJSON不正     ：GeoJSONとして読み込めない場合。
+//	 ジオメトリ不正  ：対応していないジオメトリ、座標の形式に違反したジオメトリが含まれていた場合。
+//	 高度範囲不正    ：高度範囲のプロパティの片方のみが指定されていた場合、数値以外が指定されていた場合。
+func GetExtendedSpatialIdsOnGeoJSON(geoJSON []byte, hZoom, vZoom int64) ([]string, error) {
+	// 拡張空間IDを格納するスライス
+	spatialIds := []string{}
+
+	// 入力値チェック
+	if !CheckZoom(hZoom) || !CheckZoom(vZoom) {
+		return spatialIds, errors.NewSpatialIdError(errors.InputValueErrorCode, "")
+	}
+
+	root := geoJSONObject{}
+	if err := json.Unmarshal(geoJSON, &root); err != nil {
+		return spatialIds, errors.NewSpatialIdError(errors.InputValueErrorCode, err.Error())
+	}
+
+	// Featureのリストに変換
+	features := []geoJSONObject{}
+	switch root.Type {
+	case "FeatureCollection":
+		features = root.Features
+	case "Feature":
+		features = append(features, root)
+	default:
+		features = append(features, geoJSONObject{Type: "Feature", Geometry: &root})
+	}
+
+	for index, feature := range features {
+		ids, err := getExtendedSpatialIdsOnGeoJSONFeature(feature, hZoom, vZoom)
+		if err != nil {
+			return []string{}, errors.NewSpatialIdError(
+				errors.InputValueErrorCode,
+				fmt.Sprintf("features[%v]: %v", index, err),
+			)
+		}
+		spatialIds = append(spatialIds, ids...)
+	}
+
+	return common.Unique(spatialIds), nil
+}
+
+// getExtendedSpatialIdsOnGeoJSONFeature Featureの拡張空間ID変換関数
+//
+// GeoJSONのFeatureを拡張空間IDに変換する。
+//
+// 引数：
+//
+//	feature：Feature
+//	hZoom  ：水平方向の精度レベル
+//	vZoom  ：垂直方向の精度レベル
+//
+// 戻り値：
+//
+//	拡張空間IDのリスト
+//
+// 戻り値(エラー)：
+//
+//	Featureの形式が不正な場合、変換に失敗した場合エラーを返却する。
+func getExtendedSpatialIdsOnGeoJSONFeature(feature geoJSONObject, hZoom, vZoom int64) ([]string, error) {
+	if feature.Type != "Feature" {
+		return nil, fmt.Errorf("unsupported object type %q", feature.Type)
+	}
+	if feature.Geometry == nil {
+		return nil, fmt.Errorf("feature has no geometry")
+	}
+
+	// 高度範囲の取得
+	minAltitude, maxAltitude, hasRange, err := getGeoJSONAltitudeRange(feature.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	geometry, err := newGeometryFromGeoJSON(feature.Geometry)
+	if err != nil {
+		return nil, err
+	}
+
+	return getExtendedSpatialIdsOnGeometry(geometry, minAltitude, maxAltitude, hasRange, hZoom, vZoom)
+}
+
+// newGeometryFromGeoJSON ジオメトリ変換関数
+//
+// GeoJSONのジオメトリを Geometry に変換する。
+//
+// 引数：
+//
+//	source：GeoJSONのジオメトリ
+//
+// 戻り値：
+//
+//	ジオメトリ
+//
+// 戻り値(エラー)：
+//
+//	未対応のジオメトリの場合、座標の形式が不正な場合エラーを返却する。
+func newGeometryFromGeoJSON(source *geoJSONObject) (*Geometry, error) {
+	geometry := &Geometry{}
+
+	switch source.Type {
+	case "Point", "MultiPoint":
+		positions := [][]float64{}
+		if source.Type == "Point" {
+			position := []float64{}
+			if err := json.Unmarshal(source.Coordinates, &position); err != nil {
+				return nil, err
+			}
+			positions = append(positions, position)
+		} else if err := json.Unmarshal(source.Coordinates, &positions); err != nil {
+			return nil, err
+		}
+
+		points, err := newPointsFromGeoJSONPositions(positions)
+		if err != nil {
+			return nil, err
+		}
+		geometry.Type = PointGeometry
+		geometry.Points = points
+
+	case "LineString", "MultiLineString":
+		lines := [][][]float64{}
+		if source.Type == "LineString" {
+			positions := [][]float64{}
+			if err := json.Unmarshal(source.Coordinates, &positions); err != nil {
+				return nil, err
+			}
+			lines = append(lines, positions)
+		} else if err := json.Unmarshal(source.Coordinates, &lines); err != nil {
+			return nil, err
+		}
+
+		geometry.Type = LineStringGeometry
+		for _, line := range lines {
+			points, err := newPointsFromGeoJSONPositions(line)
+			if err != nil {
+				return nil, err
+			}
+			geometry.Lines = append(geometry.Lines, points)
+		}
+
+	case "Polygon", "MultiPolygon":
+		polygons := [][][][]float64{}
+		if source.Type == "Polygon" {
+			rings := [][][]float64{}
+			if err := json.Unmarshal(source.Coordinates, &rings); err != nil {
+				return nil, err
+			}
+			polygons = append(polygons, rings)
+		} else if err := json.Unmarshal(source.Coordinates, &polygons); err != nil {
+			return nil, err
+		}
+
+		geometry.Type = PolygonGeometry
+		for _, rings := range polygons {
+			polygon := make([][]*object.Point, 0, len(rings))
+			for _, ring := range rings {
+				points, err := newPointsFromGeoJSONPositions(ring)
+				if err != nil {
+					return nil, err
+				}
+				polygon = append(polygon, points)
+			}
+			geometry.Polygons = append(geometry.Polygons, polygon)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", source.Type)
+	}
+
+	return geometry, nil
+}
+
+// getGeoJSONAltitudeRange 高度範囲取得関数
+//
+// Featureのプロパティから高度範囲を取得する。
+//
+// 引数：
+//
+//	properties：Featureのプロパティ
+//
+// 戻り値：
+//
+//	(最低高度, 最高高度, 高度範囲の指定有無)
+//
+// 戻り値(エラー)：
+//
+//	高度範囲の片方のみが指定されていた場合、数値以外が指定されていた場合、最低高度が最高高度を超える場合エラーを返却する。
+func getGeoJSONAltitudeRange(properties map[string]any) (float64, float64, bool, error) {
+	minValue, hasMin := properties[GeoJSONMinAltitudeProperty]
+	maxValue, hasMax := properties[GeoJSONMaxAltitudeProperty]
+
+	if !hasMin && !hasMax {
+		return 0, 0, false, nil
+	} else if hasMin != hasMax {
+		return 0, 0, false, fmt.Errorf("both %v and %v are required", GeoJSONMinAltitudeProperty, GeoJSONMaxAltitudeProperty)
+	}
+
+	minAltitude, isMinNumber := minValue.(float64)
+	maxAltitude, isMaxNumber := maxValue.(float64)
+	if !isMinNumber || !isMaxNumber {
+		return 0, 0, false, fmt.Errorf("%v and %v must be numbers", GeoJSONMinAltitudeProperty, GeoJSONMaxAltitudeProperty)
+	}
+	if minAltitude > maxAltitude {
+		return 0, 0, false, fmt.Errorf("%v is greater than %v", GeoJSONMinAltitudeProperty, GeoJSONMaxAltitudeProperty)
+	}
+
+	return minAltitude, maxAltitude, true, nil
+}
+
+// newPointsFromGeoJSONPositions 地理座標変換関数
+//
+// GeoJSONの座標([経度, 緯度] または [経度, 緯度, 高さ])を地理座標に変換する。
+//
+// 引数：
+//
+//	positions：GeoJSONの座標のスライス
+//
+// 戻り値：
+//
+//	地理座標のスライス
+//
+// 戻り値(エラー)：
+//
+//	座標の要素数が2または3でない場合、経度緯度が範囲外の場合エラーを返却する。
+func newPointsFromGeoJSONPositions(positions [][]float64) ([]*object.Point, error) {
+	points := make([]*object.Point, 0, len(positions))
+
+	for _, position := range positions {
+		if len(position) != 2 && len(position) != 3 {
+			return nil, fmt.Errorf("position must have 2 or 3 elements: %v", position)
+		}
+
+		alt := 0.0
+		if len(position) == 3 {
+			alt = position[2]
+		}
+
+		point, err := object.NewPoint(position[0], position[1], alt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: position %v", err, position)
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// extendExtendedSpatialIdsVertically 拡張空間IDの垂直方向押し出し関数
+//
+// 拡張空間IDの水平方向成分はそのままに、垂直方向成分を高度範囲に含まれる全インデックスに置き換える。
+//
+// 引数：
+//
+//	extendedSpatialIds：拡張空間IDのリスト
+//	minAltitude       ：最低高度
+//	maxAltitude       ：最高高度
+//	vZoom             ：垂直方向の精度レベル
+//
+// 戻り値：
+//
+//	垂直方向に押し出した拡張空間IDのリスト。IDの重複は解消された形で返却される。
+func extendExtendedSpatialIdsVertically(
+	extendedSpatialIds []string,
+	minAltitude, maxAltitude float64,
+	vZoom int64,
+) []string {
+	minVIndex, maxVIndex := getVerticalIndexRangeOnAltitudes(minAltitude, maxAltitude, vZoom)
+
+	// 水平方向成分
+	horizontalIds := make([]string, 0, len(extendedSpatialIds))
+	for _, id := range extendedSpatialIds {
+		attrs := strings.Split(id, consts.SpatialIDDelimiter)
+		horizontalIds = append(horizontalIds, strings.Join(attrs[:3], consts.SpatialIDDelimiter))
+	}
+	horizontalIds = common.Unique(horizontalIds)
+
+	ids := make([]string, 0, int64(len(horizontalIds))*(maxVIndex-minVIndex+1))
+	for _, horizontalId := range horizontalIds {
+		for v := minVIndex; v <= maxVIndex; v++ {
+			ids = append(ids, strings.Join([]string{
+				horizontalId,
+				strconv.FormatInt(vZoom, 10),
+				strconv.FormatInt(v, 10),
+			}, consts.SpatialIDDelimiter))
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,164 @@
+package shape
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// TestGetSpatialIdsOnGeoJSON01 正常系動作確認(Point)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     (Point：(139.753098, 35.685371, 0.0), 精度レベル:25)
+//
+// + 確認内容
+//   - GetSpatialIdsOnPoints と同じ空間IDが取得できること
+func TestGetSpatialIdsOnGeoJSON01(t *testing.T) {
+	geoJSON := []byte(`{"type": "Point", "coordinates": [139.753098, 35.685371, 0.0]}`)
+
+	resultVal, resultErr := GetSpatialIdsOnGeoJSON(geoJSON, 25)
+
+	expectVal := []string{"25/0/29803148/13212522"}
+
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+	if resultErr != nil {
+		t.Errorf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetExtendedSpatialIdsOnGeoJSON01 正常系動作確認(FeatureCollection)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     Feature1：Point (139.753098, 35.685371), 高度範囲：0m～3m
+//     Feature2：Polygon [(0, 0), (90, 0), (0, 60)], 高度なし
+//     水平精度レベル:3, 垂直精度レベル:25
+//
+// + 確認内容
+//   - 高度範囲のプロパティが指定されたFeatureは垂直方向に押し出されること
+//   - 全Featureの拡張空間IDが取得できること
+func TestGetExtendedSpatialIdsOnGeoJSON01(t *testing.T) {
+	geoJSON := []byte(`{
+		"type": "FeatureCollection",
+		"features": [
+			{
+				"type": "Feature",
+				"properties": {"minAltitude": 0, "maxAltitude": 3},
+				"geometry": {"type": "Point", "coordinates": [139.753098, 35.685371]}
+			},
+			{
+				"type": "Feature",
+				"properties": {},
+				"geometry": {"type": "Polygon", "coordinates": [[[0, 0], [90, 0], [0, 60], [0, 0]]]}
+			}
+		]
+	}`)
+
+	resultVal, resultErr := GetExtendedSpatialIdsOnGeoJSON(geoJSON, 3, 25)
+
+	expectVal := []string{
+		"3/7/3/25/0", "3/7/3/25/1", "3/7/3/25/2",
+		"3/4/2/25/0", "3/4/3/25/0", "3/5/3/25/0",
+	}
+
+	sort.Strings(resultVal)
+	sort.Strings(expectVal)
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+	if resultErr != nil {
+		t.Errorf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetExtendedSpatialIdsOnGeoJSON02 正常系動作確認(LineString)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     (LineString：拡張空間ID 10/10/10/10/10 から 10/13/10/10/10 の中心を結ぶ線, 精度レベル:10)
+//
+// + 確認内容
+//   - GetExtendedSpatialIdsOnLine と同じ拡張空間IDが取得できること
+func TestGetExtendedSpatialIdsOnGeoJSON02(t *testing.T) {
+	geoJSON := []byte(`{
+		"type": "Feature",
+		"geometry": {
+			"type": "LineString",
+			"coordinates": [[-176.30859375, 84.7222180279, 344064], [-175.25390625, 84.7222180279, 344064]]
+		}
+	}`)
+
+	resultVal, resultErr := GetExtendedSpatialIdsOnGeoJSON(geoJSON, 10, 10)
+
+	expectVal := []string{"10/10/10/10/10", "10/11/10/10/10", "10/12/10/10/10", "10/13/10/10/10"}
+
+	sort.Strings(resultVal)
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+	if resultErr != nil {
+		t.Errorf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetExtendedSpatialIdsOnGeoJSON03 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：2番目のFeatureが未対応のジオメトリ
+//   - パターン2：1番目のFeatureの高度範囲が片方のみ
+//   - パターン3：1番目のFeatureの緯度が範囲外
+//   - パターン4：GeoJSONとして不正
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却され、Featureのインデックスが含まれること
+func TestGetExtendedSpatialIdsOnGeoJSON03(t *testing.T) {
+	testCases := []struct {
+		geoJSON string
+		expect  string
+	}{
+		{
+			`{"type": "FeatureCollection", "features": [
+				{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0, 0]}},
+				{"type": "Feature", "geometry": {"type": "Circle", "coordinates": [0, 0]}}
+			]}`,
+			`InputValueError,入力チェックエラー,features[1]: unsupported geometry type "Circle"`,
+		},
+		{
+			`{"type": "Feature", "properties": {"minAltitude": 0}, "geometry": {"type": "Point", "coordinates": [0, 0]}}`,
+			"InputValueError,入力チェックエラー,features[0]: both minAltitude and maxAltitude are required",
+		},
+		{
+			`{"type": "Point", "coordinates": [0, 89]}`,
+			"InputValueError,入力チェックエラー,features[0]: InputValueError,入力チェックエラー: position [0 89]",
+		},
+		{
+			`{"type": `,
+			"InputValueError,入力チェックエラー,unexpected end of JSON input",
+		},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := GetExtendedSpatialIdsOnGeoJSON([]byte(testCase.geoJSON), 10, 10)
+		if len(resultVal) != 0 {
+			t.Errorf("拡張空間ID - 期待値：[], 取得値：%v", resultVal)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
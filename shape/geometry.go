@@ -0,0 +1,206 @@
+package shape
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// GeometryType ジオメトリ種別用の型
+type GeometryType int
+
+// Geometryで扱うジオメトリの種別
+const (
+	PointGeometry             GeometryType = iota // 点(Point, MultiPoint)(0)
+	LineStringGeometry                            // 線(LineString, MultiLineString)(1)
+	PolygonGeometry                               // 多角形(Polygon, MultiPolygon)(2)
+	PolyhedralSurfaceGeometry                     // 多面体(PolyhedralSurface)(3)
+)
+
+// Geometry 地理座標で構成されたジオメトリの構造体
+//
+// GeoJSON、WKT、WKBから読み込んだジオメトリを保持する。
+// 種別に応じて以下のフィールドを使用する。
+//
+//	PointGeometry            ：Points
+//	LineStringGeometry       ：Lines
+//	PolygonGeometry          ：Polygons (多角形ごとに、先頭を外周、以降を穴とする頂点リスト)
+//	PolyhedralSurfaceGeometry：Polygons (面ごとの頂点リスト)
+type Geometry struct {
+	Type     GeometryType        // ジオメトリの種別
+	Points   []*object.Point     // 点の座標
+	Lines    [][]*object.Point   // 線ごとの頂点座標
+	Polygons [][][]*object.Point // 多角形ごとの環の頂点座標
+}
+
+// GetExtendedSpatialIdsOnGeometry ジオメトリの拡張空間ID変換関数
+//
+// ジオメトリの種別に応じた形状APIを呼び出し、拡張空間IDに変換する。
+//
+//	PointGeometry            ：GetExtendedSpatialIdsOnPoints
+//	LineStringGeometry       ：GetExtendedSpatialIdsOnLine (隣接する頂点間の線分ごと)
+//	PolygonGeometry          ：GetExtendedSpatialIdsOnPolygon (多角形ごとに頂点の高さの最小値から最大値までを高度範囲とする)
+//	PolyhedralSurfaceGeometry：GetExtendedSpatialIdsOnPolygon (全ての面を水平方向に投影し、全頂点の高さの最小値から最大値までを高度範囲とする)
+//
+// 引数：
+//
+//	geometry：ジオメトリ
+//	hZoom   ：水平方向の精度レベル
+//	vZoom   ：垂直方向の精度レベル
+//
+// 戻り値：
+//
+//	拡張空間IDのリスト。IDの重複は解消された形で返却される。
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過  ：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 ジオメトリ不正：ジオメトリがnilの場合、頂点数が不足している場合、未対応の種別の場合。
+func GetExtendedSpatialIdsOnGeometry(geometry *Geometry, hZoom, vZoom int64) ([]string, error) {
+	// 入力値チェック
+	if !CheckZoom(hZoom) || !CheckZoom(vZoom) {
+		return []string{}, errors.NewSpatialIdError(errors.InputValueErrorCode, "")
+	}
+	if geometry == nil {
+		return []string{}, errors.NewSpatialIdError(errors.InputValueErrorCode, "geometry is nil")
+	}
+
+	ids, err := getExtendedSpatialIdsOnGeometry(geometry, 0, 0, false, hZoom, vZoom)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return ids, nil
+}
+
+// getExtendedSpatialIdsOnGeometry 高度範囲指定付きジオメトリの拡張空間ID変換関数
+//
+// 高度範囲が指定されている場合、頂点の高さは参照せず、
+// ジオメトリを最低高度から最高高度まで垂直方向に押し出した範囲を変換する。
+//
+// 引数：
+//
+//	geometry   ：ジオメトリ
+//	minAltitude：最低高度
+//	maxAltitude：最高高度
+//	hasRange   ：高度範囲の指定有無
+//	hZoom      ：水平方向の精度レベル
+//	vZoom      ：垂直方向の精度レベル
+//
+// 戻り値：
+//
+//	拡張空間IDのリスト。IDの重複は解消された形で返却される。
+//
+// 戻り値(エラー)：
+//
+//	頂点数が不足している場合、未対応の種別の場合、形状APIがエラーを返却した場合エラーを返却する。
+func getExtendedSpatialIdsOnGeometry(
+	geometry *Geometry,
+	minAltitude, maxAltitude float64,
+	hasRange bool,
+	hZoom, vZoom int64,
+) ([]string, error) {
+	ids := []string{}
+
+	switch geometry.Type {
+	case PointGeometry:
+		pointIds, err := GetExtendedSpatialIdsOnPoints(geometry.Points, hZoom, vZoom)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, pointIds...)
+
+	case LineStringGeometry:
+		for _, line := range geometry.Lines {
+			if len(line) < 2 {
+				return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "line must have at least 2 points")
+			}
+			for i := 0; i+1 < len(line); i++ {
+				start, end := line[i], line[i+1]
+				// 高度範囲が指定されている場合は水平方向のみ変換するため、高さを揃える
+				if hasRange && start != nil && end != nil {
+					start, _ = object.NewPoint(start.Lon(), start.Lat(), minAltitude)
+					end, _ = object.NewPoint(end.Lon(), end.Lat(), minAltitude)
+				}
+				lineIds, err := GetExtendedSpatialIdsOnLine(start, end, hZoom, vZoom)
+				if err != nil {
+					return nil, err
+				}
+				ids = append(ids, lineIds...)
+			}
+		}
+
+	case PolygonGeometry:
+		for _, polygon := range geometry.Polygons {
+			polygonMin, polygonMax := minAltitude, maxAltitude
+			if !hasRange {
+				polygonMin, polygonMax = getAltitudeRangeOnPolygons([][][]*object.Point{polygon})
+			}
+			polygonIds, err := GetExtendedSpatialIdsOnPolygon(polygon, polygonMin, polygonMax, hZoom, vZoom)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, polygonIds...)
+		}
+
+	case PolyhedralSurfaceGeometry:
+		if !hasRange {
+			minAltitude, maxAltitude = getAltitudeRangeOnPolygons(geometry.Polygons)
+		}
+		for _, face := range geometry.Polygons {
+			faceIds, err := GetExtendedSpatialIdsOnPolygon(face, minAltitude, maxAltitude, hZoom, vZoom)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, faceIds...)
+		}
+
+	default:
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("unsupported geometry type %v", geometry.Type))
+	}
+
+	// 高度範囲が指定されている場合は垂直方向に押し出す
+	if hasRange && (geometry.Type == PointGeometry || geometry.Type == LineStringGeometry) {
+		ids = extendExtendedSpatialIdsVertically(ids, minAltitude, maxAltitude, vZoom)
+	}
+
+	return common.Unique(ids), nil
+}
+
+// getAltitudeRangeOnPolygons 多角形の高度範囲取得関数
+//
+// 多角形の全頂点の高さの最小値と最大値を取得する。nilの頂点は無視する。
+//
+// 引数：
+//
+//	polygons：多角形のスライス
+//
+// 戻り値：
+//
+//	(最低高度, 最高高度)
+func getAltitudeRangeOnPolygons(polygons [][][]*object.Point) (float64, float64) {
+	minAltitude, maxAltitude := math.Inf(1), math.Inf(-1)
+
+	for _, polygon := range polygons {
+		for _, ring := range polygon {
+			for _, p := range ring {
+				if p == nil {
+					continue
+				}
+				minAltitude = math.Min(minAltitude, p.Alt())
+				maxAltitude = math.Max(maxAltitude, p.Alt())
+			}
+		}
+	}
+
+	// 頂点が存在しない場合は0mとする
+	if minAltitude > maxAltitude {
+		return 0, 0
+	}
+
+	return minAltitude, maxAltitude
+}
@@ -0,0 +1,79 @@
+package shape
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// TestGetExtendedSpatialIdsOnGeometry01 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     (Polygon：[(0, 0, 0), (90, 0, 2.5), (0, 60, 0)], 水平精度レベル:3, 垂直精度レベル:25)
+//
+// + 確認内容
+//   - 頂点の高さの最小値から最大値までの範囲で拡張空間IDが取得できること
+func TestGetExtendedSpatialIdsOnGeometry01(t *testing.T) {
+	p1, _ := object.NewPoint(0, 0, 0)
+	p2, _ := object.NewPoint(90, 0, 2.5)
+	p3, _ := object.NewPoint(0, 60, 0)
+	geometry := &Geometry{
+		Type:     PolygonGeometry,
+		Polygons: [][][]*object.Point{{{p1, p2, p3}}},
+	}
+
+	resultVal, resultErr := GetExtendedSpatialIdsOnGeometry(geometry, 3, 25)
+
+	expectVal := []string{
+		"3/4/2/25/0", "3/4/2/25/1", "3/4/2/25/2",
+		"3/4/3/25/0", "3/4/3/25/1", "3/4/3/25/2",
+		"3/5/3/25/0", "3/5/3/25/1", "3/5/3/25/2",
+	}
+
+	sort.Strings(resultVal)
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+	if resultErr != nil {
+		t.Errorf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetExtendedSpatialIdsOnGeometry02 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：ジオメトリがnil
+//   - パターン2：頂点数1の線
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetExtendedSpatialIdsOnGeometry02(t *testing.T) {
+	p1, _ := object.NewPoint(0, 0, 0)
+
+	testCases := []struct {
+		geometry *Geometry
+		expect   string
+	}{
+		{nil, "InputValueError,入力チェックエラー,geometry is nil"},
+		{&Geometry{Type: LineStringGeometry, Lines: [][]*object.Point{{p1}}}, "InputValueError,入力チェックエラー,line must have at least 2 points"},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := GetExtendedSpatialIdsOnGeometry(testCase.geometry, 3, 3)
+		if len(resultVal) != 0 {
+			t.Errorf("拡張空間ID - 期待値：[], 取得値：%v", resultVal)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
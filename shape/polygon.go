@@ -0,0 +1,378 @@
+package shape
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/consts"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// tileBoundaryMinima タイル境界とみなすタイル座標の誤差
+const tileBoundaryMinima = 1e-6
+
+// GetSpatialIdsOnPolygon 指定範囲の空間ID変換(多角形柱)を取得する。
+//
+// 多角形を底面とし、最低高度から最高高度までを高さとする多角形柱に含まれる空間IDを取得する。
+// 詳細は GetExtendedSpatialIdsOnPolygon のドキュメントを参照。
+//
+// 引数：
+//
+//	polygon    ：多角形。先頭を外周、以降を穴とする頂点リストのスライス。
+//	minAltitude：最低高度(単位:m)
+//	maxAltitude：最高高度(単位:m)
+//	zoom       ：精度レベル
+//
+// 戻り値：
+//
+//	空間ID集合
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過：精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 多角形不正  ：頂点数が3未満の環、nilの頂点が含まれていた場合。
+//	 高度不正    ：最低高度が最高高度を超えていた場合。
+func GetSpatialIdsOnPolygon(
+	polygon [][]*object.Point,
+	minAltitude float64,
+	maxAltitude float64,
+	zoom int64,
+) ([]string, error) {
+	// 拡張空間IDを取得
+	ids, err := GetExtendedSpatialIdsOnPolygon(polygon, minAltitude, maxAltitude, zoom, zoom)
+
+	if err != nil {
+		// エラーが発生した場合エラーインスタンスを返却
+		return ids, err
+	}
+
+	// 拡張空間IDを空間IDのフォーマットに変換
+	return ConvertExtendedSpatialIdsToSpatialIds(ids)
+}
+
+// GetExtendedSpatialIdsOnPolygon 指定範囲の拡張空間ID変換(多角形柱)を取得する。
+//
+// 多角形を底面とし、最低高度から最高高度までを高さとする多角形柱に含まれる拡張空間IDを取得する。
+// 多角形の辺はWebメルカトル座標上の直線として扱い、多角形と水平方向に一部でも重なるタイルを返却する。
+// 多角形の外周、穴の頂点は閉じていても(先頭と末尾が同一座標)、閉じていなくてもよい。
+//
+// 垂直方向は最低高度を含むボクセルから、最高高度を上面に含むボクセルまでを返却する。
+// 最低高度と最高高度が等しい場合は、その高度を含むボクセル1層を返却する。
+//
+// 引数：
+//
+//	polygon    ：多角形。先頭を外周、以降を穴とする頂点リストのスライス。頂点の高さは参照しない。
+//	minAltitude：最低高度(単位:m)
+//	maxAltitude：最高高度(単位:m)
+//	hZoom      ：水平方向の精度レベル
+//	vZoom      ：垂直方向の精度レベル
+//
+// 戻り値：
+//
+//	拡張空間ID集合
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 多角形不正  ：頂点数が3未満の環、nilの頂点が含まれていた場合。
+//	 高度不正    ：最低高度が最高高度を超えていた場合。
+//
+// 補足事項：
+//
+//	多角形の面積に対して精度が高すぎる場合、拡張空間ID数は大幅に増大する。
+//	動作環境によってはメモリ不足となる可能性があるため、注意すること。
+func GetExtendedSpatialIdsOnPolygon(
+	polygon [][]*object.Point,
+	minAltitude float64,
+	maxAltitude float64,
+	hZoom int64,
+	vZoom int64,
+) ([]string, error) {
+	// 入力値チェック
+	if !CheckZoom(hZoom) || !CheckZoom(vZoom) {
+		return []string{}, errors.NewSpatialIdError(errors.InputValueErrorCode, "")
+	}
+	if len(polygon) == 0 {
+		return []string{}, errors.NewSpatialIdError(errors.InputValueErrorCode, "polygon has no ring")
+	}
+	for _, ring := range polygon {
+		if len(ring) < 3 {
+			return []string{}, errors.NewSpatialIdError(errors.InputValueErrorCode, "polygon ring must have at least 3 points")
+		}
+		for _, p := range ring {
+			if p == nil {
+				return []string{}, errors.NewSpatialIdError(errors.InputValueErrorCode, "polygon ring has nil point")
+			}
+		}
+	}
+	if minAltitude > maxAltitude {
+		return []string{}, errors.NewSpatialIdError(errors.InputValueErrorCode, "minAltitude is greater than maxAltitude")
+	}
+
+	// 水平方向のタイル
+	tiles := getHorizontalTilesOnPolygon(polygon, hZoom)
+
+	// 垂直方向のインデックス範囲
+	minVIndex, maxVIndex := getVerticalIndexRangeOnAltitudes(minAltitude, maxAltitude, vZoom)
+
+	spatialIds := make([]string, 0, int64(len(tiles))*(maxVIndex-minVIndex+1))
+	for _, tile := range tiles {
+		for v := minVIndex; v <= maxVIndex; v++ {
+			spatialIds = append(spatialIds, strings.Join([]string{
+				strconv.FormatInt(hZoom, 10),
+				strconv.FormatInt(tile[0], 10),
+				strconv.FormatInt(tile[1], 10),
+				strconv.FormatInt(vZoom, 10),
+				strconv.FormatInt(v, 10),
+			}, consts.SpatialIDDelimiter))
+		}
+	}
+
+	return spatialIds, nil
+}
+
+// getVerticalIndexRangeOnAltitudes 高度範囲の垂直方向インデックス取得関数
+//
+// 最低高度を含むボクセルから、最高高度を上面に含むボクセルまでの垂直方向インデックスの範囲を取得する。
+//
+// 引数：
+//
+//	minAltitude：最低高度(単位:m)
+//	maxAltitude：最高高度(単位:m)
+//	vZoom      ：垂直方向の精度
+//
+// 戻り値：
+//
+//	(最小インデックス, 最大インデックス)
+func getVerticalIndexRangeOnAltitudes(minAltitude, maxAltitude float64, vZoom int64) (int64, int64) {
+	// 高さ全体の精度あたりの垂直方向の精度
+	altResolution := math.Pow(2, consts.ZOriginValue) / math.Pow(2, float64(vZoom))
+
+	minVIndex := int64(math.Floor(minAltitude / altResolution))
+	maxVIndex := int64(math.Ceil(maxAltitude/altResolution)) - 1
+	if maxVIndex < minVIndex {
+		maxVIndex = minVIndex
+	}
+
+	return minVIndex, maxVIndex
+}
+
+// getHorizontalTilesOnPolygon 多角形の水平方向タイル取得関数
+//
+// 多角形と一部でも重なる水平方向のタイルの(x, y)インデックスを、y、xの昇順で取得する。
+// 辺が通過するタイルと、中心が多角形の内部にあるタイルの和集合を返却する。
+//
+// 引数：
+//
+//	polygon：多角形。先頭を外周、以降を穴とする頂点リストのスライス。
+//	hZoom  ：水平方向の精度
+//
+// 戻り値：
+//
+//	タイルの(x, y)インデックスのスライス
+func getHorizontalTilesOnPolygon(polygon [][]*object.Point, hZoom int64) [][2]int64 {
+	// インデックスの最大値
+	maxIndex := int64(math.Pow(2, float64(hZoom))) - 1
+
+	// タイル座標系に変換した多角形
+	rings := make([][][2]float64, 0, len(polygon))
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, ring := range polygon {
+		tileRing := make([][2]float64, 0, len(ring))
+		for _, p := range ring {
+			x, y := getTileCoordinateOnPoint(p.Lon(), p.Lat(), hZoom)
+			tileRing = append(tileRing, [2]float64{x, y})
+			minY = math.Min(minY, y)
+			maxY = math.Max(maxY, y)
+		}
+		rings = append(rings, tileRing)
+	}
+
+	tiles := map[[2]int64]struct{}{}
+	addTile := func(x, y int64) {
+		tiles[[2]int64{clampIndex(x, maxIndex), clampIndex(y, maxIndex)}] = struct{}{}
+	}
+
+	// 辺が通過するタイル
+	for _, ring := range rings {
+		for i := range ring {
+			start := ring[i]
+			end := ring[(i+1)%len(ring)]
+			traverseTilesOnSegment(start[0], start[1], end[0], end[1], addTile)
+		}
+	}
+
+	// 中心が多角形の内部にあるタイル
+	for y := int64(math.Floor(minY)); y <= int64(math.Floor(maxY)); y++ {
+		// タイル中心を通る走査線と辺の交点
+		scanY := float64(y) + 0.5
+		crossings := []float64{}
+		for _, ring := range rings {
+			for i := range ring {
+				start := ring[i]
+				end := ring[(i+1)%len(ring)]
+				if (start[1] <= scanY) == (end[1] <= scanY) {
+					continue
+				}
+				crossings = append(crossings,
+					start[0]+(scanY-start[1])*(end[0]-start[0])/(end[1]-start[1]))
+			}
+		}
+		sort.Float64s(crossings)
+
+		// 偶奇規則で内部となる区間のタイルを追加
+		for i := 0; i+1 < len(crossings); i += 2 {
+			minX := int64(math.Ceil(crossings[i] - 0.5))
+			maxX := int64(math.Floor(crossings[i+1] - 0.5))
+			for x := minX; x <= maxX; x++ {
+				addTile(x, y)
+			}
+		}
+	}
+
+	result := make([][2]int64, 0, len(tiles))
+	for tile := range tiles {
+		result = append(result, tile)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i][1] != result[j][1] {
+			return result[i][1] < result[j][1]
+		}
+		return result[i][0] < result[j][0]
+	})
+
+	return result
+}
+
+// getTileCoordinateOnPoint タイル座標取得関数
+//
+// 経度緯度から、指定精度のタイルインデックスを単位とする実数座標を計算する。
+//
+// 引数：
+//
+//	lon  ：経度(単位:度)
+//	lat  ：緯度(単位:度)
+//	hZoom：水平方向の精度
+//
+// 戻り値：
+//
+//	(x座標, y座標)
+func getTileCoordinateOnPoint(lon float64, lat float64, hZoom int64) (float64, float64) {
+	n := math.Pow(2, float64(hZoom))
+	latRadian := common.DegreeToRadian(lat)
+
+	x := n * (lon + 180.0) / 360.0
+	y := n * (1 - math.Log(math.Tan(latRadian)+(1/math.Cos(latRadian)))/math.Pi) / 2
+
+	return snapToTileBoundary(x), snapToTileBoundary(y)
+}
+
+// snapToTileBoundary タイル境界補正関数
+//
+// 浮動小数点誤差によりタイル境界からわずかにずれた座標をタイル境界に補正する。
+// 空間IDの頂点座標から作成した多角形が隣接タイルを含まないようにするための補正。
+//
+// 引数：
+//
+//	v：タイル座標
+//
+// 戻り値：
+//
+//	補正後のタイル座標
+func snapToTileBoundary(v float64) float64 {
+	if rounded := math.Round(v); math.Abs(v-rounded) < tileBoundaryMinima {
+		return rounded
+	}
+	return v
+}
+
+// traverseTilesOnSegment 線分通過タイル走査関数
+//
+// タイル座標系の線分が内部を通過する全てのタイルを走査する。
+// タイルの境界線上のみを通る区間、タイルの頂点のみを通る区間は通過とみなさない。
+//
+// 引数：
+//
+//	x0, y0 ：始点のタイル座標
+//	x1, y1 ：終点のタイル座標
+//	operate：通過するタイルのインデックスに対する処理関数
+func traverseTilesOnSegment(x0, y0, x1, y1 float64, operate func(x, y int64)) {
+	dx := x1 - x0
+	dy := y1 - y0
+
+	// 線分を媒介変数t(0 ≦ t ≦ 1)で表した時の、タイル境界と交差するt
+	ts := []float64{0, 1}
+	ts = append(ts, gridCrossings(x0, x1)...)
+	ts = append(ts, gridCrossings(y0, y1)...)
+	sort.Float64s(ts)
+
+	for i := 0; i+1 < len(ts); i++ {
+		if ts[i+1]-ts[i] <= 0 {
+			continue
+		}
+		// 交差点間の中点を含むタイルを通過タイルとする
+		t := (ts[i] + ts[i+1]) / 2
+		x := x0 + dx*t
+		y := y0 + dy*t
+
+		// タイルの境界線上を通る区間は除外
+		if (dx == 0 && x == math.Floor(x)) || (dy == 0 && y == math.Floor(y)) {
+			continue
+		}
+		operate(int64(math.Floor(x)), int64(math.Floor(y)))
+	}
+}
+
+// gridCrossings タイル境界交差取得関数
+//
+// 線分を媒介変数t(0 < t < 1)で表した時に、1軸分の座標が整数となるtを取得する。
+//
+// 引数：
+//
+//	start：始点の座標
+//	end  ：終点の座標
+//
+// 戻り値：
+//
+//	座標が整数となるtのスライス
+func gridCrossings(start, end float64) []float64 {
+	diff := end - start
+	if diff == 0 {
+		return []float64{}
+	}
+
+	low, high := math.Min(start, end), math.Max(start, end)
+	ts := make([]float64, 0, int64(high-low)+1)
+	for k := math.Floor(low) + 1; k < high; k++ {
+		ts = append(ts, (k-start)/diff)
+	}
+
+	return ts
+}
+
+// clampIndex インデックス範囲制限関数
+//
+// インデックスを[0, maxIndex]の範囲に制限する。
+//
+// 引数：
+//
+//	index   ：インデックス
+//	maxIndex：インデックスの最大値
+//
+// 戻り値：
+//
+//	範囲内に制限したインデックス
+func clampIndex(index, maxIndex int64) int64 {
+	if index < 0 {
+		return 0
+	} else if index > maxIndex {
+		return maxIndex
+	}
+	return index
+}
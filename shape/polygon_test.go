@@ -0,0 +1,161 @@
+package shape
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// TestGetSpatialIdsOnPolygon01 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     (多角形：[(0, 0), (90, 0), (0, 60)], 高度範囲：0m～0m, 精度レベル:3)
+//
+// + 確認内容
+//   - 多角形と重なる空間IDのみが取得でき、境界線で接するだけの空間IDは含まれないこと
+func TestGetSpatialIdsOnPolygon01(t *testing.T) {
+	p1, _ := object.NewPoint(0, 0, 0)
+	p2, _ := object.NewPoint(90, 0, 0)
+	p3, _ := object.NewPoint(0, 60, 0)
+	polygon := [][]*object.Point{{p1, p2, p3}}
+
+	resultVal, resultErr := GetSpatialIdsOnPolygon(polygon, 0, 0, 3)
+
+	expectVal := []string{"3/0/4/2", "3/0/4/3", "3/0/5/3"}
+
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+	if resultErr != nil {
+		t.Errorf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetExtendedSpatialIdsOnPolygon01 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     (多角形：拡張空間ID 10/100/200/10/0 の北西端から 10/102/201/10/0 の南東端までの矩形,
+//     高度範囲：0m～2m, 水平精度レベル:10, 垂直精度レベル:25)
+//
+// + 確認内容
+//   - タイル境界に一致する多角形で、隣接するタイルが含まれないこと
+//   - 高度範囲の上端に接するだけのボクセルが含まれないこと
+func TestGetExtendedSpatialIdsOnPolygon01(t *testing.T) {
+	northWest, _ := GetPointOnExtendedSpatialId("10/100/200/10/0", enum.Vertex)
+	southEast, _ := GetPointOnExtendedSpatialId("10/102/201/10/0", enum.Vertex)
+	west, north := northWest[0].Lon(), northWest[0].Lat()
+	east, south := southEast[2].Lon(), southEast[2].Lat()
+	p1, _ := object.NewPoint(west, north, 0)
+	p2, _ := object.NewPoint(east, north, 0)
+	p3, _ := object.NewPoint(east, south, 0)
+	p4, _ := object.NewPoint(west, south, 0)
+	polygon := [][]*object.Point{{p1, p2, p3, p4, p1}}
+
+	resultVal, resultErr := GetExtendedSpatialIdsOnPolygon(polygon, 0, 2, 10, 25)
+
+	expectVal := []string{
+		"10/100/200/25/0", "10/100/200/25/1",
+		"10/101/200/25/0", "10/101/200/25/1",
+		"10/102/200/25/0", "10/102/200/25/1",
+		"10/100/201/25/0", "10/100/201/25/1",
+		"10/101/201/25/0", "10/101/201/25/1",
+		"10/102/201/25/0", "10/102/201/25/1",
+	}
+
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+	if resultErr != nil {
+		t.Errorf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetExtendedSpatialIdsOnPolygon02 正常系動作確認(穴あき多角形)
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：
+//     (外周：タイル座標(0,0)～(3,3)の矩形, 穴：タイル座標(1,1)～(2,2)の矩形, 高度範囲：0m～0m, 精度レベル:2)
+//
+// + 確認内容
+//   - 穴に含まれる拡張空間IDが返却されないこと
+func TestGetExtendedSpatialIdsOnPolygon02(t *testing.T) {
+	// タイル座標の頂点を地理座標に変換
+	corner := func(id string, index int) *object.Point {
+		vertexes, _ := GetPointOnExtendedSpatialId(id, enum.Vertex)
+		p, _ := object.NewPoint(vertexes[index].Lon(), vertexes[index].Lat(), 0)
+		return p
+	}
+	outer := []*object.Point{
+		corner("2/0/0/0/0", 0), corner("2/2/0/0/0", 1), corner("2/2/2/0/0", 2), corner("2/0/2/0/0", 3),
+	}
+	hole := []*object.Point{
+		corner("2/1/1/0/0", 0), corner("2/1/1/0/0", 1), corner("2/1/1/0/0", 2), corner("2/1/1/0/0", 3),
+	}
+
+	resultVal, resultErr := GetExtendedSpatialIdsOnPolygon([][]*object.Point{outer, hole}, 0, 0, 2, 0)
+
+	expectVal := []string{
+		"2/0/0/0/0", "2/1/0/0/0", "2/2/0/0/0",
+		"2/0/1/0/0", "2/2/1/0/0",
+		"2/0/2/0/0", "2/1/2/0/0", "2/2/2/0/0",
+	}
+
+	if !reflect.DeepEqual(resultVal, expectVal) {
+		t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, resultVal)
+	}
+	if resultErr != nil {
+		t.Errorf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetExtendedSpatialIdsOnPolygon03 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：精度レベル:36
+//   - パターン2：頂点数2の環
+//   - パターン3：最低高度が最高高度を超える
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetExtendedSpatialIdsOnPolygon03(t *testing.T) {
+	p1, _ := object.NewPoint(0, 0, 0)
+	p2, _ := object.NewPoint(90, 0, 0)
+	p3, _ := object.NewPoint(0, 60, 0)
+
+	testCases := []struct {
+		polygon  [][]*object.Point
+		min, max float64
+		hZoom    int64
+		expect   string
+	}{
+		{[][]*object.Point{{p1, p2, p3}}, 0, 0, 36, "InputValueError,入力チェックエラー"},
+		{[][]*object.Point{{p1, p2}}, 0, 0, 3, "InputValueError,入力チェックエラー,polygon ring must have at least 3 points"},
+		{[][]*object.Point{{p1, p2, p3}}, 10, 0, 3, "InputValueError,入力チェックエラー,minAltitude is greater than maxAltitude"},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := GetExtendedSpatialIdsOnPolygon(testCase.polygon, testCase.min, testCase.max, testCase.hZoom, 3)
+		if len(resultVal) != 0 {
+			t.Errorf("拡張空間ID - 期待値：[], 取得値：%v", resultVal)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
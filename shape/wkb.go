@@ -0,0 +1,355 @@
+package shape
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// WKBのジオメトリ種別コード
+const (
+	wkbPoint                    = 1
+	wkbLineString               = 2
+	wkbPolygon                  = 3
+	wkbMultiPoint               = 4
+	wkbMultiLineString          = 5
+	wkbMultiPolygon             = 6
+	wkbPolyhedralSurface        = 15
+	ewkbZFlag            uint32 = 0x80000000 // EWKBのZ次元フラグ
+	ewkbMFlag            uint32 = 0x40000000 // EWKBのM次元フラグ
+	ewkbSRIDFlag         uint32 = 0x20000000 // EWKBのSRIDフラグ
+)
+
+// wkbReader WKB読み込み用の構造体
+type wkbReader struct {
+	data []byte // WKBのバイト列
+	pos  int    // 読み込み位置
+}
+
+// ParseWKB WKB読み込み関数
+//
+// WKB(Well-Known Binary)を読み込み、ジオメトリに変換する。
+// 座標は[経度 緯度]、[経度 緯度 高さ]の順とし、EPSG:4326の地理座標として扱う。
+//
+// ISO形式(種別コード+1000:Z, +2000:M, +3000:ZM)と、
+// PostGISのEWKB形式(Z、M、SRIDのフラグ)に対応する。Mの値は参照しない。
+// 対応するジオメトリは ParseWKT と同じ。
+//
+// 引数：
+//
+//	wkb：WKBのバイト列
+//
+// 戻り値：
+//
+//	ジオメトリ
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 WKB不正       ：WKBのバイト列が不足している場合、未対応のジオメトリの場合。
+//	 座標の範囲超過：経度、緯度が object.NewPoint の入力範囲外の場合。
+func ParseWKB(wkb []byte) (*Geometry, error) {
+	r := &wkbReader{data: wkb}
+
+	geometry, err := r.readGeometry()
+	if err != nil {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, err.Error())
+	}
+	if r.pos != len(r.data) {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("%v trailing bytes", len(r.data)-r.pos))
+	}
+
+	return geometry, nil
+}
+
+// readHeader ジオメトリのヘッダの読み込み
+//
+// バイト順と種別コードを読み込む。
+//
+// 戻り値：
+//
+//	(バイト順, 種別コード, 次元指定)
+//
+// 戻り値(エラー)：
+//
+//	バイト列が不足している場合、バイト順が不正な場合エラーを返却する。
+func (r *wkbReader) readHeader() (binary.ByteOrder, uint32, string, error) {
+	if r.pos >= len(r.data) {
+		return nil, 0, "", fmt.Errorf("unexpected end of WKB")
+	}
+
+	var order binary.ByteOrder
+	switch r.data[r.pos] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
+	default:
+		return nil, 0, "", fmt.Errorf("invalid byte order %v", r.data[r.pos])
+	}
+	r.pos++
+
+	code, err := r.readUint32(order)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	// EWKBのフラグ
+	hasZ := code&ewkbZFlag != 0
+	hasM := code&ewkbMFlag != 0
+	if code&ewkbSRIDFlag != 0 {
+		if _, err := r.readUint32(order); err != nil {
+			return nil, 0, "", err
+		}
+	}
+	code &^= ewkbZFlag | ewkbMFlag | ewkbSRIDFlag
+
+	// ISO形式の次元
+	switch code / 1000 {
+	case 1:
+		hasZ = true
+	case 2:
+		hasM = true
+	case 3:
+		hasZ, hasM = true, true
+	}
+	code %= 1000
+
+	dimension := ""
+	switch {
+	case hasZ && hasM:
+		dimension = "ZM"
+	case hasZ:
+		dimension = "Z"
+	case hasM:
+		dimension = "M"
+	}
+
+	return order, code, dimension, nil
+}
+
+// readGeometry ジオメトリの読み込み
+//
+// 戻り値：
+//
+//	ジオメトリ
+//
+// 戻り値(エラー)：
+//
+//	バイト列が不足している場合、未対応のジオメトリの場合エラーを返却する。
+func (r *wkbReader) readGeometry() (*Geometry, error) {
+	order, code, dimension, err := r.readHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	geometry := &Geometry{}
+
+	switch code {
+	case wkbPoint:
+		point, err := r.readPoint(order, dimension)
+		if err != nil {
+			return nil, err
+		}
+		geometry.Type = PointGeometry
+		// 空のPointは全座標がNaNで表現される
+		if point != nil {
+			geometry.Points = []*object.Point{point}
+		}
+
+	case wkbLineString:
+		line, err := r.readPoints(order, dimension)
+		if err != nil {
+			return nil, err
+		}
+		geometry.Type = LineStringGeometry
+		geometry.Lines = [][]*object.Point{line}
+
+	case wkbPolygon:
+		rings, err := r.readRings(order, dimension)
+		if err != nil {
+			return nil, err
+		}
+		geometry.Type = PolygonGeometry
+		geometry.Polygons = [][][]*object.Point{rings}
+
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon, wkbPolyhedralSurface:
+		count, err := r.readUint32(order)
+		if err != nil {
+			return nil, err
+		}
+		geometry.Type = map[uint32]GeometryType{
+			wkbMultiPoint:        PointGeometry,
+			wkbMultiLineString:   LineStringGeometry,
+			wkbMultiPolygon:      PolygonGeometry,
+			wkbPolyhedralSurface: PolyhedralSurfaceGeometry,
+		}[code]
+
+		// 要素は種別コードを持つジオメトリとして格納されている
+		for i := uint32(0); i < count; i++ {
+			element, err := r.readGeometry()
+			if err != nil {
+				return nil, err
+			}
+			if element.Type != geometry.Type && !(code == wkbPolyhedralSurface && element.Type == PolygonGeometry) {
+				return nil, fmt.Errorf("unexpected element type %v in geometry type %v", element.Type, code)
+			}
+			geometry.Points = append(geometry.Points, element.Points...)
+			geometry.Lines = append(geometry.Lines, element.Lines...)
+			geometry.Polygons = append(geometry.Polygons, element.Polygons...)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %v", code)
+	}
+
+	return geometry, nil
+}
+
+// readRings 環のリストの読み込み
+//
+// 引数：
+//
+//	order    ：バイト順
+//	dimension：次元指定
+//
+// 戻り値：
+//
+//	地理座標のスライスのスライス
+//
+// 戻り値(エラー)：
+//
+//	バイト列が不足している場合エラーを返却する。
+func (r *wkbReader) readRings(order binary.ByteOrder, dimension string) ([][]*object.Point, error) {
+	count, err := r.readUint32(order)
+	if err != nil {
+		return nil, err
+	}
+
+	rings := [][]*object.Point{}
+	for i := uint32(0); i < count; i++ {
+		ring, err := r.readPoints(order, dimension)
+		if err != nil {
+			return nil, err
+		}
+		rings = append(rings, ring)
+	}
+
+	return rings, nil
+}
+
+// readPoints 座標リストの読み込み
+//
+// 引数：
+//
+//	order    ：バイト順
+//	dimension：次元指定
+//
+// 戻り値：
+//
+//	地理座標のスライス
+//
+// 戻り値(エラー)：
+//
+//	バイト列が不足している場合エラーを返却する。
+func (r *wkbReader) readPoints(order binary.ByteOrder, dimension string) ([]*object.Point, error) {
+	count, err := r.readUint32(order)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []*object.Point{}
+	for i := uint32(0); i < count; i++ {
+		point, err := r.readPoint(order, dimension)
+		if err != nil {
+			return nil, err
+		}
+		if point == nil {
+			return nil, fmt.Errorf("empty position in coordinate list")
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// readPoint 座標の読み込み
+//
+// 引数：
+//
+//	order    ：バイト順
+//	dimension：次元指定
+//
+// 戻り値：
+//
+//	地理座標。全ての座標値がNaNの場合(空のPoint)はnil。
+//
+// 戻り値(エラー)：
+//
+//	バイト列が不足している場合、経度緯度が範囲外の場合エラーを返却する。
+func (r *wkbReader) readPoint(order binary.ByteOrder, dimension string) (*object.Point, error) {
+	count := map[string]int{"": 2, "Z": 3, "M": 3, "ZM": 4}[dimension]
+
+	values := make([]float64, count)
+	isEmpty := true
+	for i := range values {
+		bits, err := r.readUint64(order)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = math.Float64frombits(bits)
+		isEmpty = isEmpty && math.IsNaN(values[i])
+	}
+	if isEmpty {
+		return nil, nil
+	}
+
+	return newPointFromOrdinates(values, dimension)
+}
+
+// readUint32 4バイト符号なし整数の読み込み
+//
+// 引数：
+//
+//	order：バイト順
+//
+// 戻り値：
+//
+//	読み込んだ値
+//
+// 戻り値(エラー)：
+//
+//	バイト列が不足している場合エラーを返却する。
+func (r *wkbReader) readUint32(order binary.ByteOrder) (uint32, error) {
+	if r.pos+4 > len(r.data) {
+		return 0, fmt.Errorf("unexpected end of WKB")
+	}
+	value := order.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return value, nil
+}
+
+// readUint64 8バイト符号なし整数の読み込み
+//
+// 引数：
+//
+//	order：バイト順
+//
+// 戻り値：
+//
+//	読み込んだ値
+//
+// 戻り値(エラー)：
+//
+//	バイト列が不足している場合エラーを返却する。
+func (r *wkbReader) readUint64(order binary.ByteOrder) (uint64, error) {
+	if r.pos+8 > len(r.data) {
+		return 0, fmt.Errorf("unexpected end of WKB")
+	}
+	value := order.Uint64(r.data[r.pos:])
+	r.pos += 8
+	return value, nil
+}
@@ -0,0 +1,105 @@
+package shape
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestParseWKB01 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：ISO形式のPOINT Z(ビッグエンディアン)
+//   - パターン2：EWKB形式のSRID付きPOINT Z(リトルエンディアン)
+//   - パターン3：MULTIPOLYGON(要素数1, 頂点数4)
+//
+// + 確認内容
+//   - 入力通りのジオメトリが取得できること
+func TestParseWKB01(t *testing.T) {
+	// ISO POINT Z (139.5 35.5 10)
+	iso := []byte{0}
+	iso = binary.BigEndian.AppendUint32(iso, 1001)
+	for _, v := range []float64{139.5, 35.5, 10} {
+		iso = binary.BigEndian.AppendUint64(iso, math.Float64bits(v))
+	}
+
+	// EWKB SRID=4326;POINT Z (139.5 35.5 10)
+	ewkb, _ := hex.DecodeString("01010000A0E610000000000000007061400000000000C041400000000000002440")
+
+	for _, wkb := range [][]byte{iso, ewkb} {
+		resultVal, resultErr := ParseWKB(wkb)
+		if resultErr != nil {
+			t.Errorf("error - 期待値：nil, 取得値：%s", resultErr)
+			continue
+		}
+		if resultVal.Type != PointGeometry || len(resultVal.Points) != 1 {
+			t.Errorf("ジオメトリ - 期待値：1点, 取得値：%v", resultVal)
+			continue
+		}
+		point := resultVal.Points[0]
+		resultPoint := []float64{point.Lon(), point.Lat(), point.Alt()}
+		expectPoint := []float64{139.5, 35.5, 10}
+		if !reflect.DeepEqual(resultPoint, expectPoint) {
+			t.Errorf("座標 - 期待値：%v, 取得値：%v", expectPoint, resultPoint)
+		}
+	}
+
+	// MULTIPOLYGON (((0 0, 10 0, 0 10, 0 0)))
+	multi := []byte{1}
+	multi = binary.LittleEndian.AppendUint32(multi, 6)
+	multi = binary.LittleEndian.AppendUint32(multi, 1)
+	multi = append(multi, 1)
+	multi = binary.LittleEndian.AppendUint32(multi, 3)
+	multi = binary.LittleEndian.AppendUint32(multi, 1)
+	multi = binary.LittleEndian.AppendUint32(multi, 4)
+	for _, v := range []float64{0, 0, 10, 0, 0, 10, 0, 0} {
+		multi = binary.LittleEndian.AppendUint64(multi, math.Float64bits(v))
+	}
+
+	resultVal, resultErr := ParseWKB(multi)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+	if resultVal.Type != PolygonGeometry || len(resultVal.Polygons) != 1 || len(resultVal.Polygons[0][0]) != 4 {
+		t.Errorf("ジオメトリ - 期待値：頂点数4の多角形1つ, 取得値：%v", resultVal)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestParseWKB02 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：バイト順が不正
+//   - パターン2：バイト列が不足
+//   - パターン3：未対応のジオメトリ(GeometryCollection)
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestParseWKB02(t *testing.T) {
+	testCases := []struct {
+		wkb    string
+		expect string
+	}{
+		{"0201000000", "InputValueError,入力チェックエラー,invalid byte order 2"},
+		{"010100000000000000", "InputValueError,入力チェックエラー,unexpected end of WKB"},
+		{"010700000000000000", "InputValueError,入力チェックエラー,unsupported geometry type 7"},
+	}
+
+	for _, testCase := range testCases {
+		wkb, _ := hex.DecodeString(testCase.wkb)
+		resultVal, resultErr := ParseWKB(wkb)
+		if resultVal != nil {
+			t.Errorf("ジオメトリ - 期待値：nil, 取得値：%v", resultVal)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
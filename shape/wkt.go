@@ -0,0 +1,517 @@
+package shape
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/trajectoryjp/spatial_id_go/v4/common/enum"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/errors"
+	"github.com/trajectoryjp/spatial_id_go/v4/common/object"
+)
+
+// wktParser WKT読み込み用の構造体
+type wktParser struct {
+	tokens []string // 字句のスライス
+	pos    int      // 読み込み位置
+}
+
+// ParseWKT WKT読み込み関数
+//
+// WKT(Well-Known Text)を読み込み、ジオメトリに変換する。
+// 座標は[経度 緯度]、[経度 緯度 高さ]の順とし、EPSG:4326の地理座標として扱う。
+//
+// 対応するジオメトリは以下の通り。
+// Z、M、ZMの次元指定、PostGISのEWKTにおける"SRID=4326;"の接頭辞に対応する。Mの値は参照しない。
+//
+//	POINT, MULTIPOINT          ：PointGeometry
+//	LINESTRING, MULTILINESTRING：LineStringGeometry
+//	POLYGON, MULTIPOLYGON      ：PolygonGeometry
+//	POLYHEDRALSURFACE          ：PolyhedralSurfaceGeometry
+//
+// 引数：
+//
+//	wkt：WKT文字列
+//
+// 戻り値：
+//
+//	ジオメトリ
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 WKT不正       ：WKTの構文に違反している場合、未対応のジオメトリの場合。
+//	 座標の範囲超過：経度、緯度が object.NewPoint の入力範囲外の場合。
+func ParseWKT(wkt string) (*Geometry, error) {
+	// SRIDの接頭辞を除去
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(wkt)), "SRID=") {
+		_, body, found := strings.Cut(wkt, ";")
+		if !found {
+			return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, "invalid SRID prefix")
+		}
+		wkt = body
+	}
+
+	p := &wktParser{tokens: tokenizeWKT(wkt)}
+
+	geometry, err := p.parseGeometry()
+	if err != nil {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, err.Error())
+	}
+	if p.pos != len(p.tokens) {
+		return nil, errors.NewSpatialIdError(errors.InputValueErrorCode, fmt.Sprintf("unexpected token %q", p.tokens[p.pos]))
+	}
+
+	return geometry, nil
+}
+
+// GetWKTOnExtendedSpatialId 拡張空間IDのWKT取得関数
+//
+// 拡張空間IDのボクセルを、6面で構成されるPOLYHEDRALSURFACE ZのWKTで取得する。
+// 各面は外側から見て反時計回りの頂点順とする。
+// 取得したWKTを ParseWKT で読み込み、同じ精度で GetExtendedSpatialIdsOnGeometry に入力すると元の拡張空間IDが得られる。
+//
+// 引数：
+//
+//	extendedSpatialId：拡張空間ID
+//
+// 戻り値：
+//
+//	WKT文字列
+//
+// 戻り値(エラー)：
+//
+//	以下の条件に当てはまる場合、エラーインスタンスが返却される。
+//	 精度閾値超過              ：水平方向精度、または垂直方向精度に 0 ～ 35 の整数値以外が入力されていた場合。
+//	 拡張空間IDフォーマット不正：拡張空間IDのフォーマットに違反する値が"拡張空間ID"に入力されていた場合。
+func GetWKTOnExtendedSpatialId(extendedSpatialId string) (string, error) {
+	vertexes, err := GetPointOnExtendedSpatialId(extendedSpatialId, enum.Vertex)
+	if err != nil {
+		return "", err
+	}
+
+	// 頂点の並び順は北西、北東、南東、南西の順に底面4頂点→上面4頂点
+	faces := [][4]int{
+		{0, 1, 2, 3}, // 底面
+		{4, 7, 6, 5}, // 上面
+		{0, 4, 5, 1}, // 北面
+		{1, 5, 6, 2}, // 東面
+		{2, 6, 7, 3}, // 南面
+		{3, 7, 4, 0}, // 西面
+	}
+
+	faceTexts := make([]string, 0, len(faces))
+	for _, face := range faces {
+		positions := make([]string, 0, len(face)+1)
+		for _, index := range append(face[:], face[0]) {
+			positions = append(positions, formatWKTPosition(vertexes[index]))
+		}
+		faceTexts = append(faceTexts, "(("+strings.Join(positions, ",")+"))")
+	}
+
+	return "POLYHEDRALSURFACE Z (" + strings.Join(faceTexts, ",") + ")", nil
+}
+
+// formatWKTPosition WKT座標文字列化関数
+//
+// 引数：
+//
+//	p：地理座標
+//
+// 戻り値：
+//
+//	"経度 緯度 高さ"形式の文字列
+func formatWKTPosition(p *object.Point) string {
+	return strings.Join([]string{
+		strconv.FormatFloat(p.Lon(), 'f', -1, 64),
+		strconv.FormatFloat(p.Lat(), 'f', -1, 64),
+		strconv.FormatFloat(p.Alt(), 'f', -1, 64),
+	}, " ")
+}
+
+// tokenizeWKT WKT字句分割関数
+//
+// WKTを括弧、カンマ、空白区切りの字句に分割する。英字は大文字に変換する。
+//
+// 引数：
+//
+//	wkt：WKT文字列
+//
+// 戻り値：
+//
+//	字句のスライス
+func tokenizeWKT(wkt string) []string {
+	tokens := []string{}
+	current := strings.Builder{}
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, strings.ToUpper(current.String()))
+			current.Reset()
+		}
+	}
+
+	for _, r := range wkt {
+		switch r {
+		case '(', ')', ',':
+			flush()
+			tokens = append(tokens, string(r))
+		case ' ', '\t', '\n', '\r':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// peek 次の字句の取得
+//
+// 戻り値：
+//
+//	次の字句。末尾に達している場合は空文字。
+func (p *wktParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// expect 字句の読み込み
+//
+// 次の字句が指定された字句であれば読み込み位置を進める。
+//
+// 引数：
+//
+//	token：期待する字句
+//
+// 戻り値(エラー)：
+//
+//	次の字句が指定された字句でない場合エラーを返却する。
+func (p *wktParser) expect(token string) error {
+	if p.peek() != token {
+		return fmt.Errorf("expected %q but got %q", token, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+// parseGeometry ジオメトリの読み込み
+//
+// 戻り値：
+//
+//	ジオメトリ
+//
+// 戻り値(エラー)：
+//
+//	構文に違反している場合、未対応のジオメトリの場合エラーを返却する。
+func (p *wktParser) parseGeometry() (*Geometry, error) {
+	tag := p.peek()
+	p.pos++
+
+	// 次元指定の読み込み
+	dimension := ""
+	switch p.peek() {
+	case "Z", "M", "ZM":
+		dimension = p.peek()
+		p.pos++
+	}
+
+	geometry := &Geometry{}
+
+	// 空のジオメトリ
+	if p.peek() == "EMPTY" {
+		p.pos++
+		switch tag {
+		case "POINT", "MULTIPOINT":
+			geometry.Type = PointGeometry
+		case "LINESTRING", "MULTILINESTRING":
+			geometry.Type = LineStringGeometry
+		case "POLYGON", "MULTIPOLYGON":
+			geometry.Type = PolygonGeometry
+		case "POLYHEDRALSURFACE":
+			geometry.Type = PolyhedralSurfaceGeometry
+		default:
+			return nil, fmt.Errorf("unsupported geometry type %q", tag)
+		}
+		return geometry, nil
+	}
+
+	switch tag {
+	case "POINT":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		point, err := p.parsePosition(dimension)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		geometry.Type = PointGeometry
+		geometry.Points = []*object.Point{point}
+
+	case "MULTIPOINT":
+		points, err := p.parseMultiPoint(dimension)
+		if err != nil {
+			return nil, err
+		}
+		geometry.Type = PointGeometry
+		geometry.Points = points
+
+	case "LINESTRING":
+		line, err := p.parsePositions(dimension)
+		if err != nil {
+			return nil, err
+		}
+		geometry.Type = LineStringGeometry
+		geometry.Lines = [][]*object.Point{line}
+
+	case "MULTILINESTRING":
+		lines, err := p.parseRings(dimension)
+		if err != nil {
+			return nil, err
+		}
+		geometry.Type = LineStringGeometry
+		geometry.Lines = lines
+
+	case "POLYGON":
+		rings, err := p.parseRings(dimension)
+		if err != nil {
+			return nil, err
+		}
+		geometry.Type = PolygonGeometry
+		geometry.Polygons = [][][]*object.Point{rings}
+
+	case "MULTIPOLYGON", "POLYHEDRALSURFACE":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		for {
+			rings, err := p.parseRings(dimension)
+			if err != nil {
+				return nil, err
+			}
+			geometry.Polygons = append(geometry.Polygons, rings)
+			if p.peek() != "," {
+				break
+			}
+			p.pos++
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		geometry.Type = PolygonGeometry
+		if tag == "POLYHEDRALSURFACE" {
+			geometry.Type = PolyhedralSurfaceGeometry
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", tag)
+	}
+
+	return geometry, nil
+}
+
+// parseMultiPoint MULTIPOINTの座標の読み込み
+//
+// "(x y, x y)"形式と"((x y), (x y))"形式の両方に対応する。
+//
+// 引数：
+//
+//	dimension：次元指定
+//
+// 戻り値：
+//
+//	地理座標のスライス
+//
+// 戻り値(エラー)：
+//
+//	構文に違反している場合エラーを返却する。
+func (p *wktParser) parseMultiPoint(dimension string) ([]*object.Point, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	points := []*object.Point{}
+	for {
+		enclosed := p.peek() == "("
+		if enclosed {
+			p.pos++
+		}
+		point, err := p.parsePosition(dimension)
+		if err != nil {
+			return nil, err
+		}
+		if enclosed {
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+		points = append(points, point)
+
+		if p.peek() != "," {
+			break
+		}
+		p.pos++
+	}
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// parseRings 座標リストのリストの読み込み
+//
+// "((x y, x y), (x y, x y))"形式の座標を読み込む。
+//
+// 引数：
+//
+//	dimension：次元指定
+//
+// 戻り値：
+//
+//	地理座標のスライスのスライス
+//
+// 戻り値(エラー)：
+//
+//	構文に違反している場合エラーを返却する。
+func (p *wktParser) parseRings(dimension string) ([][]*object.Point, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	rings := [][]*object.Point{}
+	for {
+		ring, err := p.parsePositions(dimension)
+		if err != nil {
+			return nil, err
+		}
+		rings = append(rings, ring)
+
+		if p.peek() != "," {
+			break
+		}
+		p.pos++
+	}
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	return rings, nil
+}
+
+// parsePositions 座標リストの読み込み
+//
+// "(x y, x y)"形式の座標を読み込む。
+//
+// 引数：
+//
+//	dimension：次元指定
+//
+// 戻り値：
+//
+//	地理座標のスライス
+//
+// 戻り値(エラー)：
+//
+//	構文に違反している場合エラーを返却する。
+func (p *wktParser) parsePositions(dimension string) ([]*object.Point, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	points := []*object.Point{}
+	for {
+		point, err := p.parsePosition(dimension)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+
+		if p.peek() != "," {
+			break
+		}
+		p.pos++
+	}
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// parsePosition 座標の読み込み
+//
+// 空白区切りの数値を読み込み、地理座標に変換する。
+// 次元指定が無い場合は数値の個数から次元を判定する(2:XY, 3:XYZ, 4:XYZM)。
+//
+// 引数：
+//
+//	dimension：次元指定
+//
+// 戻り値：
+//
+//	地理座標
+//
+// 戻り値(エラー)：
+//
+//	数値の個数が次元と一致しない場合、経度緯度が範囲外の場合エラーを返却する。
+func (p *wktParser) parsePosition(dimension string) (*object.Point, error) {
+	values := []float64{}
+	for p.peek() != "," && p.peek() != ")" && p.peek() != "" {
+		value, err := strconv.ParseFloat(p.peek(), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p.peek())
+		}
+		values = append(values, value)
+		p.pos++
+	}
+
+	return newPointFromOrdinates(values, dimension)
+}
+
+// newPointFromOrdinates 座標値の地理座標変換関数
+//
+// 次元指定に従い、座標値を地理座標に変換する。Mの値は参照しない。
+//
+// 引数：
+//
+//	values   ：座標値
+//	dimension：次元指定("", "Z", "M", "ZM")。空文字の場合は座標値の個数から判定する。
+//
+// 戻り値：
+//
+//	地理座標
+//
+// 戻り値(エラー)：
+//
+//	座標値の個数が次元と一致しない場合、経度緯度が範囲外の場合エラーを返却する。
+func newPointFromOrdinates(values []float64, dimension string) (*object.Point, error) {
+	expected := map[string]int{"Z": 3, "M": 3, "ZM": 4}[dimension]
+	if dimension == "" && len(values) >= 2 && len(values) <= 4 {
+		expected = len(values)
+	}
+	if len(values) < 2 || len(values) != expected {
+		return nil, fmt.Errorf("invalid number of ordinates %v", values)
+	}
+
+	alt := 0.0
+	if dimension != "M" && len(values) >= 3 {
+		alt = values[2]
+	}
+
+	point, err := object.NewPoint(values[0], values[1], alt)
+	if err != nil {
+		return nil, fmt.Errorf("position %v is out of range", values)
+	}
+
+	return point, nil
+}
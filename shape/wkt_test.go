@@ -0,0 +1,174 @@
+package shape
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseWKT01 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：POINT Z
+//   - パターン2：EWKTのMULTIPOINT(括弧あり)
+//   - パターン3：LINESTRING M
+//   - パターン4：穴あきPOLYGON
+//   - パターン5：MULTIPOLYGON EMPTY
+//
+// + 確認内容
+//   - ジオメトリの種別と頂点数が入力通りに取得できること
+//   - Mの値が高さとして扱われないこと
+func TestParseWKT01(t *testing.T) {
+	testCases := []struct {
+		wkt        string
+		expectType GeometryType
+		expectLen  []int
+		expectAlt  float64
+	}{
+		{"POINT Z (139.753098 35.685371 10)", PointGeometry, []int{1}, 10},
+		{"SRID=4326;MULTIPOINT ((139 35), (140 36))", PointGeometry, []int{2}, 0},
+		{"linestring m (139 35 5, 140 36 6)", LineStringGeometry, []int{2}, 0},
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 4 2, 4 4, 2 2))", PolygonGeometry, []int{5, 4}, 0},
+		{"MULTIPOLYGON EMPTY", PolygonGeometry, []int{}, 0},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := ParseWKT(testCase.wkt)
+		if resultErr != nil {
+			t.Errorf("error - 期待値：nil, 取得値：%s", resultErr)
+			continue
+		}
+		if resultVal.Type != testCase.expectType {
+			t.Errorf("種別 - 期待値：%v, 取得値：%v", testCase.expectType, resultVal.Type)
+		}
+
+		resultLen := []int{}
+		switch resultVal.Type {
+		case PointGeometry:
+			if len(resultVal.Points) > 0 {
+				resultLen = append(resultLen, len(resultVal.Points))
+				if resultVal.Points[0].Alt() != testCase.expectAlt {
+					t.Errorf("高さ - 期待値：%v, 取得値：%v", testCase.expectAlt, resultVal.Points[0].Alt())
+				}
+			}
+		case LineStringGeometry:
+			for _, line := range resultVal.Lines {
+				resultLen = append(resultLen, len(line))
+				if line[0].Alt() != testCase.expectAlt {
+					t.Errorf("高さ - 期待値：%v, 取得値：%v", testCase.expectAlt, line[0].Alt())
+				}
+			}
+		case PolygonGeometry:
+			for _, polygon := range resultVal.Polygons {
+				for _, ring := range polygon {
+					resultLen = append(resultLen, len(ring))
+				}
+			}
+		}
+		if !reflect.DeepEqual(resultLen, testCase.expectLen) {
+			t.Errorf("頂点数 - 期待値：%v, 取得値：%v", testCase.expectLen, resultLen)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestParseWKT02 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：未対応のジオメトリ
+//   - パターン2：括弧の不足
+//   - パターン3：座標値の個数が次元指定と不一致
+//   - パターン4：緯度が範囲外
+//   - パターン5：座標値の無い座標
+//   - パターン6：末尾の座標の座標値の不足
+//   - パターン7：座標値が1個の座標
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestParseWKT02(t *testing.T) {
+	testCases := []struct {
+		wkt    string
+		expect string
+	}{
+		{"CIRCULARSTRING (0 0, 1 1, 2 0)", `InputValueError,入力チェックエラー,unsupported geometry type "CIRCULARSTRING"`},
+		{"POINT (0 0", `InputValueError,入力チェックエラー,expected ")" but got ""`},
+		{"POINT Z (0 0)", "InputValueError,入力チェックエラー,invalid number of ordinates [0 0]"},
+		{"POINT (0 89)", "InputValueError,入力チェックエラー,position [0 89] is out of range"},
+		{"POINT ()", "InputValueError,入力チェックエラー,invalid number of ordinates []"},
+		{"LINESTRING (1 2, )", "InputValueError,入力チェックエラー,invalid number of ordinates []"},
+		{"POLYGON ((0 0, 1 0, 1))", "InputValueError,入力チェックエラー,invalid number of ordinates [1]"},
+	}
+
+	for _, testCase := range testCases {
+		resultVal, resultErr := ParseWKT(testCase.wkt)
+		if resultVal != nil {
+			t.Errorf("ジオメトリ - 期待値：nil, 取得値：%v", resultVal)
+		}
+		if resultErr == nil || resultErr.Error() != testCase.expect {
+			t.Errorf("error - 期待値：%s, 取得値：%v", testCase.expect, resultErr)
+		}
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetWKTOnExtendedSpatialId01 正常系動作確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：拡張空間ID 10/100/200/25/3
+//
+// + 確認内容
+//   - 6面のPOLYHEDRALSURFACE Zが取得できること
+//   - 取得したWKTを読み込み拡張空間IDに変換すると、元の拡張空間IDが得られること
+func TestGetWKTOnExtendedSpatialId01(t *testing.T) {
+	id := "10/100/200/25/3"
+
+	resultVal, resultErr := GetWKTOnExtendedSpatialId(id)
+	if resultErr != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", resultErr)
+	}
+
+	geometry, err := ParseWKT(resultVal)
+	if err != nil {
+		t.Fatalf("error - 期待値：nil, 取得値：%s", err)
+	}
+	if geometry.Type != PolyhedralSurfaceGeometry || len(geometry.Polygons) != 6 {
+		t.Errorf("ジオメトリ - 期待値：6面の多面体, 取得値：%v", resultVal)
+	}
+
+	ids, err := GetExtendedSpatialIdsOnGeometry(geometry, 10, 25)
+	expectVal := []string{id}
+	if !reflect.DeepEqual(ids, expectVal) {
+		t.Errorf("拡張空間ID - 期待値：%v, 取得値：%v", expectVal, ids)
+	}
+	if err != nil {
+		t.Errorf("error - 期待値：nil, 取得値：%s", err)
+	}
+
+	t.Log("テスト終了")
+}
+
+// TestGetWKTOnExtendedSpatialId02 入力チェックエラー確認
+//
+// 試験詳細：
+// + 試験データ
+//   - パターン1：拡張空間ID 10/100/200/25
+//
+// + 確認内容
+//   - エラーインスタンス（InputValueErrorCode）が返却されること
+func TestGetWKTOnExtendedSpatialId02(t *testing.T) {
+	resultVal, resultErr := GetWKTOnExtendedSpatialId("10/100/200/25")
+
+	expectErr := "InputValueError,入力チェックエラー"
+	if resultVal != "" {
+		t.Errorf("WKT - 期待値：\"\", 取得値：%v", resultVal)
+	}
+	if resultErr == nil || resultErr.Error() != expectErr {
+		t.Errorf("error - 期待値：%s, 取得値：%v", expectErr, resultErr)
+	}
+
+	t.Log("テスト終了")
+}